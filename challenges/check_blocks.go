@@ -0,0 +1,266 @@
+package challenges
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	registerCheckBlockChallenges()
+}
+
+func registerCheckBlockChallenges() {
+	// Check Block Basics
+	Challenges["check_basics"] = &Challenge{
+		ID:          "check_basics",
+		Name:        "Check Block Basics",
+		Description: "Write a check block with an assert that reports a descriptive warning instead of failing the run",
+		Points:      100,
+		Flag:        "flag{ch3ck_bl0ck_b4s1cs}",
+		Difficulty:  "beginner",
+		Category:    "checks",
+		Validator:   NewStructuredValidator(validateCheckBasicsStructure, ProofKindCheck),
+	}
+
+	// Scoped Data Source
+	Challenges["scoped_data_check"] = &Challenge{
+		ID:          "scoped_data_check",
+		Name:        "Scoped Data Sentinel",
+		Description: "Query live infrastructure from a scoped data source inside a check block and assert on its result",
+		Points:      175,
+		Flag:        "flag{sc0p3d_d4t4_s3nt1n3l}",
+		Difficulty:  "intermediate",
+		Category:    "checks",
+		Validator:   NewStructuredValidator(validateScopedDataCheckStructure, ProofKindCheck),
+	}
+
+	// Assertion Quality
+	Challenges["assertion_crafter"] = &Challenge{
+		ID:          "assertion_crafter",
+		Name:        "Assertion Crafter",
+		Description: "Write a check block with at least 3 assertions whose error messages explain what failed and show the actual value",
+		Points:      175,
+		Flag:        "flag{4ss3rt10n_cr4ft3r_pr0}",
+		Difficulty:  "intermediate",
+		Category:    "checks",
+		Validator:   NewStructuredValidator(validateAssertionCrafterStructure, ProofKindCheck),
+	}
+
+	// Continuous Validation
+	Challenges["continuous_validation"] = &Challenge{
+		ID:          "continuous_validation",
+		Name:        "Continuous Validation Architect",
+		Description: "Monitor your infrastructure with at least 2 check blocks, each using its own scoped data source and asserting on it",
+		Points:      250,
+		Flag:        "flag{c0nt1nu0us_v4l1d4t10n_4rch1t3ct}",
+		Difficulty:  "advanced",
+		Category:    "checks",
+		Validator:   NewStructuredValidator(validateContinuousValidationStructure, ProofKindCheck),
+	}
+}
+
+func validateCheckBasicsStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	check := proof.CheckBlocks[0]
+	if check.Name == "" {
+		result.fail("check.1.name", "Check block has no name", "Example: check \"health\" { ... }")
+		return result
+	}
+	result.pass("check.1.name", fmt.Sprintf("Found check block '%s'", check.Name))
+
+	if !checkAssertions(&result, "check.1", check, 1, 10) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{ch3ck_bl0ck_b4s1cs}"
+	result.Message = "✓ Check block basics completed! Failed assertions now surface as warnings without blocking your apply."
+	return result
+}
+
+func validateScopedDataCheckStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	var check *CheckBlockProof
+	for i := range proof.CheckBlocks {
+		if proof.CheckBlocks[i].ScopedDataSource != nil {
+			check = &proof.CheckBlocks[i]
+			break
+		}
+	}
+
+	if check == nil {
+		result.fail("check.scoped_data", "No check block declares a scoped data source",
+			"Declare a data block inside the check block: check \"health\" { data \"http\" \"site\" { ... } assert { ... } }")
+		return result
+	}
+	result.pass("check.scoped_data", fmt.Sprintf("Check '%s' declares scoped data source '%s'", check.Name, scopedDataAddress(check)))
+
+	if !checkAssertions(&result, "check", *check, 1, 10) {
+		return result
+	}
+
+	if !checkUsesScopedData(&result, "check", *check) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{sc0p3d_d4t4_s3nt1n3l}"
+	result.Message = "✓ Scoped data mastered! Your check queries live infrastructure on every plan and apply."
+	return result
+}
+
+func validateAssertionCrafterStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	// Use the check block with the most assertions
+	check := proof.CheckBlocks[0]
+	for _, c := range proof.CheckBlocks[1:] {
+		if len(c.Assertions) > len(check.Assertions) {
+			check = c
+		}
+	}
+
+	if !checkAssertions(&result, "check", check, 3, 20) {
+		return result
+	}
+
+	seen := make(map[string]bool)
+	hasInterpolation := false
+	for i, assertion := range check.Assertions {
+		msg := strings.TrimSpace(assertion.ErrorMessage)
+		if seen[msg] {
+			result.fail(fmt.Sprintf("check.assert.%d.unique", i+1), fmt.Sprintf("Assertion %d repeats the error message of an earlier assertion", i+1),
+				"Each assertion should explain its own failure")
+			return result
+		}
+		seen[msg] = true
+
+		if strings.Contains(msg, "${") && strings.Contains(msg, "}") {
+			hasInterpolation = true
+		}
+	}
+	result.pass("check.messages.unique", "Every assertion has its own error message")
+
+	if !hasInterpolation {
+		result.fail("check.messages.interpolation", "Assertion error messages should show the actual value that failed",
+			"Example: \"Expected status 200, got ${data.http.site.status_code}\"")
+		return result
+	}
+	result.pass("check.messages.interpolation", "Error messages interpolate actual values")
+
+	result.Success = true
+	result.Flag = "flag{4ss3rt10n_cr4ft3r_pr0}"
+	result.Message = "✓ Assertion crafting mastered! Your warnings tell operators exactly what drifted."
+	return result
+}
+
+func validateContinuousValidationStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if len(proof.CheckBlocks) < 2 {
+		result.failCount("checks.count", fmt.Sprintf("Need at least 2 check blocks (found %d)", len(proof.CheckBlocks)),
+			">= 2", len(proof.CheckBlocks), "Monitor separate concerns in separate check blocks")
+		return result
+	}
+	result.pass("checks.count", fmt.Sprintf("Found %d check blocks", len(proof.CheckBlocks)))
+
+	names := make(map[string]bool)
+	for i, check := range proof.CheckBlocks {
+		prefix := fmt.Sprintf("check.%d", i+1)
+
+		if check.Name == "" || names[check.Name] {
+			result.fail(prefix+".name", fmt.Sprintf("Check block %d needs a unique name", i+1), "Each check block must have a distinct name")
+			return result
+		}
+		names[check.Name] = true
+
+		if check.ScopedDataSource == nil {
+			result.fail(prefix+".scoped_data", fmt.Sprintf("Check '%s' does not declare a scoped data source", check.Name),
+				"Each check should query the infrastructure it monitors with its own scoped data block")
+			return result
+		}
+		result.pass(prefix+".scoped_data", fmt.Sprintf("Check '%s' declares scoped data source '%s'", check.Name, scopedDataAddress(&check)))
+
+		if !checkAssertions(&result, prefix, check, 1, 20) {
+			return result
+		}
+
+		if !checkUsesScopedData(&result, prefix, check) {
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Flag = "flag{c0nt1nu0us_v4l1d4t10n_4rch1t3ct}"
+	result.Message = "✓ Continuous validation architected! Every plan and apply now reports on the health of your infrastructure."
+	return result
+}
+
+// checkAssertions verifies that a check block has at least minCount assertions,
+// each with a condition and an error message of at least minMessage characters.
+func checkAssertions(result *ValidationResult, prefix string, check CheckBlockProof, minCount, minMessage int) bool {
+	if len(check.Assertions) < minCount {
+		result.failCount(prefix+".assertions", fmt.Sprintf("Check '%s' needs at least %d assert block(s) (found %d)", check.Name, minCount, len(check.Assertions)),
+			fmt.Sprintf(">= %d", minCount), len(check.Assertions), "Example: assert { condition = ..., error_message = ... }")
+		return false
+	}
+	result.pass(prefix+".assertions", fmt.Sprintf("Check '%s' has %d assertion(s)", check.Name, len(check.Assertions)))
+
+	for i, assertion := range check.Assertions {
+		id := fmt.Sprintf("%s.assert.%d", prefix, i+1)
+
+		if strings.TrimSpace(assertion.Condition) == "" {
+			result.fail(id+".condition", fmt.Sprintf("Assertion %d of check '%s' has empty condition", i+1, check.Name),
+				"Condition expression is required")
+			return false
+		}
+
+		if !validateErrorMessage(assertion.ErrorMessage, minMessage) {
+			result.failCount(id+".error_message", fmt.Sprintf("Assertion %d of check '%s' has inadequate error message", i+1, check.Name),
+				fmt.Sprintf(">= %d chars", minMessage), len(strings.TrimSpace(assertion.ErrorMessage)),
+				fmt.Sprintf("Error message must be at least %d characters and describe what failed", minMessage))
+			return false
+		}
+		result.pass(id+".error_message", fmt.Sprintf("Assertion %d has descriptive error message (%d chars)", i+1, len(assertion.ErrorMessage)))
+	}
+	return true
+}
+
+// checkUsesScopedData verifies that an assertion references the check block's
+// scoped data source.
+func checkUsesScopedData(result *ValidationResult, prefix string, check CheckBlockProof) bool {
+	address := scopedDataAddress(&check)
+	for _, assertion := range check.Assertions {
+		if strings.Contains(assertion.Condition, address) {
+			result.pass(prefix+".uses_scoped_data", fmt.Sprintf("Assertions reference %s", address))
+			return true
+		}
+	}
+
+	result.fail(prefix+".uses_scoped_data", fmt.Sprintf("No assertion in check '%s' references its scoped data source", check.Name),
+		fmt.Sprintf("Assert on the result of the scoped data source, e.g. condition = %s.<attribute> == ...", address))
+	return false
+}
+
+// scopedDataAddress returns the data.<type>.<name> address of a check block's
+// scoped data source.
+func scopedDataAddress(check *CheckBlockProof) string {
+	if check.ScopedDataSource == nil {
+		return ""
+	}
+	return fmt.Sprintf("data.%s.%s", check.ScopedDataSource.DataSourceType, check.ScopedDataSource.DataSourceName)
+}
@@ -0,0 +1,187 @@
+package challenges
+
+import (
+	"testing"
+)
+
+// healthCheck is a check block asserting on a scoped http data source.
+func healthCheck(name string) CheckBlockProof {
+	return CheckBlockProof{
+		Name:             name,
+		ScopedDataSource: &DataSourceProof{DataSourceType: "http", DataSourceName: name},
+		Assertions: []ConditionBlock{
+			{
+				Condition:    "data.http." + name + ".status_code == 200",
+				ErrorMessage: "Expected status 200 from " + name + ", got ${data.http." + name + ".status_code}",
+			},
+		},
+	}
+}
+
+func TestCheckBlockValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		checks    func() []CheckBlockProof
+		failed    string
+	}{
+		"basics": {
+			challenge: "check_basics",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{{Name: "health", Assertions: []ConditionBlock{{Condition: "var.port > 0", ErrorMessage: "Port must be positive"}}}}
+			},
+		},
+		"basics without a name": {
+			challenge: "check_basics",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("health")
+				check.Name = ""
+				return []CheckBlockProof{check}
+			},
+			failed: "check.1.name",
+		},
+		"basics without assertions": {
+			challenge: "check_basics",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{{Name: "health"}}
+			},
+			failed: "check.1.assertions",
+		},
+		"basics with an empty condition": {
+			challenge: "check_basics",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("health")
+				check.Assertions[0].Condition = "  "
+				return []CheckBlockProof{check}
+			},
+			failed: "check.1.assert.1.condition",
+		},
+		"basics with a short message": {
+			challenge: "check_basics",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("health")
+				check.Assertions[0].ErrorMessage = "   failed   "
+				return []CheckBlockProof{check}
+			},
+			failed: "check.1.assert.1.error_message",
+		},
+		"scoped data": {
+			challenge: "scoped_data_check",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{{Name: "unscoped", Assertions: healthCheck("other").Assertions}, healthCheck("site")}
+			},
+		},
+		"scoped data missing": {
+			challenge: "scoped_data_check",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.ScopedDataSource = nil
+				return []CheckBlockProof{check}
+			},
+			failed: "check.scoped_data",
+		},
+		"scoped data not asserted on": {
+			challenge: "scoped_data_check",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.Assertions[0].Condition = "data.http.other.status_code == 200"
+				return []CheckBlockProof{check}
+			},
+			failed: "check.uses_scoped_data",
+		},
+		"assertions crafted": {
+			challenge: "assertion_crafter",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.Assertions = append(check.Assertions,
+					ConditionBlock{Condition: "length(data.http.site.response_body) > 0", ErrorMessage: "The site returned an empty response body"},
+					ConditionBlock{Condition: "data.http.site.response_headers[\"Content-Type\"] != \"\"", ErrorMessage: "The site response is missing its Content-Type header"},
+				)
+				return []CheckBlockProof{healthCheck("other"), check}
+			},
+		},
+		"too few assertions": {
+			challenge: "assertion_crafter",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.Assertions = append(check.Assertions, check.Assertions[0])
+				return []CheckBlockProof{check}
+			},
+			failed: "check.assertions",
+		},
+		"repeated messages": {
+			challenge: "assertion_crafter",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.Assertions = append(check.Assertions,
+					ConditionBlock{Condition: "length(data.http.site.response_body) > 0", ErrorMessage: "The site returned an empty response body"},
+					ConditionBlock{Condition: "data.http.site.response_body != \"\"", ErrorMessage: "  The site returned an empty response body "},
+				)
+				return []CheckBlockProof{check}
+			},
+			failed: "check.assert.3.unique",
+		},
+		"messages without values": {
+			challenge: "assertion_crafter",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("site")
+				check.Assertions = []ConditionBlock{
+					{Condition: "data.http.site.status_code == 200", ErrorMessage: "The site did not return status 200 OK"},
+					{Condition: "length(data.http.site.response_body) > 0", ErrorMessage: "The site returned an empty response body"},
+					{Condition: "data.http.site.response_body != \"\"", ErrorMessage: "The site response body must not be blank"},
+				}
+				return []CheckBlockProof{check}
+			},
+			failed: "check.messages.interpolation",
+		},
+		"continuous validation": {
+			challenge: "continuous_validation",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{healthCheck("site"), healthCheck("api")}
+			},
+		},
+		"one check block": {
+			challenge: "continuous_validation",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{healthCheck("site")}
+			},
+			failed: "checks.count",
+		},
+		"duplicate names": {
+			challenge: "continuous_validation",
+			checks: func() []CheckBlockProof {
+				return []CheckBlockProof{healthCheck("site"), healthCheck("site")}
+			},
+			failed: "check.2.name",
+		},
+		"second check unscoped": {
+			challenge: "continuous_validation",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("api")
+				check.ScopedDataSource = nil
+				return []CheckBlockProof{healthCheck("site"), check}
+			},
+			failed: "check.2.scoped_data",
+		},
+		"second check asserts on the first check's data": {
+			challenge: "continuous_validation",
+			checks: func() []CheckBlockProof {
+				check := healthCheck("api")
+				check.Assertions = healthCheck("site").Assertions
+				return []CheckBlockProof{healthCheck("site"), check}
+			},
+			failed: "check.2.uses_scoped_data",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &ProofData{CheckBlocks: tc.checks()}, tc.failed)
+		})
+	}
+}
+
+func TestCheckBlockValidatorsRequireCheckProof(t *testing.T) {
+	for _, id := range []string{"check_basics", "scoped_data_check", "assertion_crafter", "continuous_validation"} {
+		expectValidation(t, id, &ProofData{Manual: map[string]interface{}{"flag": Challenges[id].Flag}}, "proof.kind")
+	}
+}
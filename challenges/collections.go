@@ -0,0 +1,255 @@
+package challenges
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+func init() {
+	registerCollectionsChallenges()
+}
+
+func registerCollectionsChallenges() {
+	// Splat and zipmap
+	Challenges["zipmap_lookup"] = &Challenge{
+		ID:          "zipmap_lookup",
+		Name:        "Zip Line",
+		Description: "Build a map of server name to region from ctfchallenge_dataset using splat expressions and zipmap",
+		Points:      150,
+		Flag:        "flag{z1pm4p_spl4t_z1p_l1n3}",
+		Difficulty:  "beginner",
+		Category:    "collections",
+		Validator: NewStructuredValidator(validateDatasetTask(expectedNameToRegion,
+			"flag{z1pm4p_spl4t_z1p_l1n3}", "✓ Zipped! Two splats and a zipmap made a lookup table.",
+			"zipmap(servers[*].name, servers[*].region)"), ProofKindEncoded),
+	}
+
+	// Filtering for expression
+	Challenges["prod_capacity"] = &Challenge{
+		ID:          "prod_capacity",
+		Name:        "Capacity Planner",
+		Description: "Use a for expression with an if clause to map the name of every prod server in ctfchallenge_dataset to its cpu",
+		Points:      175,
+		Flag:        "flag{f0r_3xpr_c4p4c1ty_pl4nn3r}",
+		Difficulty:  "beginner",
+		Category:    "collections",
+		Validator: NewStructuredValidator(validateDatasetTask(expectedProdCapacity,
+			"flag{f0r_3xpr_c4p4c1ty_pl4nn3r}", "✓ Capacity planned! A for expression filtered and reshaped the fleet.",
+			"{ for s in servers : s.name => s.cpu if ... }"), ProofKindEncoded),
+	}
+
+	// flatten
+	Challenges["port_inventory"] = &Challenge{
+		ID:          "port_inventory",
+		Name:        "Port Inventory",
+		Description: "Flatten nested for expressions into a list of \"<name>:<port>\" strings for every port of every server, in dataset order",
+		Points:      200,
+		Flag:        "flag{fl4tt3n_p0rt_1nv3nt0ry}",
+		Difficulty:  "intermediate",
+		Category:    "collections",
+		Validator: NewStructuredValidator(validateDatasetTask(expectedPortInventory,
+			"flag{fl4tt3n_p0rt_1nv3nt0ry}", "✓ Inventory taken! flatten turned a list of lists into one list.",
+			"flatten([for s in servers : [for p in s.ports : ...]])"), ProofKindEncoded),
+	}
+
+	// Grouping mode
+	Challenges["region_groups"] = &Challenge{
+		ID:          "region_groups",
+		Name:        "Region Roll Call",
+		Description: "Group server names by region with the grouping ellipsis (...), keeping dataset order within each region",
+		Points:      200,
+		Flag:        "flag{gr0up_by_r3g10n_r0ll_c4ll}",
+		Difficulty:  "intermediate",
+		Category:    "collections",
+		Validator: NewStructuredValidator(validateDatasetTask(expectedRegionNames,
+			"flag{gr0up_by_r3g10n_r0ll_c4ll}", "✓ Roll called! The grouping ellipsis collected names per region.",
+			"{ for s in servers : s.region => s.name... }"), ProofKindEncoded),
+	}
+
+	// setproduct
+	Challenges["deployment_matrix"] = &Challenge{
+		ID:          "deployment_matrix",
+		Name:        "Deployment Matrix",
+		Description: "Use setproduct over the distinct regions and sizes to map every \"<region>-<size>\" combination to its number of servers, including empty ones",
+		Points:      250,
+		Flag:        "flag{s3tpr0duct_d3pl0ym3nt_m4tr1x}",
+		Difficulty:  "advanced",
+		Category:    "collections",
+		Validator: NewStructuredValidator(validateDatasetTask(expectedDeploymentMatrix,
+			"flag{s3tpr0duct_d3pl0ym3nt_m4tr1x}", "✓ Matrix complete! setproduct covered every combination, even the empty ones.",
+			"setproduct(distinct(servers[*].region), distinct(servers[*].size))"), ProofKindEncoded),
+	}
+}
+
+// validateDatasetTask checks jsonencoded output against the result recomputed
+// from the dataset.
+func validateDatasetTask(expected func() interface{}, flag, message, hint string) StructuredValidatorFunc {
+	return func(proof *ProofData) ValidationResult {
+		result := ValidationResult{
+			Success: false,
+			Details: []string{},
+		}
+
+		got, ok := decodeEncodedOutput(&result, proof.EncodedOutput, "json")
+		if !ok {
+			return result
+		}
+
+		if diff := describeDifference("$", expected(), got); diff != "" {
+			result.fail("output.content", fmt.Sprintf("Output does not match the dataset: %s", diff),
+				fmt.Sprintf("Compute the result from data.ctfchallenge_dataset with %s and submit jsonencode() of it", hint))
+			return result
+		}
+		result.pass("output.content", "Output matches the result computed from the dataset")
+
+		result.Success = true
+		result.Flag = flag
+		result.Message = message
+		return result
+	}
+}
+
+// validateForEachTask checks the for_each_wizard output like
+// validateDatasetTask, after checking that a terraform_data resource proof
+// declares one for_each instance per us-east-1 server.
+func validateForEachTask(flag, message, hint string) StructuredValidatorFunc {
+	datasetTask := validateDatasetTask(expectedForEachSizes, flag, message, hint)
+	return func(proof *ProofData) ValidationResult {
+		result := ValidationResult{
+			Success: false,
+			Details: []string{},
+		}
+
+		forEach := ""
+		for _, resource := range proof.Resources {
+			if value, ok := resource.MetaArguments["for_each"].(string); ok && resource.ResourceType == "terraform_data" && value != "" {
+				forEach = value
+				break
+			}
+		}
+		if forEach == "" {
+			result.fail("resource.for_each", "No terraform_data resource proof with a for_each meta-argument",
+				"Add a resource_proof for terraform_data.server with meta_arguments = { for_each = jsonencode(keys(terraform_data.server)) }")
+			return result
+		}
+
+		keys := []string{}
+		if err := json.Unmarshal([]byte(forEach), &keys); err != nil {
+			result.fail("resource.for_each", "The for_each meta-argument is not a JSON list of instance keys",
+				"Set for_each = jsonencode(keys(terraform_data.server)) in meta_arguments")
+			return result
+		}
+		expected := []string{}
+		for name := range expectedForEachSizes().(map[string]interface{}) {
+			expected = append(expected, name)
+		}
+		sort.Strings(keys)
+		sort.Strings(expected)
+		if fmt.Sprint(keys) != fmt.Sprint(expected) {
+			result.AddCheck(Check{
+				ID:       "resource.for_each",
+				Status:   CheckFail,
+				Expected: fmt.Sprint(expected),
+				Actual:   fmt.Sprint(keys),
+				Message:  "for_each instance keys do not match the us-east-1 server names",
+				Hint:     "Key the for_each map by server name and keep only the us-east-1 servers",
+			})
+			result.Message = "for_each instance keys do not match the us-east-1 server names"
+			return result
+		}
+		result.pass("resource.for_each", "terraform_data has one for_each instance per us-east-1 server")
+
+		output := datasetTask(proof)
+		output.Checks = append(result.Checks, output.Checks...)
+		output.Details = append(result.Details, output.Details...)
+		return output
+	}
+}
+
+// expectedNameToRegion is the zipmap_lookup answer.
+func expectedNameToRegion() interface{} {
+	out := map[string]interface{}{}
+	for _, s := range Dataset {
+		out[s.Name] = s.Region
+	}
+	return out
+}
+
+// expectedProdCapacity is the prod_capacity answer.
+func expectedProdCapacity() interface{} {
+	out := map[string]interface{}{}
+	for _, s := range QueryDataset(DatasetFilter{Environment: "prod"}) {
+		out[s.Name] = float64(s.CPU)
+	}
+	return out
+}
+
+// expectedPortInventory is the port_inventory answer.
+func expectedPortInventory() interface{} {
+	out := []interface{}{}
+	for _, s := range Dataset {
+		for _, port := range s.Ports {
+			out = append(out, fmt.Sprintf("%s:%d", s.Name, port))
+		}
+	}
+	return out
+}
+
+// expectedRegionNames is the region_groups answer.
+func expectedRegionNames() interface{} {
+	out := map[string]interface{}{}
+	for _, s := range Dataset {
+		names, _ := out[s.Region].([]interface{})
+		out[s.Region] = append(names, s.Name)
+	}
+	return out
+}
+
+// expectedDeploymentMatrix is the deployment_matrix answer.
+func expectedDeploymentMatrix() interface{} {
+	regions := map[string]bool{}
+	sizes := map[string]bool{}
+	counts := map[string]int{}
+	for _, s := range Dataset {
+		regions[s.Region] = true
+		sizes[s.Size] = true
+		counts[s.Region+"-"+s.Size]++
+	}
+
+	out := map[string]interface{}{}
+	for _, region := range sortedSet(regions) {
+		for _, size := range sortedSet(sizes) {
+			key := region + "-" + size
+			out[key] = float64(counts[key])
+		}
+	}
+	return out
+}
+
+func sortedSet(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// expectedForEachSizes is the for_each_wizard answer.
+func expectedForEachSizes() interface{} {
+	out := map[string]interface{}{}
+	for _, s := range QueryDataset(DatasetFilter{Region: "us-east-1"}) {
+		out[s.Name] = s.Size
+	}
+	return out
+}
+
+// expectedCriticalNames is the data_source_detective answer.
+func expectedCriticalNames() interface{} {
+	out := []interface{}{}
+	for _, s := range QueryDataset(DatasetFilter{Tag: "critical"}) {
+		out = append(out, s.Name)
+	}
+	return out
+}
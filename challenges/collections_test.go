@@ -0,0 +1,189 @@
+package challenges
+
+import (
+	"fmt"
+	"testing"
+)
+
+// forEachServer is a resource proof for a terraform_data resource whose
+// for_each created the given instance keys.
+func forEachServer(keys string) []ResourceProof {
+	return []ResourceProof{{
+		ResourceType:  "terraform_data",
+		ResourceName:  "server",
+		MetaArguments: map[string]interface{}{"for_each": keys},
+	}}
+}
+
+func TestCollectionsValidators(t *testing.T) {
+	const usEast = `["alpha","beta","iota","lambda","mu","omicron","zeta"]`
+
+	cases := map[string]struct {
+		challenge string
+		output    string
+		resources []ResourceProof
+		failed    string
+	}{
+		"zipmap": {
+			challenge: "zipmap_lookup",
+			output: `{"alpha":"us-east-1","beta":"us-east-1","gamma":"eu-west-1","delta":"eu-west-1","epsilon":"ap-south-1","zeta":"us-east-1",
+				"eta":"eu-west-1","theta":"ap-south-1","iota":"us-east-1","kappa":"eu-west-1","lambda":"us-east-1","mu":"us-east-1",
+				"nu":"eu-west-1","xi":"ap-south-1","omicron":"us-east-1","pi":"eu-west-1"}`,
+		},
+		"zipmap missing a server": {
+			challenge: "zipmap_lookup",
+			output: `{"alpha":"us-east-1","beta":"us-east-1","gamma":"eu-west-1","delta":"eu-west-1","epsilon":"ap-south-1","zeta":"us-east-1",
+				"eta":"eu-west-1","theta":"ap-south-1","iota":"us-east-1","kappa":"eu-west-1","lambda":"us-east-1","mu":"us-east-1",
+				"nu":"eu-west-1","xi":"ap-south-1","omicron":"us-east-1"}`,
+			failed: "output.content",
+		},
+		"zipmap of regions to names": {
+			challenge: "zipmap_lookup",
+			output:    `{"us-east-1":"omicron","eu-west-1":"pi","ap-south-1":"xi"}`,
+			failed:    "output.content",
+		},
+		"prod capacity": {
+			challenge: "prod_capacity",
+			output:    `{"alpha":8,"beta":4,"gamma":8,"eta":4,"theta":8,"mu":8,"xi":4}`,
+		},
+		"prod capacity as strings": {
+			challenge: "prod_capacity",
+			output:    `{"alpha":"8","beta":"4","gamma":"8","eta":"4","theta":"8","mu":"8","xi":"4"}`,
+			failed:    "output.content",
+		},
+		"prod capacity including staging": {
+			challenge: "prod_capacity",
+			output:    `{"alpha":8,"beta":4,"gamma":8,"eta":4,"theta":8,"mu":8,"xi":4,"iota":4}`,
+			failed:    "output.content",
+		},
+		"port inventory": {
+			challenge: "port_inventory",
+			output: `["alpha:80","alpha:443","beta:8080","gamma:5432","delta:80","delta:443","epsilon:8080","zeta:6379","eta:8080","eta:9090",
+				"theta:5432","iota:5432","kappa:80","lambda:6379","mu:6379","nu:80","nu:8080","xi:443","omicron:8080","pi:5432"]`,
+		},
+		"port inventory not flattened": {
+			challenge: "port_inventory",
+			output: `[["alpha:80","alpha:443"],["beta:8080"],["gamma:5432"],["delta:80","delta:443"],["epsilon:8080"],["zeta:6379"],["eta:8080","eta:9090"],
+				["theta:5432"],["iota:5432"],["kappa:80"],["lambda:6379"],["mu:6379"],["nu:80","nu:8080"],["xi:443"],["omicron:8080"],["pi:5432"]]`,
+			failed: "output.content",
+		},
+		"port inventory with first ports only": {
+			challenge: "port_inventory",
+			output: `["alpha:80","beta:8080","gamma:5432","delta:80","epsilon:8080","zeta:6379","eta:8080","theta:5432","iota:5432","kappa:80",
+				"lambda:6379","mu:6379","nu:80","xi:443","omicron:8080","pi:5432"]`,
+			failed: "output.content",
+		},
+		"region groups": {
+			challenge: "region_groups",
+			output: `{"us-east-1":["alpha","beta","zeta","iota","lambda","mu","omicron"],"eu-west-1":["gamma","delta","eta","kappa","nu","pi"],
+				"ap-south-1":["epsilon","theta","xi"]}`,
+		},
+		"region groups sorted": {
+			challenge: "region_groups",
+			output: `{"us-east-1":["alpha","beta","iota","lambda","mu","omicron","zeta"],"eu-west-1":["delta","eta","gamma","kappa","nu","pi"],
+				"ap-south-1":["epsilon","theta","xi"]}`,
+			failed: "output.content",
+		},
+		"deployment matrix": {
+			challenge: "deployment_matrix",
+			output: `{"ap-south-1-large":1,"ap-south-1-medium":2,"ap-south-1-small":0,"eu-west-1-large":2,"eu-west-1-medium":2,"eu-west-1-small":2,
+				"us-east-1-large":2,"us-east-1-medium":2,"us-east-1-small":3}`,
+		},
+		"deployment matrix without empty combinations": {
+			challenge: "deployment_matrix",
+			output: `{"ap-south-1-large":1,"ap-south-1-medium":2,"eu-west-1-large":2,"eu-west-1-medium":2,"eu-west-1-small":2,
+				"us-east-1-large":2,"us-east-1-medium":2,"us-east-1-small":3}`,
+			failed: "output.content",
+		},
+		"for_each sizes": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small"}`,
+			resources: forEachServer(usEast),
+		},
+		"for_each sizes of every region": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small","gamma":"large"}`,
+			resources: forEachServer(usEast),
+			failed:    "output.content",
+		},
+		"for_each sizes without for_each": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small"}`,
+			failed:    "resource.for_each",
+		},
+		"for_each on another resource type": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small"}`,
+			resources: []ResourceProof{{ResourceType: "null_resource", ResourceName: "server", MetaArguments: map[string]interface{}{"for_each": usEast}}},
+			failed:    "resource.for_each",
+		},
+		"for_each keys as a string": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small"}`,
+			resources: forEachServer("alpha,beta,iota,lambda,mu,omicron,zeta"),
+			failed:    "resource.for_each",
+		},
+		"for_each over every server": {
+			challenge: "for_each_wizard",
+			output:    `{"alpha":"large","beta":"medium","zeta":"small","iota":"medium","lambda":"small","mu":"large","omicron":"small"}`,
+			resources: forEachServer(`["alpha","beta","gamma","iota","lambda","mu","omicron","zeta"]`),
+			failed:    "resource.for_each",
+		},
+		"for_each without output": {
+			challenge: "for_each_wizard",
+			resources: forEachServer(usEast),
+			failed:    "output.present",
+		},
+		"critical names": {
+			challenge: "data_source_detective",
+			output:    `["alpha","beta","gamma","eta","theta","mu","xi"]`,
+		},
+		"critical names out of order": {
+			challenge: "data_source_detective",
+			output:    `["alpha","beta","eta","gamma","mu","theta","xi"]`,
+			failed:    "output.content",
+		},
+		"critical prod names": {
+			challenge: "data_source_detective",
+			output:    `["alpha","beta","gamma","eta","theta","mu"]`,
+			failed:    "output.content",
+		},
+		"yaml output": {
+			challenge: "data_source_detective",
+			output:    "- alpha\n- beta\n",
+			failed:    "output.format",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &ProofData{EncodedOutput: tc.output, Resources: tc.resources}, tc.failed)
+		})
+	}
+}
+
+func TestQueryDataset(t *testing.T) {
+	cases := map[string]struct {
+		filter   DatasetFilter
+		expected []string
+	}{
+		"everything":     {filter: DatasetFilter{}, expected: []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta", "iota", "kappa", "lambda", "mu", "nu", "xi", "omicron", "pi"}},
+		"region":         {filter: DatasetFilter{Region: "ap-south-1"}, expected: []string{"epsilon", "theta", "xi"}},
+		"tag":            {filter: DatasetFilter{Tag: "db"}, expected: []string{"gamma", "theta", "iota", "pi"}},
+		"every field":    {filter: DatasetFilter{Region: "eu-west-1", Environment: "staging", Size: "medium", Tag: "api"}, expected: []string{"nu"}},
+		"no match":       {filter: DatasetFilter{Region: "us-east-1", Tag: "db", Size: "large"}, expected: []string{}},
+		"unknown region": {filter: DatasetFilter{Region: "mars-1"}, expected: []string{}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			names := []string{}
+			for _, s := range QueryDataset(tc.filter) {
+				names = append(names, s.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tc.expected) {
+				t.Fatalf("\n\nexpected:\n\n%v\n\ngot:\n\n%v\n\n", tc.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package challenges
+
+// DatasetServer is a fictional server in the dataset served by the
+// ctfchallenge_dataset data source.
+type DatasetServer struct {
+	Name        string
+	Region      string
+	Size        string
+	Environment string
+	CPU         int
+	Tags        []string
+	Ports       []int
+}
+
+// Dataset is the fleet of servers the collections challenges are computed
+// from. Its order is part of the dataset: challenges that expect lists expect
+// them in this order.
+var Dataset = []DatasetServer{
+	{Name: "alpha", Region: "us-east-1", Size: "large", Environment: "prod", CPU: 8, Tags: []string{"web", "critical"}, Ports: []int{80, 443}},
+	{Name: "beta", Region: "us-east-1", Size: "medium", Environment: "prod", CPU: 4, Tags: []string{"api", "critical"}, Ports: []int{8080}},
+	{Name: "gamma", Region: "eu-west-1", Size: "large", Environment: "prod", CPU: 8, Tags: []string{"db", "critical"}, Ports: []int{5432}},
+	{Name: "delta", Region: "eu-west-1", Size: "small", Environment: "staging", CPU: 2, Tags: []string{"web"}, Ports: []int{80, 443}},
+	{Name: "epsilon", Region: "ap-south-1", Size: "medium", Environment: "dev", CPU: 4, Tags: []string{"api"}, Ports: []int{8080}},
+	{Name: "zeta", Region: "us-east-1", Size: "small", Environment: "dev", CPU: 2, Tags: []string{"cache"}, Ports: []int{6379}},
+	{Name: "eta", Region: "eu-west-1", Size: "medium", Environment: "prod", CPU: 4, Tags: []string{"api", "critical"}, Ports: []int{8080, 9090}},
+	{Name: "theta", Region: "ap-south-1", Size: "large", Environment: "prod", CPU: 8, Tags: []string{"db", "critical"}, Ports: []int{5432}},
+	{Name: "iota", Region: "us-east-1", Size: "medium", Environment: "staging", CPU: 4, Tags: []string{"db"}, Ports: []int{5432}},
+	{Name: "kappa", Region: "eu-west-1", Size: "small", Environment: "dev", CPU: 2, Tags: []string{"web"}, Ports: []int{80}},
+	{Name: "lambda", Region: "us-east-1", Size: "small", Environment: "staging", CPU: 2, Tags: []string{"cache"}, Ports: []int{6379}},
+	{Name: "mu", Region: "us-east-1", Size: "large", Environment: "prod", CPU: 8, Tags: []string{"cache", "critical"}, Ports: []int{6379}},
+	{Name: "nu", Region: "eu-west-1", Size: "medium", Environment: "staging", CPU: 4, Tags: []string{"web", "api"}, Ports: []int{80, 8080}},
+	{Name: "xi", Region: "ap-south-1", Size: "medium", Environment: "prod", CPU: 4, Tags: []string{"web", "critical"}, Ports: []int{443}},
+	{Name: "omicron", Region: "us-east-1", Size: "small", Environment: "dev", CPU: 2, Tags: []string{"api"}, Ports: []int{8080}},
+	{Name: "pi", Region: "eu-west-1", Size: "large", Environment: "staging", CPU: 8, Tags: []string{"db"}, Ports: []int{5432}},
+}
+
+// DatasetFilter selects servers from the dataset. Empty fields match every
+// server.
+type DatasetFilter struct {
+	Region      string
+	Environment string
+	Size        string
+	Tag         string
+}
+
+// Matches reports whether a server satisfies every field of the filter.
+func (f DatasetFilter) Matches(s DatasetServer) bool {
+	if f.Region != "" && s.Region != f.Region {
+		return false
+	}
+	if f.Environment != "" && s.Environment != f.Environment {
+		return false
+	}
+	if f.Size != "" && s.Size != f.Size {
+		return false
+	}
+	if f.Tag != "" && !containsString(s.Tags, f.Tag) {
+		return false
+	}
+	return true
+}
+
+// QueryDataset returns the servers matching a filter, in dataset order.
+func QueryDataset(filter DatasetFilter) []DatasetServer {
+	servers := []DatasetServer{}
+	for _, s := range Dataset {
+		if filter.Matches(s) {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// DriftProof is the update history of a ctfchallenge_drift_target, observed
+// by the provider in the simulated cloud rather than reported by the player.
+type DriftProof struct {
+	TargetID    string
+	ChallengeID string
+	Refreshes   int
+	// Revision counts the updates Terraform applied to the target
+	Revision int
+	Drifts   int
+	// DriftsSinceRevert counts drifts since Terraform last reverted one
+	DriftsSinceRevert int
+	// Reverted is set once an apply has undone a drift
+	Reverted bool
+	// ReconciledRefreshes counts saved states that already held the drifted
+	// values when next refreshed. Each state counts once, so plans, which
+	// save nothing, add nothing
+	ReconciledRefreshes int
+	// ReconciledSinceRevert counts them since Terraform last reverted a
+	// drift: applies that kept the drift in state
+	ReconciledSinceRevert int
+
+	// Downstream targets only
+	UpstreamID       string
+	UpstreamRevision int
+	// CurrentUpstreamRevision is the upstream's revision now
+	CurrentUpstreamRevision int
+	// Generation numbers the downstream targets created for the upstream
+	Generation int
+	// ReplacedWithUpstream is set when the previous downstream target was
+	// destroyed before the upstream's latest update and this one created
+	// after it, as a replacement planned with the update does
+	ReplacedWithUpstream bool
+}
+
+// DriftSchedules describe, for each drift challenge, what changes outside
+// Terraform and when. The provider applies them in ctfchallenge_drift_target's
+// Read.
+var DriftSchedules = map[string]string{
+	"drift_ignore":    "Every refresh, an ops bot rewrites the patched_by label",
+	"refresh_only":    "On the first refresh after creation, an autoscaler doubles size",
+	"replace_trigger": "On the first refresh after creation, a key rotation job adds a rotated_by label to the upstream target",
+}
+
+func init() {
+	registerDriftChallenges()
+}
+
+func registerDriftChallenges() {
+	// ignore_changes
+	Challenges["drift_ignore"] = &Challenge{
+		ID:          "drift_ignore",
+		Name:        "Drift Tamer",
+		Description: fmt.Sprintf("A ctfchallenge_drift_target's labels drift on every refresh. Use ignore_changes so that %d applies in a row keep the drifted labels instead of reverting them", driftIgnoreStreak),
+		Points:      200,
+		Flag:        "flag{1gn0r3_ch4ng3s_dr1ft_t4m3d}",
+		Difficulty:  "intermediate",
+		Category:    "drift",
+		Validator:   NewStructuredValidator(validateDriftIgnore, ProofKindDrift),
+	}
+
+	// -refresh-only
+	Challenges["refresh_only"] = &Challenge{
+		ID:          "refresh_only",
+		Name:        "Refresh Ritual",
+		Description: "A ctfchallenge_drift_target is resized outside Terraform. Accept the change into state with terraform apply -refresh-only instead of reverting it",
+		Points:      175,
+		Flag:        "flag{r3fr3sh_0nly_4cc3pt_r34l1ty}",
+		Difficulty:  "beginner",
+		Category:    "drift",
+		Validator:   NewStructuredValidator(validateRefreshOnly, ProofKindDrift),
+	}
+
+	// replace_triggered_by
+	Challenges["replace_trigger"] = &Challenge{
+		ID:          "replace_trigger",
+		Name:        "Chain Reaction",
+		Description: "When an upstream ctfchallenge_drift_target is updated, replace its downstream target with replace_triggered_by",
+		Points:      250,
+		Flag:        "flag{r3pl4c3_tr1gg3r3d_ch41n_r34ct10n}",
+		Difficulty:  "advanced",
+		Category:    "drift",
+		Validator:   NewStructuredValidator(validateReplaceTrigger, ProofKindDrift),
+	}
+}
+
+// driftIgnoreStreak is the number of applies in a row that must keep the
+// drifted labels for drift_ignore.
+const driftIgnoreStreak = 3
+
+// DriftChallengeIDs returns the challenges served by ctfchallenge_drift_target, sorted.
+func DriftChallengeIDs() []string {
+	ids := make([]string, 0, len(DriftSchedules))
+	for id := range DriftSchedules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkDriftTarget checks that the observed target was created for the
+// challenge being validated.
+func checkDriftTarget(result *ValidationResult, proof *DriftProof, challengeID string) bool {
+	if proof.ChallengeID != challengeID {
+		result.fail("target.challenge", fmt.Sprintf("Drift target %s was created for %s, not %s", proof.TargetID, proof.ChallengeID, challengeID),
+			fmt.Sprintf("Set challenge_id = \"%s\" on the ctfchallenge_drift_target", challengeID))
+		return false
+	}
+	result.pass("target.challenge", fmt.Sprintf("Drift target %s belongs to %s", proof.TargetID, challengeID))
+	return true
+}
+
+func validateDriftIgnore(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	drift := proof.Drift
+	if !checkDriftTarget(&result, drift, "drift_ignore") {
+		return result
+	}
+
+	// A plan refreshes without saving state, so only applies that write the
+	// drifted labels to state count
+	if drift.ReconciledSinceRevert < driftIgnoreStreak {
+		hint := "Run terraform apply again; every apply that keeps the drifted labels in state counts. terraform plan alone saves nothing"
+		if drift.Reverted {
+			hint = "An apply reverted the drifted labels. Add lifecycle { ignore_changes = [labels] } so Terraform leaves them alone"
+		}
+		result.failCount("drift.streak", fmt.Sprintf("%d apply(s) in a row kept the drifted labels, expected %d", drift.ReconciledSinceRevert, driftIgnoreStreak),
+			strconv.Itoa(driftIgnoreStreak), drift.ReconciledSinceRevert, hint)
+		return result
+	}
+	result.pass("drift.streak", fmt.Sprintf("%d applies in a row kept the drifted labels", drift.ReconciledSinceRevert))
+
+	result.Success = true
+	result.Flag = "flag{1gn0r3_ch4ng3s_dr1ft_t4m3d}"
+	result.Message = "✓ Drift tamed! ignore_changes let the ops bot have its labels and kept your plan clean."
+	return result
+}
+
+func validateRefreshOnly(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	drift := proof.Drift
+	if !checkDriftTarget(&result, drift, "refresh_only") {
+		return result
+	}
+
+	if drift.Drifts == 0 {
+		result.fail("drift.observed", "Target has not drifted yet", "Run terraform plan once the target exists; the first refresh resizes it")
+		return result
+	}
+	result.pass("drift.observed", "Target was resized outside Terraform")
+
+	if drift.Reverted {
+		result.fail("drift.accepted", "An apply reverted the drifted size",
+			"Accept the drift instead: terraform apply -refresh-only, then set size to match. Recreate the target with terraform apply -replace to start over")
+		return result
+	}
+	result.pass("drift.accepted", "The drifted size was never reverted")
+
+	if drift.ReconciledRefreshes == 0 {
+		result.fail("state.reconciled", "State does not hold the drifted size yet",
+			"Run terraform apply -refresh-only and approve it, then plan again")
+		return result
+	}
+	result.pass("state.reconciled", "State holds the drifted size")
+
+	result.Success = true
+	result.Flag = "flag{r3fr3sh_0nly_4cc3pt_r34l1ty}"
+	result.Message = "✓ Reality accepted! -refresh-only brought state in line with the real world."
+	return result
+}
+
+func validateReplaceTrigger(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	drift := proof.Drift
+	if !checkDriftTarget(&result, drift, "replace_trigger") {
+		return result
+	}
+
+	if drift.UpstreamID == "" {
+		result.fail("target.downstream", "Drift target has no upstream_id",
+			"Submit the downstream target: the one whose upstream_id is the upstream target's id")
+		return result
+	}
+	result.pass("target.downstream", fmt.Sprintf("Downstream of %s", drift.UpstreamID))
+
+	if drift.CurrentUpstreamRevision == 0 {
+		result.fail("upstream.updated", "The upstream target has not been updated yet",
+			"Run terraform plan until the upstream drifts, then apply to revert it")
+		return result
+	}
+	result.pass("upstream.updated", fmt.Sprintf("Upstream target is at revision %d", drift.CurrentUpstreamRevision))
+
+	if drift.UpstreamRevision != drift.CurrentUpstreamRevision {
+		result.fail("downstream.in_sync", fmt.Sprintf("Downstream target was created at upstream revision %d, but the upstream is at revision %d",
+			drift.UpstreamRevision, drift.CurrentUpstreamRevision),
+			"Add lifecycle { replace_triggered_by = [ctfchallenge_drift_target.<upstream>] } to the downstream target")
+		return result
+	}
+	result.pass("downstream.in_sync", "Downstream target was created after the upstream's latest update")
+
+	if drift.Generation < 2 {
+		result.fail("downstream.replaced", "The downstream target has never been replaced",
+			"The downstream must be replaced because the upstream changed, not created after it")
+		return result
+	}
+	result.pass("downstream.replaced", fmt.Sprintf("Downstream target is generation %d", drift.Generation))
+
+	if !drift.ReplacedWithUpstream {
+		result.fail("downstream.replaced_with_upstream", "The downstream target was not replaced in the same apply as the upstream's update",
+			"Replacing it afterwards, e.g. with terraform apply -replace, does not count. Add replace_triggered_by so the upstream's update plans the replacement")
+		return result
+	}
+	result.pass("downstream.replaced_with_upstream", "The downstream target was replaced in the same apply as the upstream's update")
+
+	result.Success = true
+	result.Flag = "flag{r3pl4c3_tr1gg3r3d_ch41n_r34ct10n}"
+	result.Message = "✓ Chain reaction! The upstream update replaced its downstream target."
+	return result
+}
@@ -0,0 +1,111 @@
+package challenges
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDriftChallengeIDs(t *testing.T) {
+	expected := []string{"drift_ignore", "refresh_only", "replace_trigger"}
+	if actual := DriftChallengeIDs(); strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected drift challenges %v, got %v", expected, actual)
+	}
+	for _, id := range expected {
+		if _, ok := Challenges[id]; !ok {
+			t.Fatalf("drift challenge %q is not registered", id)
+		}
+	}
+}
+
+func TestDriftValidators(t *testing.T) {
+	cases := map[string]struct {
+		// challenge defaults to the proof's challenge
+		challenge string
+		proof     DriftProof
+		failed    string
+	}{
+		"drift ignore": {
+			proof: DriftProof{TargetID: "drift-1", ChallengeID: "drift_ignore", Refreshes: 5, Drifts: 5, DriftsSinceRevert: 5, ReconciledRefreshes: 3, ReconciledSinceRevert: 3},
+		},
+		"drift ignore for another challenge": {
+			challenge: "drift_ignore",
+			proof:     DriftProof{TargetID: "drift-1", ChallengeID: "refresh_only", Refreshes: 5, Drifts: 1, ReconciledRefreshes: 3, ReconciledSinceRevert: 3},
+			failed:    "target.challenge",
+		},
+		"drift ignore without drift saved": {
+			proof:  DriftProof{TargetID: "drift-1", ChallengeID: "drift_ignore", Refreshes: 1, Drifts: 1, DriftsSinceRevert: 1},
+			failed: "drift.streak",
+		},
+		"drift ignore short of the streak": {
+			proof:  DriftProof{TargetID: "drift-1", ChallengeID: "drift_ignore", Refreshes: 3, Drifts: 3, DriftsSinceRevert: 3, ReconciledRefreshes: 2, ReconciledSinceRevert: 2},
+			failed: "drift.streak",
+		},
+		"drift ignore reverted": {
+			proof: DriftProof{TargetID: "drift-1", ChallengeID: "drift_ignore", Refreshes: 5, Revision: 1, Drifts: 5, DriftsSinceRevert: 1,
+				Reverted: true, ReconciledRefreshes: 3},
+			failed: "drift.streak",
+		},
+		"drift ignore reconciled since a revert": {
+			proof: DriftProof{TargetID: "drift-1", ChallengeID: "drift_ignore", Refreshes: 8, Revision: 1, Drifts: 8, DriftsSinceRevert: 4,
+				Reverted: true, ReconciledRefreshes: 5, ReconciledSinceRevert: 3},
+		},
+		"refresh only": {
+			proof: DriftProof{TargetID: "drift-1", ChallengeID: "refresh_only", Refreshes: 3, Drifts: 1, DriftsSinceRevert: 1, ReconciledRefreshes: 1, ReconciledSinceRevert: 1},
+		},
+		"refresh only before drifting": {
+			proof:  DriftProof{TargetID: "drift-1", ChallengeID: "refresh_only"},
+			failed: "drift.observed",
+		},
+		"refresh only reverted": {
+			proof: DriftProof{TargetID: "drift-1", ChallengeID: "refresh_only", Refreshes: 3, Revision: 1, Drifts: 1,
+				Reverted: true, ReconciledRefreshes: 1},
+			failed: "drift.accepted",
+		},
+		"refresh only not reconciled": {
+			proof:  DriftProof{TargetID: "drift-1", ChallengeID: "refresh_only", Refreshes: 2, Drifts: 1, DriftsSinceRevert: 1},
+			failed: "state.reconciled",
+		},
+		"replace trigger": {
+			proof: DriftProof{TargetID: "drift-3", ChallengeID: "replace_trigger", Refreshes: 1, UpstreamID: "drift-1",
+				UpstreamRevision: 1, CurrentUpstreamRevision: 1, Generation: 2, ReplacedWithUpstream: true},
+		},
+		"replace trigger with the upstream": {
+			proof:  DriftProof{TargetID: "drift-1", ChallengeID: "replace_trigger", Refreshes: 2, Revision: 1, Drifts: 1, Reverted: true},
+			failed: "target.downstream",
+		},
+		"replace trigger before the upstream updates": {
+			proof:  DriftProof{TargetID: "drift-2", ChallengeID: "replace_trigger", Refreshes: 1, UpstreamID: "drift-1", Generation: 1},
+			failed: "upstream.updated",
+		},
+		"replace trigger left behind": {
+			proof: DriftProof{TargetID: "drift-2", ChallengeID: "replace_trigger", Refreshes: 2, UpstreamID: "drift-1",
+				CurrentUpstreamRevision: 1, Generation: 1},
+			failed: "downstream.in_sync",
+		},
+		"replace trigger created after the update": {
+			proof: DriftProof{TargetID: "drift-2", ChallengeID: "replace_trigger", UpstreamID: "drift-1",
+				UpstreamRevision: 1, CurrentUpstreamRevision: 1, Generation: 1},
+			failed: "downstream.replaced",
+		},
+		"replace trigger replaced in a later apply": {
+			proof: DriftProof{TargetID: "drift-3", ChallengeID: "replace_trigger", UpstreamID: "drift-1",
+				UpstreamRevision: 1, CurrentUpstreamRevision: 1, Generation: 2},
+			failed: "downstream.replaced_with_upstream",
+		},
+		"replace trigger left behind after a replacement": {
+			proof: DriftProof{TargetID: "drift-3", ChallengeID: "replace_trigger", UpstreamID: "drift-1",
+				UpstreamRevision: 1, CurrentUpstreamRevision: 2, Generation: 2, ReplacedWithUpstream: true},
+			failed: "downstream.in_sync",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			challenge := tc.challenge
+			if challenge == "" {
+				challenge = tc.proof.ChallengeID
+			}
+			expectValidation(t, challenge, &ProofData{Drift: &tc.proof}, tc.failed)
+		})
+	}
+}
@@ -0,0 +1,500 @@
+package challenges
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	registerEncodingChallenges()
+}
+
+func registerEncodingChallenges() {
+	// CSV to JSON
+	Challenges["csv_records"] = &Challenge{
+		ID:          "csv_records",
+		Name:        "CSV Cartographer",
+		Description: "Decode the players.csv fixture with csvdecode and jsonencode a map of active players keyed by name, each with team and a numeric score",
+		Points:      150,
+		Flag:        "flag{csv_c4rt0gr4ph3r_m4pp3d}",
+		Difficulty:  "beginner",
+		Category:    "encoding",
+		Validator:   NewStructuredValidator(validateCSVRecordsStructure, ProofKindEncoded),
+	}
+
+	// YAML Transformation
+	Challenges["yaml_transform"] = &Challenge{
+		ID:          "yaml_transform",
+		Name:        "YAML Whisperer",
+		Description: "Decode the config.yaml fixture and yamlencode a map of region to the names of its environments with at least 2 replicas",
+		Points:      200,
+		Flag:        "flag{y4ml_wh1sp3r3r_r3g10ns}",
+		Difficulty:  "intermediate",
+		Category:    "encoding",
+		Validator:   NewStructuredValidator(validateYAMLTransformStructure, ProofKindEncoded),
+	}
+
+	// Template Rendering
+	Challenges["template_render"] = &Challenge{
+		ID:          "template_render",
+		Name:        "Template Smith",
+		Description: "Render the team_report.tftpl fixture with templatefile for the active members of team blue from players.csv",
+		Points:      200,
+		Flag:        "flag{t3mpl4t3_sm1th_r3nd3r3d}",
+		Difficulty:  "intermediate",
+		Category:    "encoding",
+		Validator:   NewStructuredValidator(validateTemplateRenderStructure, ProofKindEncoded),
+	}
+
+	// Compressed Payload
+	Challenges["base64gzip_payload"] = &Challenge{
+		ID:          "base64gzip_payload",
+		Name:        "Compressed Courier",
+		Description: "Summarise the active players of each team from players.csv and submit the summary as base64gzip(jsonencode(...))",
+		Points:      250,
+		Flag:        "flag{c0mpr3ss3d_c0ur13r_gz1p}",
+		Difficulty:  "advanced",
+		Category:    "encoding",
+		Validator:   NewStructuredValidator(validateBase64GzipPayloadStructure, ProofKindEncoded),
+	}
+}
+
+func validateCSVRecordsStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	got, ok := decodeEncodedOutput(&result, proof.EncodedOutput, "json")
+	if !ok {
+		return result
+	}
+
+	if !checkCanonicalMatch(&result, expectedActivePlayers(), got) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{csv_c4rt0gr4ph3r_m4pp3d}"
+	result.Message = "✓ CSV charted! Flat records became a structured map with real numbers."
+	return result
+}
+
+func validateYAMLTransformStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	got, ok := decodeEncodedOutput(&result, proof.EncodedOutput, "yaml")
+	if !ok {
+		return result
+	}
+
+	if !checkCanonicalMatch(&result, expectedRegionGroups(), got) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{y4ml_wh1sp3r3r_r3g10ns}"
+	result.Message = "✓ YAML whispered! Environments regrouped by region and encoded back to YAML."
+	return result
+}
+
+func validateTemplateRenderStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	want := canonicalLines(expectedTeamReport())
+	got := canonicalLines(proof.EncodedOutput)
+
+	if len(got) != len(want) {
+		result.failCount("output.lines", fmt.Sprintf("Rendered report has %d line(s), expected %d", len(got), len(want)),
+			strconv.Itoa(len(want)), len(got), "Use ~ strip markers in the for directive so it doesn't add blank lines")
+		return result
+	}
+	result.pass("output.lines", fmt.Sprintf("Rendered report has %d lines", len(got)))
+
+	for i := range want {
+		if got[i] != want[i] {
+			result.fail(fmt.Sprintf("output.line.%d", i+1), fmt.Sprintf("Line %d of the rendered report differs", i+1),
+				"Pass team = \"blue\", members = a list of {name, score} objects in CSV order and total = the sum of their scores")
+			return result
+		}
+	}
+	result.pass("output.content", "Rendered report matches")
+
+	result.Success = true
+	result.Flag = "flag{t3mpl4t3_sm1th_r3nd3r3d}"
+	result.Message = "✓ Template forged! templatefile rendered the report from decoded data."
+	return result
+}
+
+func validateBase64GzipPayloadStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	got, ok := decodeEncodedOutput(&result, proof.EncodedOutput, "base64gzip")
+	if !ok {
+		return result
+	}
+
+	if !checkCanonicalMatch(&result, expectedTeamSummary(), got) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{c0mpr3ss3d_c0ur13r_gz1p}"
+	result.Message = "✓ Payload delivered! Your summary survived encoding, compression and the trip back."
+	return result
+}
+
+// maxDecompressedOutput caps the size of decompressed base64gzip output, so
+// that a small payload cannot expand without bound. Every answer is far
+// smaller.
+const maxDecompressedOutput = 1 << 20
+
+// decodeEncodedOutput decodes submitted output in the given format (json,
+// yaml or base64gzip-compressed json) into a canonical value.
+func decodeEncodedOutput(result *ValidationResult, content, format string) (interface{}, bool) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		result.fail("output.present", "encoded_output is empty", "")
+		return nil, false
+	}
+
+	var value interface{}
+	var err error
+
+	switch format {
+	case "json":
+		value, err = decodeJSON([]byte(content))
+		if err != nil {
+			result.fail("output.format", fmt.Sprintf("Output is not valid JSON: %s", err), "Encode the value with jsonencode()")
+			return nil, false
+		}
+	case "yaml":
+		if json.Valid([]byte(content)) {
+			result.fail("output.format", "Output is JSON, not YAML", "Encode the value with yamlencode()")
+			return nil, false
+		}
+		value, err = decodeYAML([]byte(content))
+		if err != nil {
+			result.fail("output.format", fmt.Sprintf("Output is not valid YAML: %s", err), "Encode the value with yamlencode()")
+			return nil, false
+		}
+	case "base64gzip":
+		compressed, decodeErr := base64.StdEncoding.DecodeString(content)
+		if decodeErr != nil {
+			result.fail("output.format", "Output is not base64 encoded", "Compress and encode the JSON with base64gzip()")
+			return nil, false
+		}
+		reader, gzipErr := gzip.NewReader(bytes.NewReader(compressed))
+		if gzipErr != nil {
+			result.fail("output.format", "Decoded output is not gzip compressed", "Use base64gzip() rather than base64encode()")
+			return nil, false
+		}
+		defer reader.Close()
+		raw, readErr := io.ReadAll(io.LimitReader(reader, maxDecompressedOutput))
+		if readErr != nil {
+			result.fail("output.format", fmt.Sprintf("Failed to decompress output: %s", readErr), "")
+			return nil, false
+		}
+		if len(raw) >= maxDecompressedOutput {
+			result.fail("output.format", fmt.Sprintf("Decompressed output is larger than %d bytes", maxDecompressedOutput),
+				"Submit only the summary: base64gzip(jsonencode(...))")
+			return nil, false
+		}
+		value, err = decodeJSON(raw)
+		if err != nil {
+			result.fail("output.format", fmt.Sprintf("Decompressed output is not valid JSON: %s", err), "Compress jsonencode() output: base64gzip(jsonencode(...))")
+			return nil, false
+		}
+	}
+
+	result.pass("output.format", fmt.Sprintf("Output decodes as %s", format))
+	return value, true
+}
+
+// checkCanonicalMatch compares canonical values, reporting the first
+// difference by path without revealing the expected values.
+func checkCanonicalMatch(result *ValidationResult, want, got interface{}) bool {
+	if diff := describeDifference("$", want, got); diff != "" {
+		result.fail("output.content", fmt.Sprintf("Output does not match the expected structure: %s", diff),
+			"Compare your transformation with the challenge description")
+		return false
+	}
+	result.pass("output.content", "Output matches the expected structure")
+	return true
+}
+
+// decodeJSON decodes JSON into a canonical value.
+func decodeJSON(content []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected content after the JSON value")
+	}
+	return canonicalize(value), nil
+}
+
+// decodeYAML decodes a YAML document into a canonical value.
+func decodeYAML(content []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+	return canonicalize(value), nil
+}
+
+// canonicalize normalises decoded JSON or YAML so that values compare equal
+// regardless of key order, whitespace or number representation: maps have
+// string keys and every number is a float64.
+func canonicalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = canonicalize(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = canonicalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = canonicalize(item)
+		}
+		return out
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+// describeDifference returns a description of the first difference between
+// two canonical values, or "" when they are equal.
+func describeDifference(path string, want, got interface{}) string {
+	if kindOf(want) != kindOf(got) {
+		return fmt.Sprintf("%s should be %s, got %s", path, kindOf(want), kindOf(got))
+	}
+
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g := got.(map[string]interface{})
+		for _, key := range sortedKeys(w) {
+			if _, ok := g[key]; !ok {
+				return fmt.Sprintf("%s is missing key %q", path, key)
+			}
+		}
+		for _, key := range sortedKeys(g) {
+			if _, ok := w[key]; !ok {
+				return fmt.Sprintf("%s has unexpected key %q", path, key)
+			}
+		}
+		for _, key := range sortedKeys(w) {
+			if diff := describeDifference(path+"."+key, w[key], g[key]); diff != "" {
+				return diff
+			}
+		}
+	case []interface{}:
+		g := got.([]interface{})
+		if len(w) != len(g) {
+			return fmt.Sprintf("%s should have %d item(s), got %d", path, len(w), len(g))
+		}
+		for i := range w {
+			if diff := describeDifference(fmt.Sprintf("%s[%d]", path, i), w[i], g[i]); diff != "" {
+				return diff
+			}
+		}
+	default:
+		if !reflect.DeepEqual(want, got) {
+			return fmt.Sprintf("%s has the wrong value", path)
+		}
+	}
+	return ""
+}
+
+// kindOf names the JSON kind of a canonical value.
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a bool"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// canonicalLines splits rendered text into lines without trailing whitespace
+// or trailing blank lines.
+func canonicalLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// fixturePlayer is a record of the players.csv fixture
+type fixturePlayer struct {
+	Name   string
+	Team   string
+	Score  int
+	Active bool
+}
+
+func fixturePlayers() []fixturePlayer {
+	records, err := csv.NewReader(bytes.NewReader(mustFixture("players.csv"))).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("invalid players.csv fixture: %s", err))
+	}
+
+	players := []fixturePlayer{}
+	for _, record := range records[1:] {
+		score, _ := strconv.Atoi(record[2])
+		players = append(players, fixturePlayer{
+			Name:   record[0],
+			Team:   record[1],
+			Score:  score,
+			Active: record[3] == "true",
+		})
+	}
+	return players
+}
+
+// expectedActivePlayers is the csv_records answer.
+func expectedActivePlayers() interface{} {
+	out := map[string]interface{}{}
+	for _, p := range fixturePlayers() {
+		if p.Active {
+			out[p.Name] = map[string]interface{}{
+				"team":  p.Team,
+				"score": float64(p.Score),
+			}
+		}
+	}
+	return out
+}
+
+// expectedRegionGroups is the yaml_transform answer.
+func expectedRegionGroups() interface{} {
+	var config struct {
+		Environments map[string]struct {
+			Region   string `yaml:"region"`
+			Replicas int    `yaml:"replicas"`
+		} `yaml:"environments"`
+	}
+	if err := yaml.Unmarshal(mustFixture("config.yaml"), &config); err != nil {
+		panic(fmt.Sprintf("invalid config.yaml fixture: %s", err))
+	}
+
+	names := make([]string, 0, len(config.Environments))
+	for name := range config.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := map[string]interface{}{}
+	for _, name := range names {
+		env := config.Environments[name]
+		if env.Replicas < 2 {
+			continue
+		}
+		list, _ := out[env.Region].([]interface{})
+		out[env.Region] = append(list, name)
+	}
+	return out
+}
+
+// expectedTeamReport is the template_render answer.
+func expectedTeamReport() string {
+	var b strings.Builder
+	total := 0
+	b.WriteString("Team BLUE report\n")
+	for _, p := range fixturePlayers() {
+		if p.Active && p.Team == "blue" {
+			fmt.Fprintf(&b, "- %s: %d points\n", p.Name, p.Score)
+			total += p.Score
+		}
+	}
+	fmt.Fprintf(&b, "Total: %d\n", total)
+	return b.String()
+}
+
+// expectedTeamSummary is the base64gzip_payload answer.
+func expectedTeamSummary() interface{} {
+	members := map[string]int{}
+	totals := map[string]int{}
+	for _, p := range fixturePlayers() {
+		if p.Active {
+			members[p.Team]++
+			totals[p.Team] += p.Score
+		}
+	}
+
+	out := map[string]interface{}{}
+	for team := range members {
+		out[team] = map[string]interface{}{
+			"members":     float64(members[team]),
+			"total_score": float64(totals[team]),
+		}
+	}
+	return out
+}
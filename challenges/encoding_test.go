@@ -0,0 +1,225 @@
+package challenges
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// base64gzip compresses content as Terraform's base64gzip function does.
+func base64gzip(t *testing.T, content string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatalf("error compressing output: %s", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("error compressing output: %s", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestEncodingValidators(t *testing.T) {
+	const summary = `{"blue":{"members":2,"total_score":230},"green":{"members":1,"total_score":150},"red":{"members":3,"total_score":285}}`
+
+	cases := map[string]struct {
+		challenge string
+		output    func(t *testing.T) string
+		failed    string
+	}{
+		"csv records": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `{"ada":{"score":120,"team":"blue"},"brian":{"score":95,"team":"red"},"dana":{"score":150,"team":"green"},
+					"eli":{"score":60,"team":"red"},"fatima":{"score":110,"team":"blue"},"hana":{"score":130,"team":"red"}}`
+			},
+		},
+		"csv records with string scores": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `{"ada":{"score":"120","team":"blue"},"brian":{"score":"95","team":"red"},"dana":{"score":"150","team":"green"},
+					"eli":{"score":"60","team":"red"},"fatima":{"score":"110","team":"blue"},"hana":{"score":"130","team":"red"}}`
+			},
+			failed: "output.content",
+		},
+		"csv records including inactive players": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `{"ada":{"score":120,"team":"blue"},"brian":{"score":95,"team":"red"},"chen":{"score":80,"team":"blue"},"dana":{"score":150,"team":"green"},
+					"eli":{"score":60,"team":"red"},"fatima":{"score":110,"team":"blue"},"hana":{"score":130,"team":"red"}}`
+			},
+			failed: "output.content",
+		},
+		"csv records as a list": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `[{"name":"ada","score":120,"team":"blue"}]`
+			},
+			failed: "output.content",
+		},
+		"csv records not json": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `{"ada": {"score": 120}`
+			},
+			failed: "output.format",
+		},
+		"csv records with trailing content": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return `{} {}`
+			},
+			failed: "output.format",
+		},
+		"empty output": {
+			challenge: "csv_records",
+			output: func(t *testing.T) string {
+				return "  \n"
+			},
+			failed: "output.present",
+		},
+		"yaml": {
+			challenge: "yaml_transform",
+			output: func(t *testing.T) string {
+				return "\"eu-west-1\":\n- \"qa\"\n- \"staging\"\n\"us-east-1\":\n- \"prod\"\n\"us-west-2\":\n- \"dr\"\n"
+			},
+		},
+		"yaml submitted as json": {
+			challenge: "yaml_transform",
+			output: func(t *testing.T) string {
+				return `{"eu-west-1":["qa","staging"],"us-east-1":["prod"],"us-west-2":["dr"]}`
+			},
+			failed: "output.format",
+		},
+		"yaml out of order": {
+			challenge: "yaml_transform",
+			output: func(t *testing.T) string {
+				return "eu-west-1: [staging, qa]\nus-east-1: [prod]\nus-west-2: [dr]\n"
+			},
+			failed: "output.content",
+		},
+		"yaml including single replicas": {
+			challenge: "yaml_transform",
+			output: func(t *testing.T) string {
+				return "eu-west-1: [dev, qa, staging]\nus-east-1: [prod, sandbox]\nus-west-2: [dr]\n"
+			},
+			failed: "output.content",
+		},
+		"template": {
+			challenge: "template_render",
+			output: func(t *testing.T) string {
+				return "Team BLUE report\r\n- ada: 120 points  \n- fatima: 110 points\nTotal: 230\n\n"
+			},
+		},
+		"template with blank lines": {
+			challenge: "template_render",
+			output: func(t *testing.T) string {
+				return "Team BLUE report\n\n- ada: 120 points\n\n- fatima: 110 points\nTotal: 230"
+			},
+			failed: "output.lines",
+		},
+		"template including inactive members": {
+			challenge: "template_render",
+			output: func(t *testing.T) string {
+				return "Team BLUE report\n- ada: 120 points\n- chen: 80 points\nTotal: 200"
+			},
+			failed: "output.line.3",
+		},
+		"template with the wrong total": {
+			challenge: "template_render",
+			output: func(t *testing.T) string {
+				return "Team BLUE report\n- ada: 120 points\n- fatima: 110 points\nTotal: 310"
+			},
+			failed: "output.line.4",
+		},
+		"base64gzip": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64gzip(t, summary)
+			},
+		},
+		"base64 without gzip": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64.StdEncoding.EncodeToString([]byte(summary))
+			},
+			failed: "output.format",
+		},
+		"plain json": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return summary
+			},
+			failed: "output.format",
+		},
+		"compressed yaml": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64gzip(t, "blue:\n  members: 2\n")
+			},
+			failed: "output.format",
+		},
+		"truncated gzip": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				compressed, _ := base64.StdEncoding.DecodeString(base64gzip(t, summary))
+				return base64.StdEncoding.EncodeToString(compressed[:len(compressed)-8])
+			},
+			failed: "output.format",
+		},
+		"oversized payload": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64gzip(t, summary+strings.Repeat(" ", maxDecompressedOutput))
+			},
+			failed: "output.format",
+		},
+		"payload just under the limit": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64gzip(t, summary+strings.Repeat(" ", maxDecompressedOutput-len(summary)-1))
+			},
+		},
+		"summary counting inactive players": {
+			challenge: "base64gzip_payload",
+			output: func(t *testing.T) string {
+				return base64gzip(t, `{"blue":{"members":3,"total_score":310},"green":{"members":2,"total_score":195},"red":{"members":3,"total_score":285}}`)
+			},
+			failed: "output.content",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &ProofData{EncodedOutput: tc.output(t)}, tc.failed)
+		})
+	}
+}
+
+func TestDescribeDifference(t *testing.T) {
+	want := map[string]interface{}{"a": []interface{}{1.0, "x"}, "b": true}
+	cases := map[string]struct {
+		got      interface{}
+		expected string
+	}{
+		"equal":          {got: map[string]interface{}{"b": true, "a": []interface{}{1.0, "x"}}},
+		"missing key":    {got: map[string]interface{}{"a": []interface{}{1.0, "x"}}, expected: `$ is missing key "b"`},
+		"unexpected key": {got: map[string]interface{}{"a": []interface{}{1.0, "x"}, "b": true, "c": nil}, expected: `$ has unexpected key "c"`},
+		"wrong kind":     {got: map[string]interface{}{"a": []interface{}{"1", "x"}, "b": true}, expected: "$.a[0] should be a number, got a string"},
+		"wrong length":   {got: map[string]interface{}{"a": []interface{}{1.0}, "b": true}, expected: "$.a should have 2 item(s), got 1"},
+		"wrong value":    {got: map[string]interface{}{"a": []interface{}{1.0, "x"}, "b": false}, expected: "$.b has the wrong value"},
+		"not an object":  {got: []interface{}{}, expected: "$ should be an object, got a list"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := describeDifference("$", want, tc.got); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
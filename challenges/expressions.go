@@ -0,0 +1,185 @@
+package challenges
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExpressionProof contains an expression evaluated by the provider
+type ExpressionProof struct {
+	Source    string
+	Expr      hclsyntax.Expression
+	Value     cty.Value
+	Functions []string
+}
+
+// ExpressionTask describes what an expression challenge expects: a result
+// computed from Input by nesting Composition, outermost function first.
+type ExpressionTask struct {
+	Input       string
+	Composition []string
+	Expected    func(input string) string
+}
+
+// ExpressionTasks are the expression challenges that accept expression proof
+var ExpressionTasks = map[string]ExpressionTask{
+	"expression_expert": {
+		Input:       "terraformexpressionsrock",
+		Composition: []string{"base64encode", "sha256"},
+		Expected:    expectedExpressionExpert,
+	},
+	"cryptographic_compute": {
+		Input:       "terraform_ctf_11_2025",
+		Composition: []string{"md5", "sha256"},
+		Expected:    expectedCryptoCompute,
+	},
+}
+
+// ParseExpression parses and evaluates a self-contained expression with
+// ExpressionFunctions. References to variables, resources or other objects
+// are rejected because the provider cannot resolve them.
+func ParseExpression(src string) (*ExpressionProof, hcl.Diagnostics) {
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "expression", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, traversal := range expr.Variables() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Expression is not self-contained",
+			Detail:   fmt.Sprintf("The expression refers to %s. Only literal values and function calls can be evaluated by the provider.", traversalString(traversal)),
+			Subject:  traversal.SourceRange().Ptr(),
+		})
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	proof := &ExpressionProof{
+		Source:    src,
+		Expr:      expr,
+		Functions: functionsCalled(expr),
+	}
+
+	value, valDiags := expr.Value(&hcl.EvalContext{Functions: ExpressionFunctions()})
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return nil, diags
+	}
+	proof.Value = value
+
+	return proof, diags
+}
+
+// functionsCalled returns the distinct functions called by an expression, in
+// the order they appear.
+func functionsCalled(expr hclsyntax.Expression) []string {
+	names := []string{}
+	seen := make(map[string]bool)
+	hclsyntax.VisitAll(expr, func(node hclsyntax.Node) hcl.Diagnostics {
+		if call, ok := node.(*hclsyntax.FunctionCallExpr); ok && !seen[call.Name] {
+			seen[call.Name] = true
+			names = append(names, call.Name)
+		}
+		return nil
+	})
+	return names
+}
+
+// compositionInput returns the argument given to the innermost function of
+// chain when the whole expression, parentheses aside, is that chain of
+// calls, outermost function first. Each function must take exactly one
+// argument, so the chain cannot be buried inside a larger expression.
+func compositionInput(expr hclsyntax.Expression, chain []string) (hclsyntax.Expression, bool) {
+	expr = unwrapParentheses(expr)
+	if len(chain) == 0 {
+		return expr, true
+	}
+
+	call, ok := expr.(*hclsyntax.FunctionCallExpr)
+	if !ok || call.Name != chain[0] || len(call.Args) != 1 || call.ExpandFinal {
+		return nil, false
+	}
+	return compositionInput(call.Args[0], chain[1:])
+}
+
+// unwrapParentheses strips any parentheses around an expression.
+func unwrapParentheses(expr hclsyntax.Expression) hclsyntax.Expression {
+	for {
+		paren, ok := expr.(*hclsyntax.ParenthesesExpr)
+		if !ok {
+			return expr
+		}
+		expr = paren.Expression
+	}
+}
+
+// compositionText renders a function chain as nested calls, e.g.
+// base64encode(sha256(...)).
+func compositionText(chain []string) string {
+	return strings.Join(chain, "(") + "(...)" + strings.Repeat(")", len(chain)-1)
+}
+
+// validateExpressionTask checks an expression against the task registered
+// for a challenge. Neither the expected value nor intermediate results are
+// reported, so a failing check cannot be turned into a copied constant.
+func validateExpressionTask(challengeID, flag, message string) StructuredValidatorFunc {
+	return func(proof *ProofData) ValidationResult {
+		result := ValidationResult{
+			Success: false,
+			Details: []string{},
+		}
+
+		task := ExpressionTasks[challengeID]
+		expr := proof.Expression
+
+		input, ok := compositionInput(expr.Expr, task.Composition)
+		if !ok {
+			result.fail("expression.composition", fmt.Sprintf("Expression is not %s", compositionText(task.Composition)),
+				fmt.Sprintf("Functions used: %s. The whole expression must be the nested calls, outermost first, with the task input innermost", functionList(expr.Functions)))
+			return result
+		}
+		result.pass("expression.composition", fmt.Sprintf("Expression is %s", compositionText(task.Composition)))
+
+		// The innermost argument may be computed, but it must come to the
+		// task input: anything else would let a copied constant through
+		inputValue, diags := input.Value(&hcl.EvalContext{Functions: ExpressionFunctions()})
+		if diags.HasErrors() || inputValue.IsNull() || !inputValue.IsKnown() || inputValue.Type() != cty.String || inputValue.AsString() != task.Input {
+			result.fail("expression.input", fmt.Sprintf("The innermost call is not applied to \"%s\"", task.Input),
+				fmt.Sprintf("Pass the string \"%s\" to %s", task.Input, task.Composition[len(task.Composition)-1]))
+			return result
+		}
+		result.pass("expression.input", fmt.Sprintf("The innermost call is applied to \"%s\"", task.Input))
+
+		if expr.Value.IsNull() || !expr.Value.IsKnown() || expr.Value.Type() != cty.String {
+			result.fail("expression.type", fmt.Sprintf("Expression evaluates to %s, not a string", expr.Value.Type().FriendlyName()), "")
+			return result
+		}
+		result.pass("expression.type", "Expression evaluates to a string")
+
+		if expr.Value.AsString() != task.Expected(task.Input) {
+			result.fail("expression.result", "Expression result does not match the expected value",
+				fmt.Sprintf("Apply the functions to the string \"%s\", innermost first", task.Input))
+			return result
+		}
+		result.pass("expression.result", "Expression result matches")
+
+		result.Success = true
+		result.Flag = flag
+		result.Message = message
+		return result
+	}
+}
+
+// functionList renders the functions used by an expression for messages.
+func functionList(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
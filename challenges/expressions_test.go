@@ -0,0 +1,174 @@
+package challenges
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestParseExpression(t *testing.T) {
+	cases := map[string]struct {
+		src       string
+		expected  string
+		functions string
+		err       string
+	}{
+		"literal":         {src: `"terraform"`, expected: `"terraform"`},
+		"arithmetic":      {src: `(1 + 2) * 3`, expected: `9`},
+		"nested calls":    {src: `upper(trimspace("  ctf  "))`, expected: `"CTF"`, functions: "upper trimspace"},
+		"repeated calls":  {src: `join("-", [lower("A"), lower("B")])`, expected: `"a-b"`, functions: "join lower"},
+		"for expression":  {src: `[for s in ["a", "b"] : upper(s)]`, expected: `["A","B"]`, functions: "upper"},
+		"template":        {src: `"${md5("abc")}!"`, expected: `"900150983cd24fb0d6963f7d28e17f72!"`, functions: "md5"},
+		"syntax error":    {src: `sha256("abc"`, err: "Unterminated function call"},
+		"variable":        {src: `sha256(var.input)`, err: "The expression refers to var.input."},
+		"resource":        {src: `ctfchallenge_flag_validator.basics.flag`, err: "The expression refers to ctfchallenge_flag_validator.basics.flag."},
+		"unknown func":    {src: `file("flag.txt")`, err: `There is no function named "file".`},
+		"wrong arg type":  {src: `sha256(["abc"])`, err: "Invalid value for \"str\" parameter: string required."},
+		"too many args":   {src: `md5("a", "b")`, err: "Function \"md5\" expects only 1 argument(s)."},
+		"operand type":    {src: `"abc" + 1`, err: "Unsuitable value for left operand: a number is required."},
+		"function errors": {src: `base64decode("not base64!")`, err: "failed to decode base64 data"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			proof, diags := ParseExpression(tc.src)
+			if tc.err != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Error(), tc.err) {
+					t.Fatalf("\n\nexpected error:\n\n%q\n\ngot:\n\n%q\n\n", tc.err, diags.Error())
+				}
+				if proof != nil {
+					t.Fatal("expected no proof for an invalid expression")
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("error parsing expression: %s", diags.Error())
+			}
+
+			value, err := ctyjson.SimpleJSONValue{Value: proof.Value}.MarshalJSON()
+			if err != nil {
+				t.Fatalf("error encoding value: %s", err)
+			}
+			if string(value) != tc.expected || strings.Join(proof.Functions, " ") != tc.functions || proof.Source != tc.src {
+				t.Fatalf("\n\nexpected:\n\n%s %q\n\ngot:\n\n%s %q\n\n", tc.expected, tc.functions, value, proof.Functions)
+			}
+		})
+	}
+}
+
+func TestCompositionInput(t *testing.T) {
+	chain := []string{"base64encode", "sha256"}
+
+	cases := map[string]struct {
+		src      string
+		chain    []string
+		expected string
+	}{
+		"composition":              {src: `base64encode(sha256("input"))`, expected: `"input"`},
+		"parentheses":              {src: `(base64encode((sha256(("input")))))`, expected: `"input"`},
+		"computed input":           {src: `base64encode(sha256(lower("INPUT")))`, expected: `lower("INPUT")`},
+		"empty chain":              {src: `"input"`, chain: []string{}, expected: `"input"`},
+		"wrong order":              {src: `sha256(base64encode("input"))`},
+		"missing a call":           {src: `base64encode("input")`},
+		"inside a larger":          {src: `upper(base64encode(sha256("input")))`},
+		"inside a template":        {src: `"${base64encode(sha256("input"))}"`},
+		"extra argument":           {src: `base64encode(sha256("input", "salt"))`},
+		"expanded final argument":  {src: `base64encode(sha256(["input"]...))`},
+		"similarly named function": {src: `base64sha256("input")`},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "expression", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("error parsing expression: %s", diags.Error())
+			}
+			want := chain
+			if tc.chain != nil {
+				want = tc.chain
+			}
+
+			input, ok := compositionInput(expr, want)
+			if ok != (tc.expected != "") {
+				t.Fatalf("expected ok=%t, got %t", tc.expected != "", ok)
+			}
+			if !ok {
+				return
+			}
+			if actual := string(input.Range().SliceBytes([]byte(tc.src))); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExpressionValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		src       string
+		failed    string
+	}{
+		"expression expert": {
+			challenge: "expression_expert",
+			src:       `base64encode(sha256("terraformexpressionsrock"))`,
+		},
+		"expression expert with a computed input": {
+			challenge: "expression_expert",
+			src:       `base64encode(sha256(join("", ["terraform", "expressions", "rock"])))`,
+		},
+		"expression expert as a constant": {
+			challenge: "expression_expert",
+			src:       `"ZmI3ZTFlNmE3ZDQ1ZGI3OTk1ZWI4MzQ3NWJhNzE1ODU4NDk3NzUzOWYzMTQ3MGFmMDk2NTA0YjMzMWI0ZWJhYQ=="`,
+			failed:    "expression.composition",
+		},
+		"expression expert with base64sha256": {
+			challenge: "expression_expert",
+			src:       `base64sha256("terraformexpressionsrock")`,
+			failed:    "expression.composition",
+		},
+		"expression expert in the wrong order": {
+			challenge: "expression_expert",
+			src:       `sha256(base64encode("terraformexpressionsrock"))`,
+			failed:    "expression.composition",
+		},
+		"expression expert with another input": {
+			challenge: "expression_expert",
+			src:       `base64encode(sha256("terraform"))`,
+			failed:    "expression.input",
+		},
+		"cryptographic compute": {
+			challenge: "cryptographic_compute",
+			src:       `md5(sha256("terraform_ctf_11_2025"))`,
+		},
+		"cryptographic compute with the expert composition": {
+			challenge: "cryptographic_compute",
+			src:       `base64encode(sha256("terraform_ctf_11_2025"))`,
+			failed:    "expression.composition",
+		},
+		"cryptographic compute with a numeric input": {
+			challenge: "cryptographic_compute",
+			src:       `md5(sha256(2025))`,
+			failed:    "expression.input",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expr, diags := ParseExpression(tc.src)
+			if diags.HasErrors() {
+				t.Fatalf("error parsing expression: %s", diags.Error())
+			}
+			result := expectValidation(t, tc.challenge, &ProofData{Expression: expr}, tc.failed)
+			// Failures never give away the answer
+			expected := ExpressionTasks[tc.challenge].Expected(ExpressionTasks[tc.challenge].Input)
+			for _, detail := range result.Details {
+				if strings.Contains(detail, expected) {
+					t.Fatalf("result detail %q reveals the expected value", detail)
+				}
+			}
+		})
+	}
+}
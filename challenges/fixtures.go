@@ -0,0 +1,46 @@
+package challenges
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+)
+
+// fixtures are the input files for the encoding challenges. They are embedded
+// so that every player transforms exactly the same data.
+//
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fixture returns the content of an embedded fixture file.
+func Fixture(name string) ([]byte, bool) {
+	content, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		return nil, false
+	}
+	return content, true
+}
+
+// FixtureNames returns the names of the embedded fixture files, sorted.
+func FixtureNames() []string {
+	entries, err := fs.ReadDir(fixtures, "fixtures")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mustFixture returns a fixture the challenges depend on.
+func mustFixture(name string) []byte {
+	content, ok := Fixture(name)
+	if !ok {
+		panic("missing fixture " + name)
+	}
+	return content
+}
@@ -0,0 +1,73 @@
+package challenges
+
+import (
+	"strings"
+	"testing"
+
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestExpressionFunctions(t *testing.T) {
+	cases := map[string]struct {
+		src      string
+		expected string
+		err      string
+	}{
+		"md5":                      {src: `md5("abc")`, expected: `"900150983cd24fb0d6963f7d28e17f72"`},
+		"sha1":                     {src: `sha1("abc")`, expected: `"a9993e364706816aba3e25717850c26c9cd0d89d"`},
+		"sha256":                   {src: `sha256("abc")`, expected: `"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"`},
+		"base64sha256":             {src: `base64sha256("abc")`, expected: `"ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0="`},
+		"base64 round trip":        {src: `base64decode(base64encode("ctf ✓"))`, expected: `"ctf ✓"`},
+		"base64decode invalid":     {src: `base64decode("%%%")`, err: "failed to decode base64 data"},
+		"base64decode not utf-8":   {src: `base64decode("/w==")`, err: "the result of decoding the provided string is not valid UTF-8"},
+		"length of a string":       {src: `length("✓✓")`, expected: `2`},
+		"length of a list":         {src: `length([1, 2, 3])`, expected: `3`},
+		"length of an object":      {src: `length({ a = 1, b = 2 })`, expected: `2`},
+		"length of a number":       {src: `length(5)`, err: "argument must be a string, a collection type, or a structural type"},
+		"alltrue":                  {src: `alltrue([true, 1 == 1])`, expected: `true`},
+		"alltrue with null":        {src: `alltrue([true, null])`, expected: `false`},
+		"alltrue of nothing":       {src: `alltrue([])`, expected: `true`},
+		"anytrue":                  {src: `anytrue([false, null, true])`, expected: `true`},
+		"anytrue of strings":       {src: `anytrue(["yes"])`, err: "a bool is required"},
+		"startswith":               {src: `startswith("flag{x}", "flag{")`, expected: `true`},
+		"endswith":                 {src: `endswith("flag{x}", "{")`, expected: `false`},
+		"urlencode":                {src: `urlencode("a b&c")`, expected: `"a+b%26c"`},
+		"tostring":                 {src: `tostring(42)`, expected: `"42"`},
+		"tonumber":                 {src: `tonumber("42")`, expected: `42`},
+		"tonumber of a word":       {src: `tonumber("forty")`, err: "cannot convert string to number"},
+		"tobool of a number":       {src: `tobool(1)`, err: "cannot convert number to bool"},
+		"tolist":                   {src: `tolist(["a", "b"])`, expected: `["a","b"]`},
+		"toset":                    {src: `toset(["b", "a", "b"])`, expected: `["a","b"]`},
+		"tomap":                    {src: `tomap({ a = "1", b = 2 })`, expected: `{"a":"1","b":"2"}`},
+		"tostring of a list":       {src: `tostring(["a"])`, err: "cannot convert tuple to string"},
+		"stdlib":                   {src: `join(",", sort(distinct(split(",", "b,a,b"))))`, expected: `"a,b"`},
+		"try":                      {src: `try(tonumber("x"), 0)`, expected: `0`},
+		"can":                      {src: `can(regex("^flag", "flag{x}"))`, expected: `true`},
+		"not a terraform function": {src: `file("flag.txt")`, err: `There is no function named "file".`},
+		"impure function":          {src: `timestamp()`, err: `There is no function named "timestamp".`},
+		"too few arguments":        {src: `startswith("flag")`, err: `Function "startswith" expects 2 argument(s).`},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			value, diags := ParseValue(tc.src)
+			if tc.err != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Error(), tc.err) {
+					t.Fatalf("\n\nexpected error:\n\n%q\n\ngot:\n\n%q\n\n", tc.err, diags.Error())
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("error evaluating %s: %s", tc.src, diags.Error())
+			}
+
+			actual, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+			if err != nil {
+				t.Fatalf("error encoding value: %s", err)
+			}
+			if string(actual) != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
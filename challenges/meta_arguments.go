@@ -18,9 +18,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Count Master",
 		Description: "Master the 'count' meta-argument by creating exactly 3 puzzle boxes with sequential keys",
 		Points:      150,
+		Flag:        "flag{c0unt_m3t4_4rgum3nt_m4st3r}",
 		Difficulty:  "intermediate",
 		Category:    "meta-arguments",
-		Validator:   validateCountChallenge,
+		Validator:   NewLegacyValidator(validateCountChallenge, "count_value", "resource_ids", "uses_count_index"),
 	}
 
 	// For_each Meta-Argument Challenge
@@ -29,9 +30,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "For Each Wizard",
 		Description: "Use 'for_each' to create puzzle boxes for all difficulty levels: beginner, intermediate, advanced",
 		Points:      200,
+		Flag:        "flag{f0r_34ch_l00p_m4g1c}",
 		Difficulty:  "intermediate",
 		Category:    "meta-arguments",
-		Validator:   validateForEachChallenge,
+		Validator:   NewLegacyValidator(validateForEachChallenge, "foreach_type", "difficulties", "uses_each"),
 	}
 
 	// Depends_on Meta-Argument Challenge
@@ -40,9 +42,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Dependency Chain Master",
 		Description: "Create a dependency chain using 'depends_on' with at least 3 resources in sequence",
 		Points:      175,
+		Flag:        "flag{d3p3nd3ncy_ch41n_m4st3r}",
 		Difficulty:  "intermediate",
 		Category:    "meta-arguments",
-		Validator:   validateDependsOnChallenge,
+		Validator:   NewLegacyValidator(validateDependsOnChallenge, "dependency_chain_length", "uses_depends_on", "resource_chain", "dependency_order"),
 	}
 
 	// Lifecycle Meta-Argument Challenge
@@ -51,9 +54,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Lifecycle Expert",
 		Description: "Use lifecycle rules to demonstrate create_before_destroy and ignore_changes",
 		Points:      225,
+		Flag:        "flag{l1f3cycl3_rul3s_3xp3rt}",
 		Difficulty:  "advanced",
 		Category:    "meta-arguments",
-		Validator:   validateLifecycleChallenge,
+		Validator:   NewLegacyValidator(validateLifecycleChallenge, "uses_create_before_destroy", "ignore_changes", "lifecycle_rules_count", "lifecycle_justification"),
 	}
 
 	// Combined Meta-Arguments Challenge
@@ -62,9 +66,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Meta-Argument Grandmaster",
 		Description: "Combine count, for_each, depends_on, and lifecycle in a single configuration",
 		Points:      300,
+		Flag:        "flag{m3t4_4rgum3nt_gr4ndm4st3r_ultimate}",
 		Difficulty:  "advanced",
 		Category:    "meta-arguments",
-		Validator:   validateMetaGrandmasterChallenge,
+		Validator:   NewLegacyValidator(validateMetaGrandmasterChallenge, "meta_arguments_used", "total_resources", "config_lines", "architecture_description"),
 	}
 
 	// Dynamic Blocks Challenge
@@ -73,9 +78,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Dynamic Block Architect",
 		Description: "Use dynamic blocks to generate configuration based on variable inputs",
 		Points:      180,
+		Flag:        "flag{dyn4m1c_bl0ck_4rch1t3ct}",
 		Difficulty:  "intermediate",
 		Category:    "meta-arguments",
-		Validator:   validateDynamicBlocksChallenge,
+		Validator:   NewLegacyValidator(validateDynamicBlocksChallenge, "uses_dynamic_blocks", "dynamic_iterations"),
 	}
 
 	// Locals and Count Challenge
@@ -84,9 +90,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Locals + Count Combo",
 		Description: "Use locals with count.index to create resources with computed names",
 		Points:      160,
+		Flag:        "flag{l0c4ls_c0unt_c0mb0_m4st3r}",
 		Difficulty:  "intermediate",
 		Category:    "meta-arguments",
-		Validator:   validateLocalsCountChallenge,
+		Validator:   NewLegacyValidator(validateLocalsCountChallenge, "uses_locals", "count_value", "resource_names", "uses_count_index_in_locals"),
 	}
 
 	// Conditional Creation Challenge
@@ -95,9 +102,10 @@ func registerMetaArgumentChallenges() {
 		Name:        "Conditional Creation Master",
 		Description: "Use count = var.condition ? 1 : 0 pattern to conditionally create resources",
 		Points:      140,
+		Flag:        "flag{c0nd1t10n4l_cr34t10n_m4st3r}",
 		Difficulty:  "beginner",
 		Category:    "meta-arguments",
-		Validator:   validateConditionalChallenge,
+		Validator:   NewLegacyValidator(validateConditionalChallenge, "uses_conditional_count", "uses_variable_condition", "condition_true_result", "condition_false_result", "conditional_pattern"),
 	}
 }
 
@@ -405,80 +413,3 @@ func validateConditionalChallenge(proof map[string]interface{}) (bool, string, e
 
 	return true, "flag{c0nd1t10n4l_cr34t10n_m4st3r}", nil
 }
-
-// validateMetaArgumentStructure validates meta-argument challenges using structured proof
-func validateMetaArgumentStructure(c *Challenge, proof *ProofData) ValidationResult {
-	result := ValidationResult{
-		Success: false,
-		Details: []string{},
-	}
-
-	switch c.ID {
-	case "count_master":
-		return validateCountStructure(proof)
-	case "foreach_wizard":
-		return validateForEachStructure(proof)
-	case "dependency_chain":
-		return validateDependsOnStructure(proof)
-	case "lifecycle_expert":
-		return validateLifecycleStructure(proof)
-	case "meta_grandmaster":
-		return validateMetaGrandmasterStructure(proof)
-	case "dynamic_block_architect":
-		return validateDynamicBlocksStructure(proof)
-	case "locals_count_combo":
-		return validateLocalsCountStructure(proof)
-	case "conditional_resources":
-		return validateConditionalStructure(proof)
-	default:
-		// Fall back to legacy validator
-		success, flag, err := c.Validator(proof.Manual)
-		result.Success = success
-		result.Flag = flag
-		if err != nil {
-			result.Message = err.Error()
-		}
-	}
-
-	return result
-}
-
-// Add stub implementations for structure validators
-func validateCountStructure(proof *ProofData) ValidationResult {
-	// For now, fall back to manual validation
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateForEachStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateDependsOnStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateLifecycleStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateMetaGrandmasterStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateDynamicBlocksStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateLocalsCountStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-func validateConditionalStructure(proof *ProofData) ValidationResult {
-	return ValidationResult{Message: "Use proof_of_work for this challenge"}
-}
-
-// validateModuleStructure validates module challenges
-func validateModuleStructure(c *Challenge, proof *ProofData) ValidationResult {
-	// Implemented in validation_conditions.go
-	return validateModuleContractStructure(proof)
-}
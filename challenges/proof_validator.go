@@ -0,0 +1,277 @@
+package challenges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ProofKind identifies the shape of proof submitted to a challenge.
+type ProofKind string
+
+const (
+	ProofKindManual     ProofKind = "manual"
+	ProofKindResource   ProofKind = "resource"
+	ProofKindDataSource ProofKind = "data_source"
+	ProofKindModule     ProofKind = "module"
+	ProofKindCheck      ProofKind = "check"
+	ProofKindRefactor   ProofKind = "refactor"
+	ProofKindTest       ProofKind = "test"
+	ProofKindVariables  ProofKind = "variables"
+	ProofKindProvider   ProofKind = "provider"
+	ProofKindExpression ProofKind = "expression"
+	ProofKindEncoded    ProofKind = "encoded"
+	ProofKindFlag       ProofKind = "flag"
+	ProofKindDrift      ProofKind = "drift"
+	ProofKindFlaky      ProofKind = "flaky"
+	ProofKindChain      ProofKind = "chain"
+)
+
+// Validator checks submitted proof for a challenge.
+//
+// Validate returns a non-nil error only when validation could not run at all
+// (for example because ctx was cancelled). A proof that simply does not meet
+// the challenge requirements is reported through an unsuccessful
+// ValidationResult.
+type Validator interface {
+	Validate(ctx context.Context, proof *ProofData) (ValidationResult, error)
+	// SupportedProofKinds lists the proof shapes this validator understands.
+	SupportedProofKinds() []ProofKind
+	// ExpectedProofKeys lists the proof_of_work keys read by the validator.
+	ExpectedProofKeys() []string
+}
+
+// LegacyValidatorFunc is the original map-based validator signature.
+type LegacyValidatorFunc func(input map[string]interface{}) (bool, string, error)
+
+// StructuredValidatorFunc validates resource, data source or module proof.
+type StructuredValidatorFunc func(proof *ProofData) ValidationResult
+
+// Kinds returns the proof kinds present in the proof data, structured kinds
+// first. Manual proof comes last whenever proof_of_work is set, so a
+// combined validator falls back to its legacy validator when none of its
+// structured validators accept the structured proof.
+func (p *ProofData) Kinds() []ProofKind {
+	kinds := []ProofKind{}
+	if len(p.Resources) > 0 {
+		kinds = append(kinds, ProofKindResource)
+	}
+	if len(p.DataSources) > 0 {
+		kinds = append(kinds, ProofKindDataSource)
+	}
+	if p.Module != nil {
+		kinds = append(kinds, ProofKindModule)
+	}
+	if len(p.CheckBlocks) > 0 {
+		kinds = append(kinds, ProofKindCheck)
+	}
+	if p.Refactor != nil {
+		kinds = append(kinds, ProofKindRefactor)
+	}
+	if len(p.TestFiles) > 0 {
+		kinds = append(kinds, ProofKindTest)
+	}
+	if len(p.Variables) > 0 {
+		kinds = append(kinds, ProofKindVariables)
+	}
+	if p.Providers != nil {
+		kinds = append(kinds, ProofKindProvider)
+	}
+	if p.Expression != nil {
+		kinds = append(kinds, ProofKindExpression)
+	}
+	if p.EncodedOutput != "" {
+		kinds = append(kinds, ProofKindEncoded)
+	}
+	if p.RecoveredFlag != "" {
+		kinds = append(kinds, ProofKindFlag)
+	}
+	if p.Drift != nil {
+		kinds = append(kinds, ProofKindDrift)
+	}
+	if p.Flaky != nil {
+		kinds = append(kinds, ProofKindFlaky)
+	}
+	if p.Chain != nil {
+		kinds = append(kinds, ProofKindChain)
+	}
+	if len(kinds) == 0 || len(p.Manual) > 0 {
+		kinds = append(kinds, ProofKindManual)
+	}
+	return kinds
+}
+
+// SupportsProof reports whether v accepts any of the proof kinds present in proof.
+func SupportsProof(v Validator, proof *ProofData) bool {
+	for _, kind := range proof.Kinds() {
+		for _, supported := range v.SupportedProofKinds() {
+			if kind == supported {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// legacyValidator adapts a LegacyValidatorFunc to the Validator interface.
+type legacyValidator struct {
+	fn   LegacyValidatorFunc
+	keys []string
+}
+
+// NewLegacyValidator wraps a map-based validator that reads the given
+// proof_of_work keys.
+func NewLegacyValidator(fn LegacyValidatorFunc, keys ...string) Validator {
+	return &legacyValidator{fn: fn, keys: keys}
+}
+
+func (v *legacyValidator) Validate(ctx context.Context, proof *ProofData) (ValidationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ValidationResult{}, err
+	}
+
+	success, flag, err := v.fn(proof.Manual)
+	result := ValidationResult{
+		Success: success,
+		Flag:    flag,
+		Details: []string{},
+	}
+
+	// Legacy validators only report a single outcome, so derive one check per
+	// expected key plus an overall requirements check. A key missing from a
+	// proof that passed was optional, so it is skipped rather than failed.
+	for _, key := range v.keys {
+		if _, ok := proof.Manual[key]; ok {
+			result.pass("proof_of_work."+key, fmt.Sprintf("Provided '%s'", key))
+		} else if success && err == nil {
+			result.AddCheck(Check{
+				ID:      "proof_of_work." + key,
+				Status:  CheckSkip,
+				Message: fmt.Sprintf("Optional '%s' not provided", key),
+			})
+		} else {
+			result.AddCheck(Check{
+				ID:      "proof_of_work." + key,
+				Status:  CheckFail,
+				Message: fmt.Sprintf("Missing '%s'", key),
+				Hint:    fmt.Sprintf("Add '%s' to proof_of_work", key),
+			})
+		}
+	}
+
+	if err != nil {
+		result.Message = err.Error()
+		result.AddCheck(Check{ID: "requirements", Status: CheckFail, Message: err.Error()})
+	} else if success {
+		result.pass("requirements", "Challenge requirements met")
+	} else {
+		result.AddCheck(Check{ID: "requirements", Status: CheckFail, Message: "Challenge requirements not met"})
+	}
+	return result, nil
+}
+
+func (v *legacyValidator) SupportedProofKinds() []ProofKind {
+	return []ProofKind{ProofKindManual}
+}
+
+func (v *legacyValidator) ExpectedProofKeys() []string {
+	return v.keys
+}
+
+// structuredValidator adapts a StructuredValidatorFunc to the Validator interface.
+type structuredValidator struct {
+	fn    StructuredValidatorFunc
+	kinds []ProofKind
+}
+
+// NewStructuredValidator wraps a structured validator accepting the given proof kinds.
+func NewStructuredValidator(fn StructuredValidatorFunc, kinds ...ProofKind) Validator {
+	return &structuredValidator{fn: fn, kinds: kinds}
+}
+
+func (v *structuredValidator) Validate(ctx context.Context, proof *ProofData) (ValidationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ValidationResult{}, err
+	}
+	return v.fn(proof), nil
+}
+
+func (v *structuredValidator) SupportedProofKinds() []ProofKind {
+	return v.kinds
+}
+
+func (v *structuredValidator) ExpectedProofKeys() []string {
+	return nil
+}
+
+// compositeValidator dispatches to the first validator supporting the proof.
+type compositeValidator struct {
+	validators []Validator
+}
+
+// CombineValidators returns a Validator that delegates to the first of vs
+// that supports the submitted proof kind.
+func CombineValidators(vs ...Validator) Validator {
+	return &compositeValidator{validators: vs}
+}
+
+func (v *compositeValidator) Validate(ctx context.Context, proof *ProofData) (ValidationResult, error) {
+	for _, candidate := range v.validators {
+		if SupportsProof(candidate, proof) {
+			return candidate.Validate(ctx, proof)
+		}
+	}
+	return unsupportedProofResult(v, proof), nil
+}
+
+func (v *compositeValidator) SupportedProofKinds() []ProofKind {
+	seen := make(map[ProofKind]bool)
+	kinds := []ProofKind{}
+	for _, candidate := range v.validators {
+		for _, kind := range candidate.SupportedProofKinds() {
+			if !seen[kind] {
+				seen[kind] = true
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	return kinds
+}
+
+func (v *compositeValidator) ExpectedProofKeys() []string {
+	keys := []string{}
+	for _, candidate := range v.validators {
+		keys = append(keys, candidate.ExpectedProofKeys()...)
+	}
+	return keys
+}
+
+func unsupportedProofResult(v Validator, proof *ProofData) ValidationResult {
+	submitted := []string{}
+	for _, kind := range proof.Kinds() {
+		submitted = append(submitted, string(kind))
+	}
+	supported := []string{}
+	for _, kind := range v.SupportedProofKinds() {
+		supported = append(supported, string(kind))
+	}
+
+	result := ValidationResult{
+		Success: false,
+		Message: fmt.Sprintf("This challenge does not accept %s proof (supported: %s)",
+			strings.Join(submitted, ", "), strings.Join(supported, ", ")),
+		Details: []string{},
+	}
+	check := Check{
+		ID:       "proof.kind",
+		Status:   CheckFail,
+		Expected: strings.Join(supported, ", "),
+		Actual:   strings.Join(submitted, ", "),
+		Message:  "Proof kind is not supported by this challenge",
+	}
+	if keys := v.ExpectedProofKeys(); len(keys) > 0 {
+		check.Hint = fmt.Sprintf("Expected proof_of_work keys: %s", strings.Join(keys, ", "))
+	}
+	result.AddCheck(check)
+	return result
+}
@@ -0,0 +1,126 @@
+package challenges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestProofKinds(t *testing.T) {
+	cases := map[string]struct {
+		proof    ProofData
+		expected []ProofKind
+	}{
+		"nothing":             {expected: []ProofKind{ProofKindManual}},
+		"proof_of_work":       {proof: ProofData{Manual: map[string]interface{}{"dependencies": "a,b,c"}}, expected: []ProofKind{ProofKindManual}},
+		"empty proof_of_work": {proof: ProofData{Manual: map[string]interface{}{}}, expected: []ProofKind{ProofKindManual}},
+		"resource":            {proof: ProofData{Resources: []ResourceProof{{}}}, expected: []ProofKind{ProofKindResource}},
+		"resource and proof_of_work": {
+			proof:    ProofData{Resources: []ResourceProof{{}}, Manual: map[string]interface{}{"dependencies": "a,b,c"}},
+			expected: []ProofKind{ProofKindResource, ProofKindManual},
+		},
+		"encoded and data source": {
+			proof:    ProofData{DataSources: []DataSourceProof{{}}, EncodedOutput: "[]"},
+			expected: []ProofKind{ProofKindDataSource, ProofKindEncoded},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := tc.proof.Kinds(); fmt.Sprint(actual) != fmt.Sprint(tc.expected) {
+				t.Fatalf("\n\nexpected:\n\n%v\n\ngot:\n\n%v\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCombineValidatorsFallback(t *testing.T) {
+	resource := []ResourceProof{{ResourceType: "null_resource", ResourceName: "first"}}
+
+	cases := map[string]struct {
+		challenge string
+		proof     ProofData
+		failed    string
+	}{
+		"legacy challenge with resource proof": {
+			challenge: "terraform_basics",
+			proof:     ProofData{Resources: resource},
+			failed:    "proof.kind",
+		},
+		"legacy challenge with resource proof and proof_of_work": {
+			challenge: "terraform_basics",
+			proof:     ProofData{Resources: resource, Manual: map[string]interface{}{"dependencies": "a,b,c"}},
+		},
+		"structured proof the challenge does not accept": {
+			challenge: "data_validator",
+			proof:     ProofData{Resources: resource},
+			failed:    "proof.kind",
+		},
+		"structured proof falling back to proof_of_work": {
+			challenge: "data_validator",
+			proof:     ProofData{Resources: resource, Manual: map[string]interface{}{"uses_data_source": "true"}},
+			failed:    "proof_of_work.uses_postcondition",
+		},
+		"structured proof preferred over proof_of_work": {
+			challenge: "precondition_guardian",
+			proof:     ProofData{Resources: resource, Manual: map[string]interface{}{"uses_precondition": "true"}},
+			failed:    "precondition.present",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &tc.proof, tc.failed)
+		})
+	}
+}
+
+func TestExpectedProofKeys(t *testing.T) {
+	for _, id := range GetAllChallengeIDs() {
+		challenge := Challenges[id]
+		t.Run(id, func(t *testing.T) {
+			for _, kind := range challenge.Validator.SupportedProofKinds() {
+				if kind == ProofKindManual && len(challenge.Validator.ExpectedProofKeys()) == 0 {
+					t.Fatalf("%s accepts proof_of_work but lists no proof_of_work keys", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLegacyValidatorChecks(t *testing.T) {
+	// requireName passes with a name, whether or not a nickname is given
+	requireName := func(input map[string]interface{}) (bool, string, error) {
+		if _, ok := input["name"]; !ok {
+			return false, "", fmt.Errorf("missing 'name'")
+		}
+		return true, "flag{legacy}", nil
+	}
+
+	cases := map[string]struct {
+		manual   map[string]interface{}
+		expected string
+	}{
+		"every key":        {manual: map[string]interface{}{"name": "a", "nickname": "b"}, expected: "proof_of_work.name=pass proof_of_work.nickname=pass requirements=pass"},
+		"optional missing": {manual: map[string]interface{}{"name": "a"}, expected: "proof_of_work.name=pass proof_of_work.nickname=skip requirements=pass"},
+		"required missing": {manual: map[string]interface{}{"nickname": "b"}, expected: "proof_of_work.name=fail proof_of_work.nickname=pass requirements=fail"},
+		"nothing":          {manual: map[string]interface{}{}, expected: "proof_of_work.name=fail proof_of_work.nickname=fail requirements=fail"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result, err := NewLegacyValidator(requireName, "name", "nickname").Validate(context.Background(), &ProofData{Manual: tc.manual})
+			if err != nil {
+				t.Fatalf("error validating proof: %s", err)
+			}
+			checks := []string{}
+			for _, check := range result.Checks {
+				checks = append(checks, fmt.Sprintf("%s=%s", check.ID, check.Status))
+			}
+			if actual := strings.Join(checks, " "); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func init() {
+	registerProviderChallenges()
+}
+
+func registerProviderChallenges() {
+	// Provider Aliases
+	Challenges["provider_alias"] = &Challenge{
+		ID:          "provider_alias",
+		Name:        "Double Agent",
+		Description: "Configure 2 ctfchallenge provider instances, one with an alias and a matching instance_name, and create a resource with each",
+		Points:      150,
+		Flag:        "flag{d0ubl3_4g3nt_4l14s}",
+		Difficulty:  "beginner",
+		Category:    "providers",
+		Validator:   NewStructuredValidator(validateProviderAliasStructure, ProofKindProvider),
+	}
+
+	// Multiple Players
+	Challenges["multi_player"] = &Challenge{
+		ID:          "multi_player",
+		Name:        "Split Personality",
+		Description: "Create resources as 2 different players from aliased provider instances with different player_name settings",
+		Points:      175,
+		Flag:        "flag{spl1t_p3rs0n4l1ty_pl4y3rs}",
+		Difficulty:  "intermediate",
+		Category:    "providers",
+		Validator:   NewStructuredValidator(validateMultiPlayerStructure, ProofKindProvider),
+	}
+
+	// Passing Providers to Modules
+	Challenges["module_providers"] = &Challenge{
+		ID:          "module_providers",
+		Name:        "Provider Courier",
+		Description: "Pass an aliased provider into a module with providers = {} and configuration_aliases, and create a resource in the module with it",
+		Points:      225,
+		Flag:        "flag{pr0v1d3r_c0ur13r_d3l1v3r3d}",
+		Difficulty:  "intermediate",
+		Category:    "providers",
+		Validator:   NewStructuredValidator(validateModuleProvidersStructure, ProofKindProvider),
+	}
+
+	// Provider Matrix
+	Challenges["provider_matrix"] = &Challenge{
+		ID:          "provider_matrix",
+		Name:        "Provider Matrix",
+		Description: "Use at least 3 provider instances for 2 players and pass a different aliased instance into each of 2 modules",
+		Points:      300,
+		Flag:        "flag{pr0v1d3r_m4tr1x_0rch3str4t0r}",
+		Difficulty:  "advanced",
+		Category:    "providers",
+		Validator:   NewStructuredValidator(validateProviderMatrixStructure, ProofKindProvider),
+	}
+}
+
+func validateProviderAliasStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkProviderInstances(&result, proof.Providers.Instances, 2) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{d0ubl3_4g3nt_4l14s}"
+	result.Message = "✓ Double agent! Two provider instances, each leaving its name on the resources it creates."
+	return result
+}
+
+func validateMultiPlayerStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkProviderInstances(&result, proof.Providers.Instances, 2) {
+		return result
+	}
+
+	if !checkProviderPlayers(&result, proof.Providers.Instances, 2) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{spl1t_p3rs0n4l1ty_pl4y3rs}"
+	result.Message = "✓ Split personality! One configuration now acts for several players."
+	return result
+}
+
+func validateModuleProvidersStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	providers := proof.Providers
+	if !checkProviderInstances(&result, providers.Instances, 2) {
+		return result
+	}
+
+	if len(providers.Modules) == 0 {
+		result.fail("modules.present", "No module call passes providers",
+			"Example: module \"team\" { source = \"./team\", providers = { ctfchallenge.secondary = ctfchallenge.blue } }")
+		return result
+	}
+	result.pass("modules.present", fmt.Sprintf("Found %d module call(s) passing providers", len(providers.Modules)))
+
+	for i, module := range providers.Modules {
+		if alias := checkModuleProviders(&result, fmt.Sprintf("module.%d", i+1), providers, module); alias != "" {
+			result.Success = true
+			result.Flag = "flag{pr0v1d3r_c0ur13r_d3l1v3r3d}"
+			result.Message = "✓ Provider delivered! Your module creates resources with the instance it was handed."
+			return result
+		}
+	}
+
+	return result
+}
+
+func validateProviderMatrixStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	providers := proof.Providers
+	if !checkProviderInstances(&result, providers.Instances, 3) {
+		return result
+	}
+
+	if !checkProviderPlayers(&result, providers.Instances, 2) {
+		return result
+	}
+
+	if len(providers.Modules) < 2 {
+		result.failCount("modules.count", fmt.Sprintf("Need at least 2 module calls passing providers (found %d)", len(providers.Modules)),
+			">= 2", len(providers.Modules), "Call a module once per team, passing each call a different aliased provider")
+		return result
+	}
+	result.pass("modules.count", fmt.Sprintf("Found %d module calls passing providers", len(providers.Modules)))
+
+	passed := make(map[string]string)
+	for i, module := range providers.Modules {
+		prefix := fmt.Sprintf("module.%d", i+1)
+		alias := checkModuleProviders(&result, prefix, providers, module)
+		if alias == "" {
+			return result
+		}
+		if other, ok := passed[alias]; ok {
+			result.fail(prefix+".distinct", fmt.Sprintf("Modules '%s' and '%s' both receive ctfchallenge.%s", other, module.ModuleName, alias),
+				"Pass a different aliased provider into each module")
+			return result
+		}
+		passed[alias] = module.ModuleName
+	}
+	result.pass("modules.distinct", "Each module receives its own aliased provider")
+
+	result.Success = true
+	result.Flag = "flag{pr0v1d3r_m4tr1x_0rch3str4t0r}"
+	result.Message = "✓ Provider matrix orchestrated! Every module works through the instance you chose for it."
+	return result
+}
+
+// checkProviderInstances verifies that at least minCount distinct provider
+// instances created a ctfchallenge resource, and that aliased instances
+// identify themselves by their alias through instance_name.
+func checkProviderInstances(result *ValidationResult, instances []ProviderInstanceProof, minCount int) bool {
+	if len(instances) < minCount {
+		result.failCount("instances.count", fmt.Sprintf("Need at least %d provider instances (found %d)", minCount, len(instances)),
+			fmt.Sprintf(">= %d", minCount), len(instances), "Add provider \"ctfchallenge\" blocks with alias = \"...\"")
+		return false
+	}
+	result.pass("instances.count", fmt.Sprintf("Found %d provider instances", len(instances)))
+
+	aliases := make(map[string]bool)
+	names := make(map[string]bool)
+	addresses := make(map[string]bool)
+	aliased := 0
+
+	for i, instance := range instances {
+		prefix := fmt.Sprintf("instance.%d", i+1)
+		label := providerAddress(instance.Alias)
+
+		if aliases[instance.Alias] {
+			result.fail(prefix+".alias", fmt.Sprintf("%s is listed more than once", label),
+				"Each provider block needs its own alias; only one may omit it")
+			return false
+		}
+		aliases[instance.Alias] = true
+
+		if instance.Alias != "" {
+			aliased++
+			if instance.InstanceName != instance.Alias {
+				result.fail(prefix+".instance_name", fmt.Sprintf("%s reports provider_instance '%s'", label, instance.InstanceName),
+					fmt.Sprintf("Set instance_name = \"%s\" in the aliased provider block so its resources say which instance created them", instance.Alias))
+				return false
+			}
+		}
+
+		if names[instance.InstanceName] {
+			result.fail(prefix+".instance_name", fmt.Sprintf("provider_instance '%s' is reported by more than one instance", instance.InstanceName),
+				"Give each provider instance a distinct instance_name")
+			return false
+		}
+		names[instance.InstanceName] = true
+		result.pass(prefix+".instance_name", fmt.Sprintf("%s identifies itself as '%s'", label, instance.InstanceName))
+
+		address := normalizeAddress(instance.ResourceAddress)
+		resourceType := strings.SplitN(resourceAddress(stripModulePath(address)), ".", 2)[0]
+		if !strings.HasPrefix(resourceType, "ctfchallenge_") {
+			result.fail(prefix+".resource", fmt.Sprintf("%s is not a ctfchallenge resource", instance.ResourceAddress),
+				"Use the provider_instance and player_name attributes of a ctfchallenge resource created by the instance")
+			return false
+		}
+		if addresses[address] {
+			result.fail(prefix+".resource", fmt.Sprintf("%s is claimed by more than one instance", instance.ResourceAddress),
+				"A resource is created by exactly one provider instance")
+			return false
+		}
+		addresses[address] = true
+		result.pass(prefix+".resource", fmt.Sprintf("%s created %s", label, instance.ResourceAddress))
+	}
+
+	if aliased == 0 {
+		result.fail("instances.aliased", "No provider instance uses an alias",
+			"Add alias = \"...\" to a second provider \"ctfchallenge\" block")
+		return false
+	}
+	result.pass("instances.aliased", fmt.Sprintf("%d aliased provider instance(s)", aliased))
+	return true
+}
+
+// checkProviderPlayers verifies that the instances act for at least minCount
+// different players.
+func checkProviderPlayers(result *ValidationResult, instances []ProviderInstanceProof, minCount int) bool {
+	players := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.PlayerName != "" {
+			players[instance.PlayerName] = true
+		}
+	}
+
+	names := make([]string, 0, len(players))
+	for name := range players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) < minCount {
+		result.failCount("instances.players", fmt.Sprintf("Need resources created by at least %d different players (found %d)", minCount, len(names)),
+			fmt.Sprintf(">= %d", minCount), len(names), "Set a different player_name in each aliased provider block")
+		return false
+	}
+	result.pass("instances.players", fmt.Sprintf("Resources created by players: %s", strings.Join(names, ", ")))
+	return true
+}
+
+// checkModuleProviders verifies that a module call passes an aliased
+// ctfchallenge provider, that the module declares the configuration aliases
+// it receives, and that a resource in the module was created by the passed
+// instance. It returns the alias of the passed instance, or "" on failure.
+func checkModuleProviders(result *ValidationResult, prefix string, providers *ProviderProof, module ModuleProviders) string {
+	if module.ModuleName == "" {
+		result.fail(prefix+".name", "Module call has no name", "")
+		return ""
+	}
+
+	declared := make(map[string]bool)
+	for _, instance := range providers.Instances {
+		declared[instance.Alias] = true
+	}
+
+	configurationAliases := make(map[string]bool)
+	for _, alias := range module.ConfigurationAliases {
+		configurationAliases[strings.TrimSpace(alias)] = true
+	}
+
+	keys := make([]string, 0, len(module.Providers))
+	for key := range module.Providers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	passed := ""
+	for _, key := range keys {
+		value := strings.TrimSpace(module.Providers[key])
+		localAlias, ok := ctfchallengeProviderAlias(key)
+		if !ok {
+			continue
+		}
+
+		parentAlias, ok := ctfchallengeProviderAlias(value)
+		if !ok || !declared[parentAlias] {
+			result.fail(prefix+".providers", fmt.Sprintf("Module '%s' receives %s, which is not one of your provider instances", module.ModuleName, value),
+				"Pass a configured instance, e.g. providers = { ctfchallenge.secondary = ctfchallenge.blue }")
+			return ""
+		}
+
+		if localAlias != "" && !configurationAliases[key] {
+			result.fail(prefix+".configuration_aliases", fmt.Sprintf("Module '%s' does not declare %s in configuration_aliases", module.ModuleName, key),
+				fmt.Sprintf("Add configuration_aliases = [%s] to the module's required_providers entry for ctfchallenge", key))
+			return ""
+		}
+
+		if parentAlias != "" && passed == "" {
+			passed = parentAlias
+		}
+	}
+
+	if passed == "" {
+		result.fail(prefix+".providers", fmt.Sprintf("Module '%s' is not passed an aliased ctfchallenge provider", module.ModuleName),
+			"Example: providers = { ctfchallenge = ctfchallenge.blue }")
+		return ""
+	}
+	result.pass(prefix+".providers", fmt.Sprintf("Module '%s' receives %s", module.ModuleName, providerAddress(passed)))
+
+	for _, instance := range providers.Instances {
+		if instance.Alias == passed && inModule(instance.ResourceAddress, module.ModuleName) {
+			result.pass(prefix+".resource", fmt.Sprintf("%s in module '%s' was created by %s", instance.ResourceAddress, module.ModuleName, providerAddress(passed)))
+			return passed
+		}
+	}
+
+	result.fail(prefix+".resource", fmt.Sprintf("No resource in module '%s' was created by %s", module.ModuleName, providerAddress(passed)),
+		fmt.Sprintf("Report a resource at module.%s.<type>.<name> as the proof for %s", module.ModuleName, providerAddress(passed)))
+	return ""
+}
+
+// ctfchallengeProviderAlias parses a ctfchallenge provider address such as
+// ctfchallenge or ctfchallenge.blue, returning the alias ("" for the default
+// instance).
+func ctfchallengeProviderAlias(address string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(address), ".")
+	switch {
+	case len(parts) == 1 && parts[0] == "ctfchallenge":
+		return "", true
+	case len(parts) == 2 && parts[0] == "ctfchallenge" && parts[1] != "":
+		return parts[1], true
+	}
+	return "", false
+}
+
+// providerAddress renders the provider address of an alias.
+func providerAddress(alias string) string {
+	if alias == "" {
+		return "ctfchallenge"
+	}
+	return "ctfchallenge." + alias
+}
+
+// stripModulePath removes leading module.<name> steps from an address.
+func stripModulePath(address string) string {
+	for strings.HasPrefix(address, "module.") {
+		parts := strings.SplitN(address, ".", 3)
+		if len(parts) < 3 {
+			return ""
+		}
+		address = parts[2]
+	}
+	return address
+}
+
+// inModule reports whether an address belongs to the named module call,
+// including instances of it created with count or for_each.
+func inModule(address, moduleName string) bool {
+	address = normalizeAddress(address)
+	prefix := "module." + moduleName
+	return strings.HasPrefix(address, prefix+".") || strings.HasPrefix(address, prefix+"[")
+}
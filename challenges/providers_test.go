@@ -0,0 +1,199 @@
+package challenges
+
+import "testing"
+
+var (
+	defaultInstance = ProviderInstanceProof{InstanceName: "default", PlayerName: "alice", ResourceAddress: "ctfchallenge_flag_validator.alice"}
+	blueInstance    = ProviderInstanceProof{Alias: "blue", InstanceName: "blue", PlayerName: "bob", ResourceAddress: "module.blue.ctfchallenge_flag_validator.team"}
+	redInstance     = ProviderInstanceProof{Alias: "red", InstanceName: "red", PlayerName: "bob", ResourceAddress: "module.red[0].ctfchallenge_flag_validator.team"}
+
+	blueModule = ModuleProviders{ModuleName: "blue", Providers: map[string]string{"ctfchallenge": "ctfchallenge.blue"}}
+	redModule  = ModuleProviders{ModuleName: "red", Providers: map[string]string{"ctfchallenge.secondary": "ctfchallenge.red"}, ConfigurationAliases: []string{"ctfchallenge.secondary"}}
+)
+
+func TestProviderValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		proof     ProviderProof
+		failed    string
+	}{
+		"provider alias": {
+			challenge: "provider_alias",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance}},
+		},
+		"provider alias with one instance": {
+			challenge: "provider_alias",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{blueInstance}},
+			failed:    "instances.count",
+		},
+		"provider alias without an alias": {
+			challenge: "provider_alias",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{InstanceName: "other", ResourceAddress: "ctfchallenge_flag_validator.other"}}},
+			failed: "instance.2.alias",
+		},
+		"provider alias with only aliased instances": {
+			challenge: "provider_alias",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{blueInstance, redInstance}},
+		},
+		"provider alias with a mismatched instance name": {
+			challenge: "provider_alias",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{Alias: "blue", InstanceName: "default", ResourceAddress: "ctfchallenge_flag_validator.blue"}}},
+			failed: "instance.2.instance_name",
+		},
+		"provider alias with a repeated instance name": {
+			challenge: "provider_alias",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{
+				{InstanceName: "blue", ResourceAddress: "ctfchallenge_flag_validator.alice"}, blueInstance}},
+			failed: "instance.2.instance_name",
+		},
+		"provider alias with another provider's resource": {
+			challenge: "provider_alias",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{Alias: "blue", InstanceName: "blue", ResourceAddress: "terraform_data.blue"}}},
+			failed: "instance.2.resource",
+		},
+		"provider alias with a shared resource": {
+			challenge: "provider_alias",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{Alias: "blue", InstanceName: "blue", ResourceAddress: `ctfchallenge_flag_validator.alice`}}},
+			failed: "instance.2.resource",
+		},
+		"multi player": {
+			challenge: "multi_player",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance}},
+		},
+		"multi player with one player": {
+			challenge: "multi_player",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{blueInstance, redInstance}},
+			failed:    "instances.players",
+		},
+		"multi player without player names": {
+			challenge: "multi_player",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{
+				{InstanceName: "default", ResourceAddress: "ctfchallenge_flag_validator.alice"},
+				{Alias: "blue", InstanceName: "blue", ResourceAddress: "ctfchallenge_flag_validator.blue"}}},
+			failed: "instances.players",
+		},
+		"module providers": {
+			challenge: "module_providers",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance}, Modules: []ModuleProviders{blueModule}},
+		},
+		"module providers with a configuration alias": {
+			challenge: "module_providers",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, redInstance}, Modules: []ModuleProviders{redModule}},
+		},
+		"module providers without modules": {
+			challenge: "module_providers",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance}},
+			failed:    "modules.present",
+		},
+		"module providers without a module name": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance},
+				Modules: []ModuleProviders{{Providers: blueModule.Providers}}},
+			failed: "module.1.name",
+		},
+		"module providers passing an unknown instance": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance},
+				Modules: []ModuleProviders{{ModuleName: "blue", Providers: map[string]string{"ctfchallenge": "ctfchallenge.green"}}}},
+			failed: "module.1.providers",
+		},
+		"module providers passing the default instance": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance},
+				Modules: []ModuleProviders{{ModuleName: "blue", Providers: map[string]string{"ctfchallenge": "ctfchallenge"}}}},
+			failed: "module.1.providers",
+		},
+		"module providers passing another provider": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance},
+				Modules: []ModuleProviders{{ModuleName: "blue", Providers: map[string]string{"aws": "aws.west"}}}},
+			failed: "module.1.providers",
+		},
+		"module providers without configuration aliases": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, redInstance},
+				Modules: []ModuleProviders{{ModuleName: "red", Providers: redModule.Providers}}},
+			failed: "module.1.configuration_aliases",
+		},
+		"module providers without a resource in the module": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{Alias: "blue", InstanceName: "blue", PlayerName: "bob", ResourceAddress: "ctfchallenge_flag_validator.blue"}},
+				Modules: []ModuleProviders{blueModule}},
+			failed: "module.1.resource",
+		},
+		"module providers with a resource in a similarly named module": {
+			challenge: "module_providers",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance,
+				{Alias: "blue", InstanceName: "blue", PlayerName: "bob", ResourceAddress: "module.blueprint.ctfchallenge_flag_validator.team"}},
+				Modules: []ModuleProviders{blueModule}},
+			failed: "module.1.resource",
+		},
+		"provider matrix": {
+			challenge: "provider_matrix",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance, redInstance}, Modules: []ModuleProviders{blueModule, redModule}},
+		},
+		"provider matrix with two instances": {
+			challenge: "provider_matrix",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance}, Modules: []ModuleProviders{blueModule}},
+			failed:    "instances.count",
+		},
+		"provider matrix with one player": {
+			challenge: "provider_matrix",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{
+				{InstanceName: "default", PlayerName: "bob", ResourceAddress: "ctfchallenge_flag_validator.bob"}, blueInstance, redInstance},
+				Modules: []ModuleProviders{blueModule, redModule}},
+			failed: "instances.players",
+		},
+		"provider matrix with one module": {
+			challenge: "provider_matrix",
+			proof:     ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance, redInstance}, Modules: []ModuleProviders{blueModule}},
+			failed:    "modules.count",
+		},
+		"provider matrix passing one instance twice": {
+			challenge: "provider_matrix",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance, redInstance},
+				Modules: []ModuleProviders{blueModule, {ModuleName: "blue", Providers: map[string]string{"ctfchallenge": "ctfchallenge.blue"}}}},
+			failed: "module.2.distinct",
+		},
+		"provider matrix with a broken module": {
+			challenge: "provider_matrix",
+			proof: ProviderProof{Instances: []ProviderInstanceProof{defaultInstance, blueInstance, redInstance},
+				Modules: []ModuleProviders{blueModule, {ModuleName: "red", Providers: redModule.Providers}}},
+			failed: "module.2.configuration_aliases",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &ProofData{Providers: &tc.proof}, tc.failed)
+		})
+	}
+}
+
+func TestCtfchallengeProviderAlias(t *testing.T) {
+	cases := map[string]struct {
+		alias string
+		ok    bool
+	}{
+		"ctfchallenge":          {ok: true},
+		" ctfchallenge.blue ":   {alias: "blue", ok: true},
+		"ctfchallenge.":         {},
+		"ctfchallenge.blue.red": {},
+		"aws.blue":              {},
+		"":                      {},
+	}
+
+	for address, tc := range cases {
+		t.Run(address, func(t *testing.T) {
+			alias, ok := ctfchallengeProviderAlias(address)
+			if alias != tc.alias || ok != tc.ok {
+				t.Fatalf("\n\nexpected:\n\n%q %t\n\ngot:\n\n%q %t\n\n", tc.alias, tc.ok, alias, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package challenges
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PuzzleChain is a sequence of puzzles played as the stages of linked
+// ctfchallenge_puzzle_box resources. The secret_output of each stage is the
+// stage_key of the next: it unlocks the stage and seeds its instance, so a
+// stage can only be played with the output of the stage before it. Solving
+// every stage in order completes the chain challenge of the same ID.
+type PuzzleChain struct {
+	ID string
+	// Stages are the puzzle types of the stages, in order
+	Stages []string
+}
+
+// PuzzleChains holds every registered chain by ID.
+var PuzzleChains = make(map[string]*PuzzleChain)
+
+// ChainStageProof is the latest solved record of one stage of a puzzle
+// chain.
+type ChainStageProof struct {
+	Stage      int
+	BoxID      string
+	PuzzleType string
+	// KeyHash is the SHA-256 of the stage_key the stage was unlocked with,
+	// empty for the first stage
+	KeyHash string
+	// OutputHash is the SHA-256 of the secret_output the stage revealed
+	OutputHash string
+}
+
+// ChainProof is a player's progress through a puzzle chain, recorded by the
+// provider rather than reported by the player.
+type ChainProof struct {
+	// BoxID is the ctfchallenge_puzzle_box the proof was read from
+	BoxID  string
+	Chain  string
+	Player string
+	// Stages are the solved stages, in stage order. Stages the player has
+	// not solved are missing.
+	Stages []ChainStageProof
+}
+
+func init() {
+	registerPuzzleChains()
+}
+
+func registerPuzzleChains() {
+	registerPuzzleChain(&PuzzleChain{ID: "cipher_relay", Stages: []string{"caesar", "vigenere", "xor"}}, &Challenge{
+		Name:        "Cipher Relay",
+		Description: "Solve the caesar, vigenere and xor stages of the cipher_relay puzzle chain in order, passing each ctfchallenge_puzzle_box's secret_output to the next as stage_key",
+		Points:      300,
+		Flag:        "flag{c1ph3r_r3l4y_b4t0n_p4ss3d}",
+		Difficulty:  "intermediate",
+	})
+
+	registerPuzzleChain(&PuzzleChain{ID: "network_gauntlet", Stages: []string{"toposort", "cidr_carving", "regex_golf"}}, &Challenge{
+		Name:        "Network Gauntlet",
+		Description: "Solve the toposort, cidr_carving and regex_golf stages of the network_gauntlet puzzle chain in order, passing each ctfchallenge_puzzle_box's secret_output to the next as stage_key",
+		Points:      450,
+		Flag:        "flag{n3tw0rk_g4untl3t_f1n1sh3d}",
+		Difficulty:  "advanced",
+	})
+}
+
+// registerPuzzleChain adds a chain and the challenge completing it, which
+// shares the chain's ID.
+func registerPuzzleChain(chain *PuzzleChain, challenge *Challenge) {
+	PuzzleChains[chain.ID] = chain
+
+	challenge.ID = chain.ID
+	challenge.Category = "puzzles"
+	challenge.Validator = NewStructuredValidator(chain.validate, ProofKindChain)
+	Challenges[chain.ID] = challenge
+}
+
+// PuzzleChainIDs returns the registered chain IDs, sorted.
+func PuzzleChainIDs() []string {
+	ids := make([]string, 0, len(PuzzleChains))
+	for id := range PuzzleChains {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// StageType returns the puzzle type of a stage, numbered from 1, and
+// whether the chain has that stage.
+func (c *PuzzleChain) StageType(stage int) (string, bool) {
+	if stage < 1 || stage > len(c.Stages) {
+		return "", false
+	}
+	return c.Stages[stage-1], true
+}
+
+// StageKey returns the stage_key that unlocks a player's stage: the
+// secret_output of the stage before it, or "" for the first stage.
+func (c *PuzzleChain) StageKey(stage int, player, salt string) string {
+	key := ""
+	for s := 1; s < stage; s++ {
+		key = c.stageOutput(s, player, salt, key)
+	}
+	return key
+}
+
+// StageOutput returns the secret_output a player's stage reveals when it is
+// solved and it is not the final stage.
+func (c *PuzzleChain) StageOutput(stage int, player, salt string) string {
+	return c.stageOutput(stage, player, salt, c.StageKey(stage, player, salt))
+}
+
+// stageOutput derives a stage's output from the key that unlocked it.
+func (c *PuzzleChain) stageOutput(stage int, player, salt, key string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.ID, strconv.Itoa(stage), player, salt, key}, "\x00")))
+	return fmt.Sprintf("%s-%d-%x", c.ID, stage, sum[:8])
+}
+
+// StageSalt returns the salt a player's instance of a stage is generated
+// with: the event salt mixed with the stage's key, so that every stage's
+// puzzle is derived from the output of the stage before it.
+func (c *PuzzleChain) StageSalt(stage int, player, salt string) string {
+	return strings.Join([]string{salt, c.ID, strconv.Itoa(stage), c.StageKey(stage, player, salt)}, "\x00")
+}
+
+// ChainHash returns the hex SHA-256 of a stage key or output, as recorded in
+// ChainStageProof.
+func ChainHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// validate checks that every stage of the chain was solved, each unlocked
+// by the output of the stage before it.
+func (c *PuzzleChain) validate(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	chain := proof.Chain
+	if chain.Chain != c.ID {
+		hint := fmt.Sprintf("Set puzzle_box_id to the final stage of a ctfchallenge_puzzle_box chain with chain = \"%s\"", c.ID)
+		if chain.Chain == "" {
+			result.fail("box.chain", fmt.Sprintf("Puzzle box %s is not a stage of a puzzle chain", chain.BoxID), hint)
+		} else {
+			result.fail("box.chain", fmt.Sprintf("Puzzle box %s is a stage of %s, not %s", chain.BoxID, chain.Chain, c.ID), hint)
+		}
+		return result
+	}
+	result.pass("box.chain", fmt.Sprintf("Puzzle box %s is a stage of %s", chain.BoxID, c.ID))
+
+	stages := make(map[int]ChainStageProof, len(chain.Stages))
+	for _, stage := range chain.Stages {
+		stages[stage.Stage] = stage
+	}
+
+	for n, puzzleType := range c.Stages {
+		id := fmt.Sprintf("stage.%d", n+1)
+		stage, ok := stages[n+1]
+		if !ok {
+			result.fail(id, fmt.Sprintf("Stage %d (%s) has not been solved by %s", n+1, puzzleType, chain.Player),
+				fmt.Sprintf("Solve a ctfchallenge_puzzle_box with chain = \"%s\" and stage = %d", c.ID, n+1))
+			return result
+		}
+		if stage.PuzzleType != puzzleType {
+			result.fail(id, fmt.Sprintf("Stage %d was solved as a %s puzzle, expected %s", n+1, stage.PuzzleType, puzzleType),
+				fmt.Sprintf("Set puzzle_type = \"%s\" on stage %d", puzzleType, n+1))
+			return result
+		}
+
+		if n > 0 {
+			previous := stages[n]
+			if stage.KeyHash != previous.OutputHash {
+				result.fail(id+".key", fmt.Sprintf("Stage %d was not unlocked with the secret_output of stage %d", n+1, n),
+					fmt.Sprintf("Set stage_key on stage %d to the secret_output of stage %d, then apply again", n+1, n))
+				return result
+			}
+			result.pass(id+".key", fmt.Sprintf("Stage %d was unlocked with the secret_output of stage %d", n+1, n))
+		}
+		result.pass(id, fmt.Sprintf("Stage %d (%s) solved by %s", n+1, puzzleType, stage.BoxID))
+	}
+
+	result.Success = true
+	result.Flag = Challenges[c.ID].Flag
+	result.Message = fmt.Sprintf("✓ Chain complete! All %d stages of %s were solved in order, each unlocked by the one before.", len(c.Stages), c.ID)
+	return result
+}
@@ -0,0 +1,243 @@
+package challenges
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// chainProof records every stage of a chain as solved by player, each
+// unlocked with the output of the stage before it.
+func chainProof(c *PuzzleChain, player, salt string) *ChainProof {
+	proof := &ChainProof{
+		BoxID:  fmt.Sprintf("%s-%d", c.ID, len(c.Stages)),
+		Chain:  c.ID,
+		Player: player,
+	}
+	for n, puzzleType := range c.Stages {
+		stage := ChainStageProof{
+			Stage:      n + 1,
+			BoxID:      fmt.Sprintf("%s-%d", c.ID, n+1),
+			PuzzleType: puzzleType,
+			OutputHash: ChainHash(c.StageOutput(n+1, player, salt)),
+		}
+		if n > 0 {
+			stage.KeyHash = ChainHash(c.StageKey(n+1, player, salt))
+		}
+		proof.Stages = append(proof.Stages, stage)
+	}
+	return proof
+}
+
+func TestPuzzleChainIDs(t *testing.T) {
+	expected := []string{"cipher_relay", "network_gauntlet"}
+	if actual := PuzzleChainIDs(); fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Fatalf("expected chains %v, got %v", expected, actual)
+	}
+	for _, id := range expected {
+		challenge, ok := Challenges[id]
+		if !ok || challenge.Category != "puzzles" {
+			t.Fatalf("chain %q has no puzzles challenge", id)
+		}
+		for _, puzzleType := range PuzzleChains[id].Stages {
+			if _, ok := Puzzles[puzzleType]; !ok {
+				t.Fatalf("chain %q has a stage of unknown type %q", id, puzzleType)
+			}
+		}
+	}
+}
+
+func TestPuzzleChainStageType(t *testing.T) {
+	c := PuzzleChains["cipher_relay"]
+	for stage, expected := range map[int]string{0: "", 1: "caesar", 2: "vigenere", 3: "xor", 4: ""} {
+		puzzleType, ok := c.StageType(stage)
+		if puzzleType != expected || ok != (expected != "") {
+			t.Errorf("stage %d: expected %q, got %q (%t)", stage, expected, puzzleType, ok)
+		}
+	}
+}
+
+func TestPuzzleChainStageKeys(t *testing.T) {
+	for _, id := range PuzzleChainIDs() {
+		c := PuzzleChains[id]
+		t.Run(id, func(t *testing.T) {
+			if key := c.StageKey(1, "alice", "test-event"); key != "" {
+				t.Fatalf("expected stage 1 to need no key, got %q", key)
+			}
+
+			seen := map[string]bool{}
+			for stage := 1; stage <= len(c.Stages); stage++ {
+				output := c.StageOutput(stage, "alice", "test-event")
+				if next := c.StageKey(stage+1, "alice", "test-event"); next != output {
+					t.Fatalf("stage %d is unlocked with %q, expected the output of stage %d, %q", stage+1, next, stage, output)
+				}
+				if seen[output] {
+					t.Fatalf("stage %d repeats the output %q of an earlier stage", stage, output)
+				}
+				seen[output] = true
+
+				for name, other := range map[string]string{
+					"player": c.StageOutput(stage, "bob", "test-event"),
+					"salt":   c.StageOutput(stage, "alice", "other-event"),
+				} {
+					if other == output {
+						t.Errorf("stage %d: changing the %s does not change the output", stage, name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPuzzleChainStagesSolvable(t *testing.T) {
+	for _, id := range PuzzleChainIDs() {
+		c := PuzzleChains[id]
+		for _, player := range puzzlePlayers {
+			t.Run(id+"/"+player, func(t *testing.T) {
+				for n, puzzleType := range c.Stages {
+					p, err := GeneratePuzzle(puzzleType, player, c.StageSalt(n+1, player, "test-event"))
+					if err != nil {
+						t.Fatalf("error generating stage %d: %s", n+1, err)
+					}
+
+					answer := solvePuzzle(t, p)
+					if ok, message := p.Check(answer); !ok {
+						t.Fatalf("stage %d: answer %v rejected: %s\n\nprompt: %s", n+1, answer, message, p.Prompt())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPuzzleChainStageSalt(t *testing.T) {
+	c := PuzzleChains["cipher_relay"]
+	salt := c.StageSalt(2, "alice", "test-event")
+	if salt != c.StageSalt(2, "alice", "test-event") {
+		t.Fatal("StageSalt is not deterministic")
+	}
+	for name, other := range map[string]string{
+		"stage":  c.StageSalt(3, "alice", "test-event"),
+		"player": c.StageSalt(2, "bob", "test-event"),
+		"chain":  PuzzleChains["network_gauntlet"].StageSalt(2, "alice", "test-event"),
+		"event":  c.StageSalt(2, "alice", "other-event"),
+	} {
+		if other == salt {
+			t.Errorf("changing the %s does not change the stage salt", name)
+		}
+	}
+
+	chained, _ := GeneratePuzzle("vigenere", "alice", salt)
+	standalone, _ := GeneratePuzzle("vigenere", "alice", "test-event")
+	if chained.Prompt() == standalone.Prompt() {
+		t.Fatalf("stage 2 is the standalone puzzle:\n\n%s", chained.Prompt())
+	}
+}
+
+func TestPuzzleChainValidate(t *testing.T) {
+	c := PuzzleChains["cipher_relay"]
+	cases := map[string]struct {
+		proof  func(proof *ChainProof)
+		failed string
+	}{
+		"complete": {
+			proof: func(proof *ChainProof) {},
+		},
+		"stages out of order in the proof": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[0], proof.Stages[2] = proof.Stages[2], proof.Stages[0]
+			},
+		},
+		"not a chain": {
+			proof: func(proof *ChainProof) {
+				proof.Chain = ""
+			},
+			failed: "box.chain",
+		},
+		"another chain": {
+			proof: func(proof *ChainProof) {
+				proof.Chain = "network_gauntlet"
+			},
+			failed: "box.chain",
+		},
+		"missing stage": {
+			proof: func(proof *ChainProof) {
+				proof.Stages = append(proof.Stages[:1], proof.Stages[2])
+			},
+			failed: "stage.2",
+		},
+		"missing final stage": {
+			proof: func(proof *ChainProof) {
+				proof.Stages = proof.Stages[:2]
+			},
+			failed: "stage.3",
+		},
+		"wrong puzzle type": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[1].PuzzleType = "caesar"
+			},
+			failed: "stage.2",
+		},
+		"key from the wrong stage": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[2].KeyHash = ChainHash(c.StageOutput(1, "alice", "test-event"))
+			},
+			failed: "stage.3.key",
+		},
+		"key from another player": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[1].KeyHash = ChainHash(c.StageKey(2, "bob", "test-event"))
+			},
+			failed: "stage.2.key",
+		},
+		"unhashed key": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[1].KeyHash = c.StageKey(2, "alice", "test-event")
+			},
+			failed: "stage.2.key",
+		},
+		"no key": {
+			proof: func(proof *ChainProof) {
+				proof.Stages[2].KeyHash = ""
+			},
+			failed: "stage.3.key",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			proof := chainProof(c, "alice", "test-event")
+			tc.proof(proof)
+
+			result, err := Challenges[c.ID].ValidateProof(context.Background(), &ProofData{Chain: proof})
+			if err != nil {
+				t.Fatalf("error validating proof: %s", err)
+			}
+			if failed := failedCheck(result); failed != tc.failed || result.Success != (tc.failed == "") {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q (success %t): %s\n\n", tc.failed, failed, result.Success, result.Message)
+			}
+			if result.Success && result.Flag != "flag{c1ph3r_r3l4y_b4t0n_p4ss3d}" {
+				t.Fatalf("expected the chain flag, got %q", result.Flag)
+			}
+			if !result.Success && result.Flag != "" {
+				t.Fatalf("failed validation revealed flag %q", result.Flag)
+			}
+		})
+	}
+}
+
+func TestPuzzleChainValidateAnotherPlayer(t *testing.T) {
+	c := PuzzleChains["network_gauntlet"]
+	proof := chainProof(c, "bob", "test-event")
+	result, _ := Challenges[c.ID].ValidateProof(context.Background(), &ProofData{Chain: proof})
+	if !result.Success {
+		t.Fatalf("bob's own chain rejected: %s", result.Message)
+	}
+
+	// Stage 3 unlocked with alice's key, though bob solved stages 1 and 2
+	proof.Stages[2].KeyHash = ChainHash(c.StageKey(3, "alice", "test-event"))
+	result, _ = Challenges[c.ID].ValidateProof(context.Background(), &ProofData{Chain: proof})
+	if failed := failedCheck(result); result.Success || failed != "stage.3.key" {
+		t.Fatalf("expected stage.3.key to fail, got %q (success %t)", failed, result.Success)
+	}
+}
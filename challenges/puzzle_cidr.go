@@ -0,0 +1,128 @@
+package challenges
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterPuzzle(&cidrCarvingPuzzle{
+		parent:   netip.MustParsePrefix("10.20.0.0/22"),
+		prefixes: []int{23, 24, 25, 25},
+	})
+}
+
+// maxCarvedBits is the longest prefix a generated puzzle asks for.
+const maxCarvedBits = 26
+
+// cidrCarvingPuzzle asks for non-overlapping subnets of the given prefix
+// lengths carved out of a parent network, as cidrsubnet() would produce.
+type cidrCarvingPuzzle struct {
+	parent   netip.Prefix
+	prefixes []int
+}
+
+func (p *cidrCarvingPuzzle) Type() string       { return "cidr_carving" }
+func (p *cidrCarvingPuzzle) Name() string       { return "Subnet Sculptor" }
+func (p *cidrCarvingPuzzle) Difficulty() string { return "advanced" }
+func (p *cidrCarvingPuzzle) Flag() string       { return "flag{c1dr_c4rv3d_cl34n}" }
+
+func (p *cidrCarvingPuzzle) Prompt() string {
+	return fmt.Sprintf("Carve %s into %d non-overlapping subnets with prefix lengths %s",
+		p.parent, len(p.prefixes), prefixLengths(p.prefixes))
+}
+
+// Parameters gives the parent network and the prefix lengths, comma
+// separated, as numbers for cidrsubnet().
+func (p *cidrCarvingPuzzle) Parameters() map[string]string {
+	prefixes := make([]string, len(p.prefixes))
+	for i, bits := range p.prefixes {
+		prefixes[i] = strconv.Itoa(bits)
+	}
+	return map[string]string{
+		"parent":   p.parent.String(),
+		"prefixes": strings.Join(prefixes, ","),
+	}
+}
+
+func (p *cidrCarvingPuzzle) InputSchema() string {
+	return fmt.Sprintf("subnets: %d CIDR blocks inside %s, comma separated, in any order", len(p.prefixes), p.parent)
+}
+
+// prefixLengths formats prefix lengths as "/23, /24".
+func prefixLengths(bits []int) string {
+	lengths := make([]string, len(bits))
+	for i, b := range bits {
+		lengths[i] = "/" + strconv.Itoa(b)
+	}
+	return strings.Join(lengths, ", ")
+}
+
+func (p *cidrCarvingPuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	raw, ok := puzzleInput(inputs, "subnets")
+	if !ok {
+		return false, fmt.Sprintf("Provide %d CIDR blocks as subnets, comma separated", len(p.prefixes))
+	}
+
+	var subnets []netip.Prefix
+	for _, item := range splitList(raw) {
+		subnet, err := netip.ParsePrefix(item)
+		if err != nil {
+			return false, fmt.Sprintf("%q is not a CIDR block", item)
+		}
+		if subnet.Masked() != subnet {
+			return false, fmt.Sprintf("%s has host bits set; the network address is %s", subnet, subnet.Masked())
+		}
+		if !p.parent.Contains(subnet.Addr()) || subnet.Bits() < p.parent.Bits() {
+			return false, fmt.Sprintf("%s is not inside %s", subnet, p.parent)
+		}
+		for _, other := range subnets {
+			if subnet.Overlaps(other) {
+				return false, fmt.Sprintf("%s overlaps %s", subnet, other)
+			}
+		}
+		subnets = append(subnets, subnet)
+	}
+
+	got := make([]int, len(subnets))
+	for i, subnet := range subnets {
+		got[i] = subnet.Bits()
+	}
+	want := append([]int(nil), p.prefixes...)
+	sort.Ints(got)
+	sort.Ints(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		return false, fmt.Sprintf("The subnets must have prefix lengths %s, got %s. Try again!", prefixLengths(want), prefixLengths(got))
+	}
+
+	return true, fmt.Sprintf("Puzzle solved! %s is carved into %d subnets.", p.parent, len(subnets))
+}
+
+// Generate draws a /21 or /22 inside 10.0.0.0/8 and splits it in half a few
+// times, so the prefix lengths always tile the parent exactly.
+func (p *cidrCarvingPuzzle) Generate(rng *rand.Rand) Puzzle {
+	var addr [4]byte
+	binary.BigEndian.PutUint32(addr[:], 10<<24|rng.Uint32()&0x00ffffff)
+	parent := netip.PrefixFrom(netip.AddrFrom4(addr), 21+rng.Intn(2)).Masked()
+
+	prefixes := []int{parent.Bits()}
+	for splits := 2 + rng.Intn(3); splits > 0; splits-- {
+		var splittable []int
+		for i, bits := range prefixes {
+			if bits < maxCarvedBits {
+				splittable = append(splittable, i)
+			}
+		}
+		i := splittable[rng.Intn(len(splittable))]
+		prefixes[i]++
+		prefixes = append(prefixes, prefixes[i])
+	}
+	sort.Ints(prefixes)
+
+	return &cidrCarvingPuzzle{parent: parent, prefixes: prefixes}
+}
@@ -0,0 +1,136 @@
+package challenges
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+func init() {
+	RegisterPuzzle(&caesarPuzzle{plaintext: "infrastructure as code", shift: 11})
+	RegisterPuzzle(&vigenerePuzzle{plaintext: "never edit the state file by hand", key: "lock"})
+}
+
+// cipherWords are the words generated cipher puzzles build their messages
+// from. They are lower case letters only.
+var cipherWords = []string{
+	"apply", "backend", "block", "bucket", "count", "data", "depends", "destroy", "drift", "dynamic",
+	"each", "expression", "flag", "function", "graph", "import", "instance", "lifecycle", "local", "lock",
+	"module", "moved", "network", "output", "plan", "provider", "refresh", "registry", "remote", "resource",
+	"secret", "state", "subnet", "taint", "template", "variable", "vault", "version", "workspace", "zone",
+}
+
+// cipherMessageWords is the number of words in a generated message.
+const cipherMessageWords = 5
+
+// cipherMessage draws a message of cipherMessageWords words.
+func cipherMessage(rng *rand.Rand) string {
+	words := make([]string, cipherMessageWords)
+	for i := range words {
+		words[i] = pick(rng, cipherWords)
+	}
+	return strings.Join(words, " ")
+}
+
+// cipherKeys are the Vigenère keys generated puzzles use.
+var cipherKeys = []string{"lock", "plan", "state", "drift", "graph", "taint", "apply", "module"}
+
+// caesarPuzzle asks for the plaintext of a Caesar cipher with an unknown
+// shift.
+type caesarPuzzle struct {
+	plaintext string
+	shift     int
+}
+
+func (p *caesarPuzzle) Type() string       { return "caesar" }
+func (p *caesarPuzzle) Name() string       { return "Caesar's Console" }
+func (p *caesarPuzzle) Difficulty() string { return "beginner" }
+func (p *caesarPuzzle) Flag() string       { return "flag{c43s4r_sh1ft_d3c0d3d}" }
+
+func (p *caesarPuzzle) Prompt() string {
+	return fmt.Sprintf("Decode the Caesar cipher %q. Every letter was shifted forward by the same unknown amount", p.ciphertext())
+}
+
+func (p *caesarPuzzle) Parameters() map[string]string {
+	return map[string]string{"ciphertext": p.ciphertext()}
+}
+
+func (p *caesarPuzzle) InputSchema() string {
+	return "plaintext: the decoded message"
+}
+
+func (p *caesarPuzzle) ciphertext() string {
+	return shiftLetters(p.plaintext, func(int) int { return p.shift })
+}
+
+func (p *caesarPuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	return checkPlaintext(inputs, p.plaintext, "Caesar")
+}
+
+func (p *caesarPuzzle) Generate(rng *rand.Rand) Puzzle {
+	return &caesarPuzzle{plaintext: cipherMessage(rng), shift: 1 + rng.Intn(25)}
+}
+
+// vigenerePuzzle asks for the plaintext of a Vigenère cipher with a known
+// key.
+type vigenerePuzzle struct {
+	plaintext string
+	key       string
+}
+
+func (p *vigenerePuzzle) Type() string       { return "vigenere" }
+func (p *vigenerePuzzle) Name() string       { return "Vigenère Vault" }
+func (p *vigenerePuzzle) Difficulty() string { return "intermediate" }
+func (p *vigenerePuzzle) Flag() string       { return "flag{v1g3n3r3_k3y_turn3d}" }
+
+func (p *vigenerePuzzle) Prompt() string {
+	return fmt.Sprintf("Decode the Vigenère cipher %q with the key %q. The key advances on letters only", p.ciphertext(), p.key)
+}
+
+func (p *vigenerePuzzle) Parameters() map[string]string {
+	return map[string]string{"ciphertext": p.ciphertext(), "key": p.key}
+}
+
+func (p *vigenerePuzzle) InputSchema() string {
+	return "plaintext: the decoded message"
+}
+
+func (p *vigenerePuzzle) ciphertext() string {
+	return shiftLetters(p.plaintext, func(i int) int { return int(p.key[i%len(p.key)] - 'a') })
+}
+
+func (p *vigenerePuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	return checkPlaintext(inputs, p.plaintext, "Vigenère")
+}
+
+func (p *vigenerePuzzle) Generate(rng *rand.Rand) Puzzle {
+	return &vigenerePuzzle{plaintext: cipherMessage(rng), key: pick(rng, cipherKeys)}
+}
+
+// shiftLetters shifts the i-th letter of text forward by shift(i), leaving
+// other characters unchanged. text is lower case.
+func shiftLetters(text string, shift func(i int) int) string {
+	var b strings.Builder
+	i := 0
+	for _, r := range text {
+		if r >= 'a' && r <= 'z' {
+			r = 'a' + (r-'a'+rune(shift(i)))%26
+			i++
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// checkPlaintext compares the plaintext input with the expected message,
+// ignoring case and repeated whitespace.
+func checkPlaintext(inputs map[string]interface{}, want, cipher string) (bool, string) {
+	got, ok := puzzleInput(inputs, "plaintext")
+	if !ok {
+		return false, "Provide the decoded message as plaintext"
+	}
+	if strings.Join(strings.Fields(strings.ToLower(got)), " ") != want {
+		return false, fmt.Sprintf("%q is not the message hidden by the %s cipher. Try again!", got, cipher)
+	}
+	return true, fmt.Sprintf("Puzzle solved! The %s cipher reads %q.", cipher, want)
+}
@@ -0,0 +1,139 @@
+package challenges
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+func init() {
+	RegisterPuzzle(&topoSortPuzzle{dependencies: map[string][]string{
+		"vpc":               {},
+		"iam_role":          {},
+		"subnet":            {"vpc"},
+		"security_group":    {"vpc"},
+		"instance":          {"subnet", "security_group", "iam_role"},
+		"load_balancer":     {"subnet", "security_group"},
+		"target_attachment": {"instance", "load_balancer"},
+		"dns_record":        {"load_balancer"},
+	}})
+}
+
+// graphNodes are the node names generated graphs draw from.
+var graphNodes = []string{
+	"vpc", "subnet", "route_table", "security_group", "iam_role", "iam_policy", "instance",
+	"load_balancer", "target_group", "dns_record", "bucket", "database", "cache", "queue",
+}
+
+// graphSize is the number of nodes in a generated graph.
+const graphSize = 8
+
+// topoSortPuzzle asks for an order in which a dependency graph can be
+// created, the way Terraform walks its resource graph. Any order that puts
+// every node after its dependencies is accepted.
+type topoSortPuzzle struct {
+	// dependencies maps each node to the nodes it depends on
+	dependencies map[string][]string
+}
+
+func (p *topoSortPuzzle) Type() string       { return "toposort" }
+func (p *topoSortPuzzle) Name() string       { return "Graph Walker" }
+func (p *topoSortPuzzle) Difficulty() string { return "intermediate" }
+func (p *topoSortPuzzle) Flag() string       { return "flag{t0p0l0g1c4l_0rd3r_k33p3r}" }
+
+func (p *topoSortPuzzle) Prompt() string {
+	var edges []string
+	for _, node := range p.nodes() {
+		if deps := p.dependencies[node]; len(deps) > 0 {
+			edges = append(edges, fmt.Sprintf("%s depends on %s", node, strings.Join(deps, ", ")))
+		}
+	}
+	return fmt.Sprintf("Order the nodes %s so that each is created after everything it depends on: %s",
+		strings.Join(p.nodes(), ", "), strings.Join(edges, "; "))
+}
+
+// Parameters maps each node to the nodes it depends on, comma separated.
+func (p *topoSortPuzzle) Parameters() map[string]string {
+	params := make(map[string]string, len(p.dependencies))
+	for node, deps := range p.dependencies {
+		params[node] = strings.Join(deps, ",")
+	}
+	return params
+}
+
+func (p *topoSortPuzzle) InputSchema() string {
+	return fmt.Sprintf("order: the %d nodes, comma separated, in creation order", len(p.dependencies))
+}
+
+// nodes returns the graph's nodes, sorted.
+func (p *topoSortPuzzle) nodes() []string {
+	nodes := make([]string, 0, len(p.dependencies))
+	for node := range p.dependencies {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (p *topoSortPuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	raw, ok := puzzleInput(inputs, "order")
+	if !ok {
+		return false, "Provide the creation order as order, e.g. \"vpc, subnet, ...\""
+	}
+
+	order := splitList(raw)
+	position := make(map[string]int, len(order))
+	for i, node := range order {
+		if _, known := p.dependencies[node]; !known {
+			return false, fmt.Sprintf("Unknown node %q (expected %s)", node, strings.Join(p.nodes(), ", "))
+		}
+		if _, dup := position[node]; dup {
+			return false, fmt.Sprintf("%s appears more than once", node)
+		}
+		position[node] = i
+	}
+
+	var missing []string
+	for _, node := range p.nodes() {
+		if _, ok := position[node]; !ok {
+			missing = append(missing, node)
+		}
+	}
+	if len(missing) > 0 {
+		return false, fmt.Sprintf("The order is missing %s", strings.Join(missing, ", "))
+	}
+
+	for _, node := range order {
+		for _, dep := range p.dependencies[node] {
+			if position[dep] > position[node] {
+				return false, fmt.Sprintf("%s is created before %s, which it depends on. Try again!", node, dep)
+			}
+		}
+	}
+
+	return true, "Puzzle solved! Every node is created after its dependencies."
+}
+
+// Generate draws graphSize nodes in a hidden creation order and gives every
+// node after the first two one or two dependencies among the nodes before
+// it, so the graph has no cycles and usually more than one valid order.
+func (p *topoSortPuzzle) Generate(rng *rand.Rand) Puzzle {
+	order := make([]string, graphSize)
+	for i, j := range rng.Perm(len(graphNodes))[:graphSize] {
+		order[i] = graphNodes[j]
+	}
+
+	dependencies := make(map[string][]string, graphSize)
+	for i, node := range order {
+		deps := []string{}
+		if i >= 2 {
+			for _, j := range rng.Perm(i)[:1+rng.Intn(2)] {
+				deps = append(deps, order[j])
+			}
+			sort.Strings(deps)
+		}
+		dependencies[node] = deps
+	}
+	return &topoSortPuzzle{dependencies: dependencies}
+}
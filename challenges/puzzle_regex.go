@@ -0,0 +1,185 @@
+package challenges
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterPuzzle(&regexGolfPuzzle{
+		match:     []string{"v1.5.7", "v1.9.0", "v0.15.5", "v1.10.2", "v0.12.31"},
+		reject:    []string{"v1.5", "1.5.7", "v1.5.7-beta1", "v1.5.7.1", "release-v1.6.0", "v1.x.0"},
+		maxLength: 18,
+	})
+}
+
+// regexFamily generates regex golf strings: valid strings match reference,
+// and each mutation turns a valid string into a near miss that does not.
+type regexFamily struct {
+	reference *regexp.Regexp
+	valid     func(rng *rand.Rand) string
+	mutations []func(rng *rand.Rand, s string) string
+}
+
+// regexFamilies are the kinds of strings generated regex golf puzzles use.
+var regexFamilies = []regexFamily{
+	{
+		// Release tags
+		reference: regexp.MustCompile(`^v\d+\.\d+\.\d+$`),
+		valid: func(rng *rand.Rand) string {
+			return fmt.Sprintf("v%d.%d.%d", rng.Intn(3), rng.Intn(20), rng.Intn(40))
+		},
+		mutations: []func(*rand.Rand, string) string{
+			func(rng *rand.Rand, s string) string { return s[:strings.LastIndex(s, ".")] },
+			func(rng *rand.Rand, s string) string { return s[1:] },
+			func(rng *rand.Rand, s string) string { return s + pick(rng, []string{"-beta1", "-rc1", "-alpha"}) },
+			func(rng *rand.Rand, s string) string { return s + "." + strconv.Itoa(1+rng.Intn(9)) },
+			func(rng *rand.Rand, s string) string { return "release-" + s },
+			func(rng *rand.Rand, s string) string { return strings.Replace(s, ".", ".x", 1) },
+		},
+	},
+	{
+		// Cloud region codes
+		reference: regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d$`),
+		valid: func(rng *rand.Rand) string {
+			return fmt.Sprintf("%s-%s-%d", pick(rng, []string{"us", "eu", "ap", "ca", "sa"}),
+				pick(rng, []string{"east", "west", "north", "south", "central"}), 1+rng.Intn(9))
+		},
+		mutations: []func(*rand.Rand, string) string{
+			func(rng *rand.Rand, s string) string { return s[:strings.LastIndex(s, "-")] },
+			func(rng *rand.Rand, s string) string { return strings.Replace(s, "-", "", 1) },
+			func(rng *rand.Rand, s string) string { return s + strconv.Itoa(rng.Intn(10)) },
+			func(rng *rand.Rand, s string) string { return strings.ToUpper(s[:2]) + s[2:] },
+			func(rng *rand.Rand, s string) string { return s[:2] + "x" + s[2:] },
+			func(rng *rand.Rand, s string) string { return s + pick(rng, []string{"a", "b", "c"}) },
+		},
+	},
+	{
+		// Environment-qualified host names
+		reference: regexp.MustCompile(`^(dev|stg|prd)-[a-z]+-\d\d$`),
+		valid: func(rng *rand.Rand) string {
+			return fmt.Sprintf("%s-%s-%02d", pick(rng, []string{"dev", "stg", "prd"}),
+				pick(rng, []string{"web", "api", "db", "cache", "queue"}), 1+rng.Intn(20))
+		},
+		mutations: []func(*rand.Rand, string) string{
+			func(rng *rand.Rand, s string) string { return s[:len(s)-2] + s[len(s)-1:] },
+			func(rng *rand.Rand, s string) string { return pick(rng, []string{"qa", "uat", "prod"}) + s[3:] },
+			func(rng *rand.Rand, s string) string { return s[:4] + strings.ToUpper(s[4:5]) + s[5:] },
+			func(rng *rand.Rand, s string) string { return s + "-old" },
+			func(rng *rand.Rand, s string) string { return s[:4] + s[strings.LastIndex(s, "-"):] },
+			func(rng *rand.Rand, s string) string { return s + strconv.Itoa(rng.Intn(10)) },
+		},
+	},
+}
+
+// regexGolfSlack is how many characters longer than the family's reference
+// pattern a generated puzzle allows.
+const regexGolfSlack = 2
+
+// regexGolfMatches is the number of strings a generated puzzle must match.
+const regexGolfMatches = 5
+
+// regexGolfPuzzle asks for a short regular expression that matches every
+// string in match and none in reject. Patterns are unanchored unless they
+// use ^ and $, as with Terraform's regex().
+type regexGolfPuzzle struct {
+	match     []string
+	reject    []string
+	maxLength int
+}
+
+func (p *regexGolfPuzzle) Type() string       { return "regex_golf" }
+func (p *regexGolfPuzzle) Name() string       { return "Regex Golf" }
+func (p *regexGolfPuzzle) Difficulty() string { return "advanced" }
+func (p *regexGolfPuzzle) Flag() string       { return "flag{r3g3x_g0lf_h0l3_1n_0n3}" }
+
+func (p *regexGolfPuzzle) Prompt() string {
+	return fmt.Sprintf("Write a regular expression of at most %d characters that matches %s and does not match %s",
+		p.maxLength, quoteList(p.match), quoteList(p.reject))
+}
+
+// Parameters gives the strings to match and reject, comma separated, and
+// the character limit.
+func (p *regexGolfPuzzle) Parameters() map[string]string {
+	return map[string]string{
+		"match":      strings.Join(p.match, ","),
+		"reject":     strings.Join(p.reject, ","),
+		"max_length": strconv.Itoa(p.maxLength),
+	}
+}
+
+func (p *regexGolfPuzzle) InputSchema() string {
+	return fmt.Sprintf("pattern: an RE2 regular expression of at most %d characters", p.maxLength)
+}
+
+func (p *regexGolfPuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	pattern, ok := inputs["pattern"].(string)
+	if !ok || pattern == "" {
+		return false, "Provide a regular expression as pattern"
+	}
+	if len(pattern) > p.maxLength {
+		return false, fmt.Sprintf("pattern is %d characters; the limit is %d", len(pattern), p.maxLength)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Sprintf("pattern is not a valid regular expression: %s", err)
+	}
+	for _, s := range p.match {
+		if !re.MatchString(s) {
+			return false, fmt.Sprintf("pattern does not match %q. Try again!", s)
+		}
+	}
+	for _, s := range p.reject {
+		if re.MatchString(s) {
+			return false, fmt.Sprintf("pattern matches %q, which it must reject. Try again!", s)
+		}
+	}
+
+	return true, fmt.Sprintf("Puzzle solved! %d characters, with %d to spare.", len(pattern), p.maxLength-len(pattern))
+}
+
+// Generate draws a family, regexGolfMatches valid strings and one near miss
+// per mutation. The limit leaves regexGolfSlack characters over the
+// family's reference pattern.
+func (p *regexGolfPuzzle) Generate(rng *rand.Rand) Puzzle {
+	family := regexFamilies[rng.Intn(len(regexFamilies))]
+	seen := make(map[string]bool)
+	generated := &regexGolfPuzzle{maxLength: len(family.reference.String()) + regexGolfSlack}
+
+	for len(generated.match) < regexGolfMatches {
+		if s := family.valid(rng); !seen[s] {
+			seen[s] = true
+			generated.match = append(generated.match, s)
+		}
+	}
+
+	for _, mutate := range family.mutations {
+		// Mutations rarely collide; give up on one after a few tries
+		for attempt := 0; attempt < 5; attempt++ {
+			s := mutate(rng, family.valid(rng))
+			if !seen[s] && !family.reference.MatchString(s) {
+				seen[s] = true
+				generated.reject = append(generated.reject, s)
+				break
+			}
+		}
+	}
+	rng.Shuffle(len(generated.reject), func(i, j int) {
+		generated.reject[i], generated.reject[j] = generated.reject[j], generated.reject[i]
+	})
+
+	return generated
+}
+
+// quoteList formats values as a comma separated list of quoted strings.
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,81 @@
+package challenges
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+func init() {
+	RegisterPuzzle(&xorPuzzle{count: 5, max: 255, target: 0})
+}
+
+// xorPuzzle asks for count distinct numbers between 1 and max whose XOR is
+// target. Requiring distinct non-zero numbers rules out the all-zeros and
+// cancelling-pairs answers.
+type xorPuzzle struct {
+	count  int
+	max    int
+	target int
+}
+
+func (p *xorPuzzle) Type() string       { return "xor" }
+func (p *xorPuzzle) Name() string       { return "XOR Lock" }
+func (p *xorPuzzle) Difficulty() string { return "beginner" }
+func (p *xorPuzzle) Flag() string       { return "flag{xor_puzzl3_s0lv3d}" }
+
+func (p *xorPuzzle) Prompt() string {
+	return fmt.Sprintf("Find %d distinct numbers between 1 and %d whose XOR is %d", p.count, p.max, p.target)
+}
+
+func (p *xorPuzzle) Parameters() map[string]string {
+	return map[string]string{
+		"count":  strconv.Itoa(p.count),
+		"max":    strconv.Itoa(p.max),
+		"target": strconv.Itoa(p.target),
+	}
+}
+
+func (p *xorPuzzle) InputSchema() string {
+	return fmt.Sprintf("input_1 through input_%d: distinct whole numbers between 1 and %d", p.count, p.max)
+}
+
+func (p *xorPuzzle) Check(inputs map[string]interface{}) (bool, string) {
+	numbers := make([]int, 0, p.count)
+	seen := make(map[int]string)
+
+	for i := 1; i <= p.count; i++ {
+		key := fmt.Sprintf("input_%d", i)
+		raw, ok := puzzleInput(inputs, key)
+		if !ok {
+			return false, fmt.Sprintf("Provide exactly %d numbers (input_1 through input_%d)", p.count, p.count)
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return false, fmt.Sprintf("%s must be a whole number, got %q", key, raw)
+		}
+		if n < 1 || n > p.max {
+			return false, fmt.Sprintf("%s must be between 1 and %d, got %d", key, p.max, n)
+		}
+		if other, dup := seen[n]; dup {
+			return false, fmt.Sprintf("%s repeats %s (%d); every number must be distinct", key, other, n)
+		}
+		seen[n] = key
+		numbers = append(numbers, n)
+	}
+
+	result := XOR(numbers)
+	if result != p.target {
+		return false, fmt.Sprintf("XOR result: %d (must be %d). Try again!", result, p.target)
+	}
+	if p.target == 0 {
+		return true, "Puzzle solved! XOR of all inputs equals zero."
+	}
+	return true, fmt.Sprintf("Puzzle solved! XOR of all inputs equals %d.", p.target)
+}
+
+// Generate draws the target, leaving out 0 so that answers to the classic
+// instance do not carry over.
+func (p *xorPuzzle) Generate(rng *rand.Rand) Puzzle {
+	return &xorPuzzle{count: p.count, max: p.max, target: 1 + rng.Intn(p.max)}
+}
@@ -0,0 +1,113 @@
+package challenges
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Puzzle is a logic puzzle played with ctfchallenge_puzzle_box. The box's
+// puzzle_type selects the puzzle, which checks the box's inputs itself.
+//
+// A Puzzle value is one instance of its type, with fixed data and answer.
+// The registered value is the classic instance; players are given the
+// instance Generate derives from their seed, see GeneratePuzzle.
+type Puzzle interface {
+	// Type is the puzzle_type that selects the puzzle
+	Type() string
+	Name() string
+	// Difficulty is beginner, intermediate or advanced
+	Difficulty() string
+	// Prompt states the puzzle, including the data needed to solve it
+	Prompt() string
+	// Parameters are the instance's data, for use in expressions
+	Parameters() map[string]string
+	// InputSchema describes the inputs the puzzle reads
+	InputSchema() string
+	// Flag is revealed in secret_output when the puzzle is solved
+	Flag() string
+	// Check reports whether the inputs solve the puzzle, with a message
+	// explaining the outcome
+	Check(inputs map[string]interface{}) (bool, string)
+	// Generate returns a new instance of the puzzle type, drawing its data
+	// from rng only, so that the same seed always gives the same instance
+	Generate(rng *rand.Rand) Puzzle
+}
+
+// DefaultPuzzleType is the puzzle a ctfchallenge_puzzle_box plays when
+// puzzle_type is not set.
+const DefaultPuzzleType = "xor"
+
+// Puzzles holds every registered puzzle by type.
+var Puzzles = make(map[string]Puzzle)
+
+// RegisterPuzzle adds a puzzle to the registry. Puzzles are registered from
+// init, so an empty or duplicate type panics.
+func RegisterPuzzle(p Puzzle) {
+	if p.Type() == "" {
+		panic("challenges: puzzle registered without a type")
+	}
+	if _, exists := Puzzles[p.Type()]; exists {
+		panic(fmt.Sprintf("challenges: puzzle type %q registered twice", p.Type()))
+	}
+	Puzzles[p.Type()] = p
+}
+
+// PuzzleTypes returns the registered puzzle types, sorted.
+func PuzzleTypes() []string {
+	types := make([]string, 0, len(Puzzles))
+	for t := range Puzzles {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// PuzzleSeed derives the seed of a player's instance of a puzzle type from
+// the player name and the event salt, which organisers set per event so
+// that instances cannot be worked out in advance.
+func PuzzleSeed(puzzleType, player, salt string) int64 {
+	sum := sha256.Sum256([]byte(puzzleType + "\x00" + player + "\x00" + salt))
+	return int64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// GeneratePuzzle returns a player's instance of a puzzle type. The same
+// type, player and salt always give the same instance, so organisers can
+// reproduce any player's puzzle.
+func GeneratePuzzle(puzzleType, player, salt string) (Puzzle, error) {
+	p, ok := Puzzles[puzzleType]
+	if !ok {
+		return nil, fmt.Errorf("unknown puzzle_type %q (expected one of %s)", puzzleType, strings.Join(PuzzleTypes(), ", "))
+	}
+	return p.Generate(rand.New(rand.NewSource(PuzzleSeed(puzzleType, player, salt)))), nil
+}
+
+// puzzleInput returns the input at key with surrounding whitespace removed,
+// and whether it was provided.
+func puzzleInput(inputs map[string]interface{}, key string) (string, bool) {
+	value, ok := inputs[key].(string)
+	if !ok {
+		return "", false
+	}
+	value = strings.TrimSpace(value)
+	return value, value != ""
+}
+
+// splitList splits a comma separated input into its non-empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pick returns a random element of values.
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.Intn(len(values))]
+}
@@ -0,0 +1,423 @@
+package challenges
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// solvePuzzle works out an answer to a puzzle from its Parameters, the way
+// a player would.
+func solvePuzzle(t *testing.T, p Puzzle) map[string]interface{} {
+	t.Helper()
+
+	params := p.Parameters()
+	switch p.Type() {
+	case "xor":
+		return solveXOR(t, params)
+	case "caesar":
+		for shift := 1; shift < 26; shift++ {
+			inputs := map[string]interface{}{"plaintext": shiftLetters(params["ciphertext"], func(int) int { return 26 - shift })}
+			if ok, _ := p.Check(inputs); ok {
+				return inputs
+			}
+		}
+		t.Fatalf("no shift decodes %q", params["ciphertext"])
+	case "vigenere":
+		key := params["key"]
+		return map[string]interface{}{"plaintext": shiftLetters(params["ciphertext"], func(i int) int { return 26 - int(key[i%len(key)]-'a') })}
+	case "toposort":
+		return map[string]interface{}{"order": strings.Join(topologicalOrder(params), ", ")}
+	case "cidr_carving":
+		return map[string]interface{}{"subnets": strings.Join(carveSubnets(t, params), ", ")}
+	case "regex_golf":
+		return map[string]interface{}{"pattern": golfPattern(t, params)}
+	}
+	t.Fatalf("no solver for puzzle type %q", p.Type())
+	return nil
+}
+
+// solveXOR picks count-1 small numbers and makes the last one up the
+// difference, skipping choices where it collides.
+func solveXOR(t *testing.T, params map[string]string) map[string]interface{} {
+	count, _ := strconv.Atoi(params["count"])
+	max, _ := strconv.Atoi(params["max"])
+	target, _ := strconv.Atoi(params["target"])
+
+	for offset := 0; offset < max; offset++ {
+		numbers := make([]int, 0, count)
+		for n := 1; len(numbers) < count-1; n++ {
+			numbers = append(numbers, n+offset)
+		}
+		last := XOR(numbers) ^ target
+		if last < 1 || last > max || containsInt(numbers, last) {
+			continue
+		}
+		return xorInputs(append(numbers, last))
+	}
+	t.Fatalf("no XOR answer for %v", params)
+	return nil
+}
+
+func xorInputs(numbers []int) map[string]interface{} {
+	inputs := make(map[string]interface{}, len(numbers))
+	for i, n := range numbers {
+		inputs[fmt.Sprintf("input_%d", i+1)] = strconv.Itoa(n)
+	}
+	return inputs
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// topologicalOrder orders the nodes of a toposort puzzle's Parameters,
+// dependencies first.
+func topologicalOrder(params map[string]string) []string {
+	var order []string
+	done := make(map[string]bool)
+	for len(order) < len(params) {
+		var ready []string
+		for node, deps := range params {
+			if done[node] {
+				continue
+			}
+			satisfied := true
+			for _, dep := range splitList(deps) {
+				satisfied = satisfied && done[dep]
+			}
+			if satisfied {
+				ready = append(ready, node)
+			}
+		}
+		sort.Strings(ready)
+		for _, node := range ready {
+			done[node] = true
+		}
+		order = append(order, ready...)
+	}
+	return order
+}
+
+// carveSubnets allocates the largest subnets first, one after another, so
+// every subnet starts on its own boundary.
+func carveSubnets(t *testing.T, params map[string]string) []string {
+	parent := netip.MustParsePrefix(params["parent"])
+	var prefixes []int
+	for _, item := range splitList(params["prefixes"]) {
+		bits, err := strconv.Atoi(item)
+		if err != nil {
+			t.Fatalf("invalid prefix length %q", item)
+		}
+		prefixes = append(prefixes, bits)
+	}
+	sort.Ints(prefixes)
+
+	next := ipv4Uint(parent.Addr())
+	subnets := make([]string, len(prefixes))
+	for i, bits := range prefixes {
+		subnets[i] = netip.PrefixFrom(uint32IPv4(next), bits).String()
+		next += 1 << (32 - bits)
+	}
+	return subnets
+}
+
+func ipv4Uint(addr netip.Addr) uint32 {
+	b := addr.As4()
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+func uint32IPv4(n uint32) netip.Addr {
+	return netip.AddrFrom4([4]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+}
+
+// golfPattern returns the reference pattern of the regex family whose
+// strings the puzzle uses.
+func golfPattern(t *testing.T, params map[string]string) string {
+	match := splitList(params["match"])
+	reject := splitList(params["reject"])
+	for _, family := range regexFamilies {
+		if matchesAll(family.reference, match) && !matchesAny(family.reference, reject) {
+			return family.reference.String()
+		}
+	}
+	t.Fatalf("no regex family fits %v", params)
+	return ""
+}
+
+func matchesAll(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if !re.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(re *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if re.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// nearMisses returns answers to a puzzle that are wrong, each with a name:
+// the trivial answers and ones a small step from a correct answer.
+func nearMisses(t *testing.T, p Puzzle) map[string]map[string]interface{} {
+	t.Helper()
+
+	params := p.Parameters()
+	answer := solvePuzzle(t, p)
+	misses := map[string]map[string]interface{}{
+		"no inputs": {},
+	}
+
+	switch p.Type() {
+	case "xor":
+		count, _ := strconv.Atoi(params["count"])
+		zeros := make([]int, count)
+		misses["all zeros"] = xorInputs(zeros)
+
+		numbers := make([]int, count)
+		for i := range numbers {
+			numbers[i], _ = strconv.Atoi(answer[fmt.Sprintf("input_%d", i+1)].(string))
+		}
+		offByOne := append([]int(nil), numbers...)
+		offByOne[count-1] ^= 1
+		if containsInt(offByOne[:count-1], offByOne[count-1]) || offByOne[count-1] == 0 {
+			offByOne[count-1] ^= 3
+		}
+		misses["off by one bit"] = xorInputs(offByOne)
+
+		pairs := append([]int(nil), numbers...)
+		pairs[1] = pairs[0]
+		misses["repeated number"] = xorInputs(pairs)
+
+		outOfRange := append([]int(nil), numbers...)
+		outOfRange[0] += 256
+		misses["out of range"] = xorInputs(outOfRange)
+		misses["missing input"] = xorInputs(numbers[:count-1])
+	case "caesar", "vigenere":
+		plaintext := answer["plaintext"].(string)
+		misses["ciphertext"] = map[string]interface{}{"plaintext": params["ciphertext"]}
+		misses["shifted by one"] = map[string]interface{}{"plaintext": shiftLetters(plaintext, func(int) int { return 1 })}
+		misses["last word missing"] = map[string]interface{}{"plaintext": plaintext[:strings.LastIndex(plaintext, " ")]}
+	case "toposort":
+		order := splitList(answer["order"].(string))
+		reversed := make([]string, len(order))
+		for i, node := range order {
+			reversed[len(order)-1-i] = node
+		}
+		misses["reversed"] = map[string]interface{}{"order": strings.Join(reversed, ",")}
+		misses["alphabetical"] = map[string]interface{}{"order": strings.Join(sortedNodes(params), ",")}
+		misses["missing node"] = map[string]interface{}{"order": strings.Join(order[:len(order)-1], ",")}
+		misses["repeated node"] = map[string]interface{}{"order": strings.Join(append(order, order[0]), ",")}
+		misses["unknown node"] = map[string]interface{}{"order": strings.Join(append(order, "mainframe"), ",")}
+	case "cidr_carving":
+		subnets := splitList(answer["subnets"].(string))
+		misses["parent"] = map[string]interface{}{"subnets": params["parent"]}
+		misses["missing subnet"] = map[string]interface{}{"subnets": strings.Join(subnets[:len(subnets)-1], ",")}
+		overlapping := append([]string(nil), subnets...)
+		overlapping[len(overlapping)-1] = overlapping[0]
+		misses["overlapping"] = map[string]interface{}{"subnets": strings.Join(overlapping, ",")}
+		hostBits := append([]string(nil), subnets...)
+		last := netip.MustParsePrefix(hostBits[len(hostBits)-1])
+		hostBits[len(hostBits)-1] = netip.PrefixFrom(last.Addr().Next(), last.Bits()).String()
+		misses["host bits set"] = map[string]interface{}{"subnets": strings.Join(hostBits, ",")}
+		outside := append([]string(nil), subnets...)
+		first := netip.MustParsePrefix(outside[0])
+		outside[0] = netip.PrefixFrom(uint32IPv4(ipv4Uint(first.Addr())^0x00800000), first.Bits()).String()
+		misses["outside the parent"] = map[string]interface{}{"subnets": strings.Join(outside, ",")}
+	case "regex_golf":
+		pattern := answer["pattern"].(string)
+		misses["match everything"] = map[string]interface{}{"pattern": ".*"}
+		misses["unanchored"] = map[string]interface{}{"pattern": strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$")}
+		misses["too long"] = map[string]interface{}{"pattern": "(?:" + pattern + ")"}
+		misses["invalid"] = map[string]interface{}{"pattern": "(" + pattern}
+	}
+	return misses
+}
+
+func sortedNodes(params map[string]string) []string {
+	nodes := make([]string, 0, len(params))
+	for node := range params {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func TestPuzzleTypes(t *testing.T) {
+	expected := []string{"caesar", "cidr_carving", "regex_golf", "toposort", "vigenere", "xor"}
+	if actual := PuzzleTypes(); strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected puzzle types %v, got %v", expected, actual)
+	}
+	if _, ok := Puzzles[DefaultPuzzleType]; !ok {
+		t.Fatalf("default puzzle type %q is not registered", DefaultPuzzleType)
+	}
+}
+
+func TestClassicPuzzles(t *testing.T) {
+	for _, puzzleType := range PuzzleTypes() {
+		p := Puzzles[puzzleType]
+		t.Run(puzzleType, func(t *testing.T) {
+			if p.Type() != puzzleType || p.Name() == "" || p.Prompt() == "" || p.InputSchema() == "" {
+				t.Fatalf("puzzle %q is missing its type, name, prompt or input schema", puzzleType)
+			}
+			if !regexp.MustCompile(`^flag\{\w+\}$`).MatchString(p.Flag()) {
+				t.Fatalf("puzzle %q has flag %q", puzzleType, p.Flag())
+			}
+
+			answer := solvePuzzle(t, p)
+			if ok, message := p.Check(answer); !ok {
+				t.Fatalf("answer %v rejected: %s", answer, message)
+			}
+			for name, inputs := range nearMisses(t, p) {
+				if ok, _ := p.Check(inputs); ok {
+					t.Errorf("%s: wrong answer %v accepted", name, inputs)
+				}
+			}
+		})
+	}
+}
+
+func TestClassicXORPuzzle(t *testing.T) {
+	p := Puzzles["xor"]
+	cases := map[string]struct {
+		inputs   []int
+		solved   bool
+		expected string
+	}{
+		"solved": {
+			inputs:   []int{15, 23, 42, 56, 10},
+			solved:   true,
+			expected: "Puzzle solved! XOR of all inputs equals zero.",
+		},
+		"all zeros": {
+			inputs:   []int{0, 0, 0, 0, 0},
+			expected: "input_1 must be between 1 and 255, got 0",
+		},
+		"cancelling pairs": {
+			inputs:   []int{7, 7, 9, 9, 12},
+			expected: "input_2 repeats input_1 (7); every number must be distinct",
+		},
+		"near miss": {
+			inputs:   []int{15, 23, 42, 56, 11},
+			expected: "XOR result: 1 (must be 0). Try again!",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			solved, message := p.Check(xorInputs(tc.inputs))
+			if solved != tc.solved || message != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%t %q\n\ngot:\n\n%t %q\n\n", tc.solved, tc.expected, solved, message)
+			}
+		})
+	}
+}
+
+func TestClassicPlaintextNormalised(t *testing.T) {
+	for inputs, solved := range map[string]bool{
+		"  Infrastructure   AS code ": true,
+		"infrastructure as code":      true,
+		"infrastructure-as-code":      false,
+		"infrastructure as codes":     false,
+	} {
+		if ok, message := Puzzles["caesar"].Check(map[string]interface{}{"plaintext": inputs}); ok != solved {
+			t.Errorf("plaintext %q: expected solved=%t, got %t: %s", inputs, solved, ok, message)
+		}
+	}
+}
+
+// puzzlePlayers are the players generated puzzles are tested for.
+var puzzlePlayers = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi"}
+
+func TestGeneratedPuzzles(t *testing.T) {
+	for _, puzzleType := range PuzzleTypes() {
+		for _, player := range puzzlePlayers {
+			t.Run(puzzleType+"/"+player, func(t *testing.T) {
+				p, err := GeneratePuzzle(puzzleType, player, "test-event")
+				if err != nil {
+					t.Fatalf("error generating puzzle: %s", err)
+				}
+				if p.Type() != puzzleType || p.Flag() != Puzzles[puzzleType].Flag() {
+					t.Fatalf("generated %q puzzle has type %q and flag %q", puzzleType, p.Type(), p.Flag())
+				}
+
+				answer := solvePuzzle(t, p)
+				if ok, message := p.Check(answer); !ok {
+					t.Fatalf("answer %v rejected: %s\n\nprompt: %s", answer, message, p.Prompt())
+				}
+				for name, inputs := range nearMisses(t, p) {
+					if ok, _ := p.Check(inputs); ok {
+						t.Errorf("%s: wrong answer %v accepted\n\nprompt: %s", name, inputs, p.Prompt())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGeneratedPuzzlesDeterministic(t *testing.T) {
+	for _, puzzleType := range PuzzleTypes() {
+		t.Run(puzzleType, func(t *testing.T) {
+			first, err := GeneratePuzzle(puzzleType, "alice", "test-event")
+			if err != nil {
+				t.Fatalf("error generating puzzle: %s", err)
+			}
+			second, _ := GeneratePuzzle(puzzleType, "alice", "test-event")
+			if !reflect.DeepEqual(first, second) {
+				t.Fatalf("same seed gave different puzzles:\n\n%s\n\n%s", first.Prompt(), second.Prompt())
+			}
+
+			prompts := map[string]bool{first.Prompt(): true}
+			for _, player := range puzzlePlayers[1:] {
+				p, _ := GeneratePuzzle(puzzleType, player, "test-event")
+				prompts[p.Prompt()] = true
+			}
+			other, _ := GeneratePuzzle(puzzleType, "alice", "other-event")
+			prompts[other.Prompt()] = true
+			if len(prompts) < len(puzzlePlayers) {
+				t.Fatalf("expected nearly every player and event to get its own puzzle, got %d distinct puzzles for %d seeds", len(prompts), len(puzzlePlayers)+1)
+			}
+		})
+	}
+}
+
+func TestPuzzleSeed(t *testing.T) {
+	seed := PuzzleSeed("xor", "alice", "test-event")
+	if seed != PuzzleSeed("xor", "alice", "test-event") {
+		t.Fatal("PuzzleSeed is not deterministic")
+	}
+	for name, other := range map[string]int64{
+		"type":   PuzzleSeed("caesar", "alice", "test-event"),
+		"player": PuzzleSeed("xor", "bob", "test-event"),
+		"salt":   PuzzleSeed("xor", "alice", "other-event"),
+		"joined": PuzzleSeed("xor", "alicetest-event", ""),
+	} {
+		if other == seed {
+			t.Errorf("changing the %s does not change the seed", name)
+		}
+	}
+}
+
+func TestGeneratePuzzleUnknownType(t *testing.T) {
+	_, err := GeneratePuzzle("sudoku", "alice", "test-event")
+	expected := `unknown puzzle_type "sudoku" (expected one of caesar, cidr_carving, regex_golf, toposort, vigenere, xor)`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got %v", expected, err)
+	}
+}
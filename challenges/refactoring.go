@@ -0,0 +1,410 @@
+package challenges
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	registerRefactoringChallenges()
+}
+
+func registerRefactoringChallenges() {
+	// Count to For Each Migration
+	Challenges["count_to_for_each"] = &Challenge{
+		ID:          "count_to_for_each",
+		Name:        "Count to For Each Migration",
+		Description: "Migrate a count-based set of ctfchallenge_puzzle_box resources to for_each with moved blocks, without destroying any box",
+		Points:      200,
+		Flag:        "flag{m0v3d_w1th0ut_d3str0y}",
+		Difficulty:  "intermediate",
+		Category:    "refactoring",
+		Validator:   NewStructuredValidator(validateCountToForEachStructure, ProofKindRefactor),
+	}
+
+	// Import Block
+	Challenges["import_block"] = &Challenge{
+		ID:          "import_block",
+		Name:        "Import Block Adopter",
+		Description: "Bring an existing ctfchallenge_flag_validator completion record under management with an import block",
+		Points:      150,
+		Flag:        "flag{1mp0rt_bl0ck_4d0pt3r}",
+		Difficulty:  "intermediate",
+		Category:    "refactoring",
+		Validator:   NewStructuredValidator(validateImportBlockStructure, ProofKindRefactor),
+	}
+
+	// Removed Block
+	Challenges["removed_block"] = &Challenge{
+		ID:          "removed_block",
+		Name:        "Graceful Removal",
+		Description: "Stop managing a resource with a removed block while leaving the real object in place",
+		Points:      150,
+		Flag:        "flag{r3m0v3d_n0t_d3str0y3d}",
+		Difficulty:  "intermediate",
+		Category:    "refactoring",
+		Validator:   NewStructuredValidator(validateRemovedBlockStructure, ProofKindRefactor),
+	}
+
+	// Refactor Master
+	Challenges["refactor_master"] = &Challenge{
+		ID:          "refactor_master",
+		Name:        "Refactor Master",
+		Description: "Combine moved, import and removed blocks in a single refactor that leaves every address accounted for",
+		Points:      300,
+		Flag:        "flag{r3f4ct0r_gr4ndm4st3r}",
+		Difficulty:  "advanced",
+		Category:    "refactoring",
+		Validator:   NewStructuredValidator(validateRefactorMasterStructure, ProofKindRefactor),
+	}
+}
+
+func validateCountToForEachStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkMovedMappings(&result, proof.Refactor, "ctfchallenge_puzzle_box", 2) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{m0v3d_w1th0ut_d3str0y}"
+	result.Message = "✓ Migration completed! Every puzzle box moved to its for_each key without being replaced."
+	return result
+}
+
+func validateImportBlockStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkImports(&result, proof.Refactor) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{1mp0rt_bl0ck_4d0pt3r}"
+	result.Message = "✓ Import block mastered! The existing completion record is now managed by your configuration."
+	return result
+}
+
+func validateRemovedBlockStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkRemoved(&result, proof.Refactor) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{r3m0v3d_n0t_d3str0y3d}"
+	result.Message = "✓ Graceful removal completed! The resource left your state without being destroyed."
+	return result
+}
+
+func validateRefactorMasterStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkMovedMappings(&result, proof.Refactor, "", 1) {
+		return result
+	}
+	if !checkImports(&result, proof.Refactor) {
+		return result
+	}
+	if !checkRemoved(&result, proof.Refactor) {
+		return result
+	}
+
+	// Every previous address must still exist, have moved, or be removed
+	moved := make(map[string]bool)
+	for _, m := range proof.Refactor.Moved {
+		moved[normalizeAddress(m.From)] = true
+	}
+	removed := make(map[string]bool)
+	for _, r := range proof.Refactor.Removed {
+		removed[normalizeAddress(r.From)] = true
+	}
+	current := addressSet(proof.Refactor.CurrentAddresses)
+
+	unaccounted := []string{}
+	for _, addr := range proof.Refactor.PreviousAddresses {
+		a := normalizeAddress(addr)
+		if !current[a] && !moved[a] && !removed[resourceAddress(a)] {
+			unaccounted = append(unaccounted, a)
+		}
+	}
+	if len(unaccounted) > 0 {
+		result.failCount("refactor.accounted", fmt.Sprintf("Addresses would be destroyed by this refactor: %s", strings.Join(unaccounted, ", ")),
+			"0", len(unaccounted), "Add a moved block for renamed addresses or a removed block for resources you no longer manage")
+		return result
+	}
+	result.pass("refactor.accounted", fmt.Sprintf("All %d previous addresses are accounted for", len(proof.Refactor.PreviousAddresses)))
+
+	result.Success = true
+	result.Flag = "flag{r3f4ct0r_gr4ndm4st3r}"
+	result.Message = "✓ Refactor mastered! Nothing is destroyed, nothing is orphaned."
+	return result
+}
+
+// checkMovedMappings verifies that every previous count-indexed address of
+// resourceType (any type when empty) maps to a for_each instance through a
+// moved block. At least minInstances such addresses are required.
+func checkMovedMappings(result *ValidationResult, refactor *RefactorProof, resourceType string, minInstances int) bool {
+	previous := []string{}
+	for _, addr := range refactor.PreviousAddresses {
+		parsed, ok := parseAddress(addr)
+		if ok && parsed.countIndexed() && (resourceType == "" || parsed.Type == resourceType) {
+			previous = append(previous, parsed.String())
+		}
+	}
+
+	what := "count-indexed"
+	if resourceType != "" {
+		what = "count-indexed " + resourceType
+	}
+	if len(previous) < minInstances {
+		result.failCount("refactor.previous_addresses", fmt.Sprintf("Need at least %d %s address(es) before the refactor (found %d)", minInstances, what, len(previous)),
+			fmt.Sprintf(">= %d", minInstances), len(previous), "List the old addresses, e.g. ctfchallenge_puzzle_box.box[0], in previous_addresses")
+		return false
+	}
+	result.pass("refactor.previous_addresses", fmt.Sprintf("Found %d %s address(es) to migrate", len(previous), what))
+
+	movedTo := make(map[string]string)
+	for _, m := range refactor.Moved {
+		movedTo[normalizeAddress(m.From)] = normalizeAddress(m.To)
+	}
+	current := addressSet(refactor.CurrentAddresses)
+	targets := make(map[string]string)
+
+	for i, from := range previous {
+		id := fmt.Sprintf("moved.%d", i+1)
+
+		to, ok := movedTo[from]
+		if !ok {
+			result.fail(id+".from", fmt.Sprintf("%s has no moved block and would be destroyed", from),
+				fmt.Sprintf("moved { from = %s, to = ... }", from))
+			return false
+		}
+
+		parsed, ok := parseAddress(to)
+		if !ok || !parsed.keyed() {
+			result.fail(id+".to", fmt.Sprintf("moved block for %s targets %s, which is not a for_each instance", from, to),
+				"Move to a string key, e.g. ctfchallenge_puzzle_box.box[\"easy\"]")
+			return false
+		}
+
+		if !current[to] {
+			result.fail(id+".target_exists", fmt.Sprintf("moved block target %s is not in current_addresses", to),
+				"The new address must exist in the configuration after the refactor")
+			return false
+		}
+
+		if other, dup := targets[to]; dup {
+			result.fail(id+".unique", fmt.Sprintf("%s and %s both move to %s", other, from, to),
+				"Each old instance must map to its own for_each key")
+			return false
+		}
+		targets[to] = from
+
+		result.pass(id+".from", fmt.Sprintf("%s moves to %s", from, to))
+	}
+	return true
+}
+
+// checkImports verifies that at least one import block adopts an existing
+// ctfchallenge_flag_validator completion record.
+func checkImports(result *ValidationResult, refactor *RefactorProof) bool {
+	if len(refactor.Imports) == 0 {
+		result.fail("import.present", "No import blocks found",
+			"Example: import { to = ctfchallenge_flag_validator.basics, id = \"terraform_basics\" }")
+		return false
+	}
+	result.pass("import.present", fmt.Sprintf("Found %d import block(s)", len(refactor.Imports)))
+
+	current := addressSet(refactor.CurrentAddresses)
+	previous := addressSet(refactor.PreviousAddresses)
+
+	for i, imp := range refactor.Imports {
+		id := fmt.Sprintf("import.%d", i+1)
+		to := normalizeAddress(imp.To)
+
+		parsed, ok := parseAddress(to)
+		if !ok || parsed.Type != "ctfchallenge_flag_validator" {
+			result.fail(id+".to", fmt.Sprintf("Import block %d targets %s, not a ctfchallenge_flag_validator", i+1, to),
+				"Import an existing completion record into a ctfchallenge_flag_validator resource")
+			return false
+		}
+
+		if !knownRecordID(imp.ID) {
+			result.AddCheck(Check{
+				ID:       id + ".id",
+				Status:   CheckFail,
+				Expected: "<challenge_id> or <challenge_id>-<suffix>",
+				Actual:   imp.ID,
+				Message:  fmt.Sprintf("Import block %d has an id that does not identify a completion record", i+1),
+				Hint:     "Use a challenge ID or the ID of an existing ctfchallenge_flag_validator",
+			})
+			result.Message = fmt.Sprintf("Import block %d has an id that does not identify a completion record", i+1)
+			return false
+		}
+
+		if !current[to] {
+			result.fail(id+".target_exists", fmt.Sprintf("Import target %s is not in current_addresses", to),
+				"Declare the resource block the record is imported into")
+			return false
+		}
+
+		if previous[to] {
+			result.fail(id+".unmanaged", fmt.Sprintf("%s was already managed before the refactor", to),
+				"Import blocks adopt objects that are not yet in state")
+			return false
+		}
+
+		result.pass(id+".to", fmt.Sprintf("Imports %s into %s", imp.ID, to))
+	}
+	return true
+}
+
+// checkRemoved verifies that at least one removed block forgets a previously
+// managed resource without destroying it.
+func checkRemoved(result *ValidationResult, refactor *RefactorProof) bool {
+	if len(refactor.Removed) == 0 {
+		result.fail("removed.present", "No removed blocks found",
+			"Example: removed { from = ctfchallenge_puzzle_box.legacy, lifecycle { destroy = false } }")
+		return false
+	}
+	result.pass("removed.present", fmt.Sprintf("Found %d removed block(s)", len(refactor.Removed)))
+
+	current := addressSet(refactor.CurrentAddresses)
+
+	for i, r := range refactor.Removed {
+		id := fmt.Sprintf("removed.%d", i+1)
+		from := normalizeAddress(r.From)
+
+		parsed, ok := parseAddress(from)
+		if !ok || parsed.Index != "" {
+			result.fail(id+".from", fmt.Sprintf("Removed block %d has from = %s, which is not a resource address", i+1, from),
+				"Removed blocks take a resource address without an instance key")
+			return false
+		}
+
+		wasManaged := false
+		for _, addr := range refactor.PreviousAddresses {
+			if resourceAddress(normalizeAddress(addr)) == from {
+				wasManaged = true
+				break
+			}
+		}
+		if !wasManaged {
+			result.fail(id+".previous", fmt.Sprintf("%s was not managed before the refactor", from),
+				"Only resources in previous_addresses can be removed")
+			return false
+		}
+
+		for addr := range current {
+			if resourceAddress(addr) == from {
+				result.fail(id+".absent", fmt.Sprintf("%s is still declared in the configuration", from),
+					"Delete the resource block when adding a removed block for it")
+				return false
+			}
+		}
+
+		if r.Destroy {
+			result.fail(id+".destroy", fmt.Sprintf("Removed block for %s would destroy the object", from),
+				"Set lifecycle { destroy = false } to keep the real object")
+			return false
+		}
+
+		result.pass(id+".from", fmt.Sprintf("%s is forgotten without being destroyed", from))
+	}
+	return true
+}
+
+// knownRecordID reports whether id is a challenge ID, or a challenge ID
+// followed by a -<suffix> as used by ctfchallenge_flag_validator IDs.
+func knownRecordID(id string) bool {
+	if _, exists := Challenges[id]; exists {
+		return true
+	}
+	if i := strings.LastIndex(id, "-"); i > 0 && i < len(id)-1 {
+		_, exists := Challenges[id[:i]]
+		return exists
+	}
+	return false
+}
+
+// address is a parsed resource instance address
+type address struct {
+	Module string
+	Type   string
+	Name   string
+	Index  string
+}
+
+var addressPattern = regexp.MustCompile(`^((?:module\.[A-Za-z0-9_-]+(?:\[[^\]]+\])?\.)*)([A-Za-z0-9_]+)\.([A-Za-z0-9_-]+)(?:\[([^\]]+)\])?$`)
+
+func parseAddress(s string) (address, bool) {
+	m := addressPattern.FindStringSubmatch(normalizeAddress(s))
+	if m == nil {
+		return address{}, false
+	}
+	return address{Module: m[1], Type: m[2], Name: m[3], Index: m[4]}, true
+}
+
+func (a address) String() string {
+	s := a.Module + a.Type + "." + a.Name
+	if a.Index != "" {
+		s += "[" + a.Index + "]"
+	}
+	return s
+}
+
+// countIndexed reports whether the address has a numeric count index
+func (a address) countIndexed() bool {
+	if a.Index == "" {
+		return false
+	}
+	for _, ch := range a.Index {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// keyed reports whether the address has a for_each string key
+func (a address) keyed() bool {
+	return len(a.Index) >= 2 && strings.HasPrefix(a.Index, `"`) && strings.HasSuffix(a.Index, `"`)
+}
+
+// normalizeAddress strips whitespace so that addresses compare equal
+// regardless of formatting
+func normalizeAddress(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// resourceAddress strips the instance key from an address
+func resourceAddress(s string) string {
+	if a, ok := parseAddress(s); ok {
+		a.Index = ""
+		return a.String()
+	}
+	return s
+}
+
+func addressSet(addrs []string) map[string]bool {
+	set := make(map[string]bool, len(addrs))
+	for _, addr := range addrs {
+		set[normalizeAddress(addr)] = true
+	}
+	return set
+}
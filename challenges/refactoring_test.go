@@ -0,0 +1,240 @@
+package challenges
+
+import (
+	"testing"
+)
+
+// countMigration is a refactor moving two count-indexed puzzle boxes to
+// for_each keys.
+func countMigration() *RefactorProof {
+	return &RefactorProof{
+		PreviousAddresses: []string{"ctfchallenge_puzzle_box.box[0]", "ctfchallenge_puzzle_box.box[1]"},
+		CurrentAddresses:  []string{`ctfchallenge_puzzle_box.box["easy"]`, `ctfchallenge_puzzle_box.box["hard"]`},
+		Moved: []MovedBlock{
+			{From: "ctfchallenge_puzzle_box.box[0]", To: `ctfchallenge_puzzle_box.box["easy"]`},
+			{From: "ctfchallenge_puzzle_box.box[ 1 ]", To: `ctfchallenge_puzzle_box.box["hard"]`},
+		},
+	}
+}
+
+// fullRefactor is a refactor combining moved, import and removed blocks.
+func fullRefactor() *RefactorProof {
+	refactor := countMigration()
+	refactor.PreviousAddresses = append(refactor.PreviousAddresses, "ctfchallenge_puzzle_box.legacy", "ctfchallenge_flag_validator.kept")
+	refactor.CurrentAddresses = append(refactor.CurrentAddresses, "ctfchallenge_flag_validator.kept", "ctfchallenge_flag_validator.basics")
+	refactor.Imports = []ImportBlock{{To: "ctfchallenge_flag_validator.basics", ID: "terraform_basics"}}
+	refactor.Removed = []RemovedBlock{{From: "ctfchallenge_puzzle_box.legacy"}}
+	return refactor
+}
+
+func TestRefactoringValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		refactor  func(refactor *RefactorProof)
+		failed    string
+	}{
+		"count to for_each": {
+			challenge: "count_to_for_each",
+			refactor:  func(refactor *RefactorProof) {},
+		},
+		"one instance": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.PreviousAddresses = refactor.PreviousAddresses[:1]
+			},
+			failed: "refactor.previous_addresses",
+		},
+		"other resource type": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.PreviousAddresses = []string{"ctfchallenge_flag_validator.v[0]", "ctfchallenge_flag_validator.v[1]"}
+			},
+			failed: "refactor.previous_addresses",
+		},
+		"instance without a moved block": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.Moved = refactor.Moved[:1]
+			},
+			failed: "moved.2.from",
+		},
+		"moved to a count index": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.Moved[1].To = "ctfchallenge_puzzle_box.box[3]"
+			},
+			failed: "moved.2.to",
+		},
+		"moved to a missing key": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.Moved[1].To = `ctfchallenge_puzzle_box.box["medium"]`
+			},
+			failed: "moved.2.target_exists",
+		},
+		"two instances on one key": {
+			challenge: "count_to_for_each",
+			refactor: func(refactor *RefactorProof) {
+				refactor.Moved[1].To = refactor.Moved[0].To
+			},
+			failed: "moved.2.unique",
+		},
+		"import": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+			},
+		},
+		"import of a validator id": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports[0].ID = "terraform_basics-1a2b"
+			},
+		},
+		"no imports": {
+			challenge: "import_block",
+			failed:    "import.present",
+		},
+		"import into another type": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports[0].To = "ctfchallenge_puzzle_box.basics"
+			},
+			failed: "import.1.to",
+		},
+		"import of an unknown record": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports[0].ID = "no_such_challenge"
+			},
+			failed: "import.1.id",
+		},
+		"import without a resource block": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports[0].To = "ctfchallenge_flag_validator.missing"
+			},
+			failed: "import.1.target_exists",
+		},
+		"import of a managed resource": {
+			challenge: "import_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports[0].To = "ctfchallenge_flag_validator.kept"
+			},
+			failed: "import.1.unmanaged",
+		},
+		"removed": {
+			challenge: "removed_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+			},
+		},
+		"no removed blocks": {
+			challenge: "removed_block",
+			failed:    "removed.present",
+		},
+		"removed instance": {
+			challenge: "removed_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Removed[0].From = "ctfchallenge_puzzle_box.box[0]"
+			},
+			failed: "removed.1.from",
+		},
+		"removed unmanaged resource": {
+			challenge: "removed_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Removed[0].From = "ctfchallenge_puzzle_box.never"
+			},
+			failed: "removed.1.previous",
+		},
+		"removed but still declared": {
+			challenge: "removed_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Removed[0].From = "ctfchallenge_flag_validator.kept"
+			},
+			failed: "removed.1.absent",
+		},
+		"removed and destroyed": {
+			challenge: "removed_block",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Removed[0].Destroy = true
+			},
+			failed: "removed.1.destroy",
+		},
+		"refactor master": {
+			challenge: "refactor_master",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+			},
+		},
+		"refactor master without imports": {
+			challenge: "refactor_master",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.Imports = nil
+			},
+			failed: "import.present",
+		},
+		"refactor master dropping an address": {
+			challenge: "refactor_master",
+			refactor: func(refactor *RefactorProof) {
+				*refactor = *fullRefactor()
+				refactor.PreviousAddresses = append(refactor.PreviousAddresses, "ctfchallenge_puzzle_box.orphan")
+			},
+			failed: "refactor.accounted",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			refactor := countMigration()
+			if tc.refactor != nil {
+				tc.refactor(refactor)
+			}
+			expectValidation(t, tc.challenge, &ProofData{Refactor: refactor}, tc.failed)
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	cases := map[string]struct {
+		address  string
+		ok       bool
+		expected string
+		count    bool
+		keyed    bool
+	}{
+		"resource":       {address: "ctfchallenge_puzzle_box.box", ok: true, expected: "ctfchallenge_puzzle_box.box"},
+		"count index":    {address: "ctfchallenge_puzzle_box.box[ 2 ]", ok: true, expected: "ctfchallenge_puzzle_box.box[2]", count: true},
+		"for_each key":   {address: `ctfchallenge_puzzle_box.box["easy"]`, ok: true, expected: `ctfchallenge_puzzle_box.box["easy"]`, keyed: true},
+		"module":         {address: `module.net["a"].aws_vpc.main`, ok: true, expected: `module.net["a"].aws_vpc.main`},
+		"unquoted key":   {address: "ctfchallenge_puzzle_box.box[easy]", ok: true, expected: "ctfchallenge_puzzle_box.box[easy]"},
+		"type only":      {address: "ctfchallenge_puzzle_box"},
+		"too many parts": {address: "data.http.site.extra"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			parsed, ok := parseAddress(tc.address)
+			if ok != tc.ok {
+				t.Fatalf("expected ok=%t, got %t", tc.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if parsed.String() != tc.expected || parsed.countIndexed() != tc.count || parsed.keyed() != tc.keyed {
+				t.Fatalf("\n\nexpected:\n\n%s count=%t keyed=%t\n\ngot:\n\n%s count=%t keyed=%t\n\n",
+					tc.expected, tc.count, tc.keyed, parsed, parsed.countIndexed(), parsed.keyed())
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+)
+
+// FlakyEvent is one operation recorded for a ctfchallenge_flaky.
+type FlakyEvent struct {
+	// Operation is create, update or delete
+	Operation string
+	// ObjectID is empty for a create that left nothing behind
+	ObjectID string
+	// Outcome is ok, failed, partial or timeout
+	Outcome        string
+	Attempt        int
+	Mode           string
+	DelaySeconds   int
+	TimeoutSeconds int
+}
+
+// FlakyProof is the operation history of a ctfchallenge_flaky, recorded by
+// the provider rather than reported by the player.
+type FlakyProof struct {
+	ObjectID    string
+	Name        string
+	ChallengeID string
+	// Status is healthy, or partial for an object a failed create left behind
+	Status  string
+	History []FlakyEvent
+}
+
+// ResilienceModes are the failure_mode each resilience challenge is played
+// with.
+var ResilienceModes = map[string]string{
+	"retry_create":     "fail_nth_create",
+	"tainted_recovery": "partial_state",
+	"update_retry":     "fail_on_update",
+	"timeout_tuning":   "none",
+}
+
+// timeoutTuningDelay is the minimum create delay for timeout_tuning, longer
+// than ctfchallenge_flaky's default create timeout.
+const timeoutTuningDelay = 5
+
+func init() {
+	registerResilienceChallenges()
+}
+
+func registerResilienceChallenges() {
+	// Failed create
+	Challenges["retry_create"] = &Challenge{
+		ID:          "retry_create",
+		Name:        "Third Time Lucky",
+		Description: "A ctfchallenge_flaky with failure_mode = \"fail_nth_create\" fails its create. Get it created anyway",
+		Points:      150,
+		Flag:        "flag{f41l3d_cr34t3_l34v3s_n0_tr4c3}",
+		Difficulty:  "beginner",
+		Category:    "resilience",
+		Validator:   NewStructuredValidator(validateRetryCreate, ProofKindFlaky),
+	}
+
+	// Tainted resource
+	Challenges["tainted_recovery"] = &Challenge{
+		ID:          "tainted_recovery",
+		Name:        "Phoenix",
+		Description: "A ctfchallenge_flaky with failure_mode = \"partial_state\" fails halfway through its create and is tainted. Recover a healthy object",
+		Points:      200,
+		Flag:        "flag{t41nt3d_r1s3s_fr0m_th3_4sh3s}",
+		Difficulty:  "intermediate",
+		Category:    "resilience",
+		Validator:   NewStructuredValidator(validateTaintedRecovery, ProofKindFlaky),
+	}
+
+	// Failed update
+	Challenges["update_retry"] = &Challenge{
+		ID:          "update_retry",
+		Name:        "Second Attempt",
+		Description: "A ctfchallenge_flaky with failure_mode = \"fail_on_update\" fails an update. Finish the update without replacing the object",
+		Points:      225,
+		Flag:        "flag{upd4t3_f41l3d_upd4t3_r3tr13d}",
+		Difficulty:  "intermediate",
+		Category:    "resilience",
+		Validator:   NewStructuredValidator(validateUpdateRetry, ProofKindFlaky),
+	}
+
+	// timeouts block
+	Challenges["timeout_tuning"] = &Challenge{
+		ID:          "timeout_tuning",
+		Name:        "Patience",
+		Description: fmt.Sprintf("Create a ctfchallenge_flaky that takes at least %d seconds to create, longer than its default create timeout", timeoutTuningDelay),
+		Points:      250,
+		Flag:        "flag{t1m30uts_c0nf1gur3d_p4t13nc3}",
+		Difficulty:  "advanced",
+		Category:    "resilience",
+		Validator:   NewStructuredValidator(validateTimeoutTuning, ProofKindFlaky),
+	}
+}
+
+// FlakyChallengeIDs returns the challenges served by ctfchallenge_flaky, sorted.
+func FlakyChallengeIDs() []string {
+	ids := make([]string, 0, len(ResilienceModes))
+	for id := range ResilienceModes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkFlakyObject checks that the observed object was created for the
+// challenge being validated and returns the index of the event that created
+// it, or -1.
+func checkFlakyObject(result *ValidationResult, proof *FlakyProof, challengeID string) int {
+	if proof.ChallengeID != challengeID {
+		result.fail("object.challenge", fmt.Sprintf("Flaky object %s was created for %s, not %s", proof.ObjectID, proof.ChallengeID, challengeID),
+			fmt.Sprintf("Set challenge_id = \"%s\" on the ctfchallenge_flaky", challengeID))
+		return -1
+	}
+	result.pass("object.challenge", fmt.Sprintf("Flaky object %s belongs to %s", proof.ObjectID, challengeID))
+
+	for i, event := range proof.History {
+		if event.Operation == "create" && event.ObjectID == proof.ObjectID {
+			return i
+		}
+	}
+	result.fail("object.history", fmt.Sprintf("No recorded create for flaky object %s", proof.ObjectID),
+		"The history only keeps recent events. Recreate the object with terraform apply -replace")
+	return -1
+}
+
+// flakyEventBefore reports whether an event before index end matches.
+func flakyEventBefore(history []FlakyEvent, end int, match func(FlakyEvent) bool) (FlakyEvent, bool) {
+	for _, event := range history[:end] {
+		if match(event) {
+			return event, true
+		}
+	}
+	return FlakyEvent{}, false
+}
+
+func validateRetryCreate(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	flaky := proof.Flaky
+	created := checkFlakyObject(&result, flaky, "retry_create")
+	if created < 0 {
+		return result
+	}
+
+	failed, ok := flakyEventBefore(flaky.History, created, func(e FlakyEvent) bool {
+		return e.Operation == "create" && e.Outcome == "failed" && e.Mode == "fail_nth_create"
+	})
+	if !ok {
+		result.fail("create.failed", "No failed create was recorded before this object was created",
+			"Set failure_mode = \"fail_nth_create\" before the object is first created, then recreate it with terraform apply -replace")
+		return result
+	}
+	result.pass("create.failed", fmt.Sprintf("Create attempt %d failed and left nothing in state", failed.Attempt))
+
+	if flaky.History[created].Outcome != "ok" {
+		result.fail("create.succeeded", "The object's create did not succeed", "Run terraform apply again")
+		return result
+	}
+	result.pass("create.succeeded", fmt.Sprintf("Create attempt %d succeeded", flaky.History[created].Attempt))
+
+	result.Success = true
+	result.Flag = "flag{f41l3d_cr34t3_l34v3s_n0_tr4c3}"
+	result.Message = "✓ Third time lucky! A create that fails without an ID leaves nothing behind, so apply simply tries again."
+	return result
+}
+
+func validateTaintedRecovery(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	flaky := proof.Flaky
+	created := checkFlakyObject(&result, flaky, "tainted_recovery")
+	if created < 0 {
+		return result
+	}
+
+	if flaky.Status != "healthy" {
+		hint := "Run terraform apply to replace the tainted object"
+		if flaky.History[created].Outcome == "partial" {
+			hint = "This is the half-built object. terraform untaint keeps it; replace it with terraform apply -replace instead"
+		}
+		result.fail("object.healthy", fmt.Sprintf("Flaky object %s is %s", flaky.ObjectID, flaky.Status), hint)
+		return result
+	}
+	result.pass("object.healthy", fmt.Sprintf("Flaky object %s is healthy", flaky.ObjectID))
+
+	partial, ok := flakyEventBefore(flaky.History, created, func(e FlakyEvent) bool {
+		return e.Operation == "create" && e.Outcome == "partial"
+	})
+	if !ok {
+		result.fail("create.partial", "No partially created object was recorded before this object was created",
+			"Set failure_mode = \"partial_state\" before the object is first created, then recreate it with terraform apply -replace")
+		return result
+	}
+	result.pass("create.partial", fmt.Sprintf("Create attempt %d left partial object %s, which Terraform tainted", partial.Attempt, partial.ObjectID))
+
+	if _, ok := flakyEventBefore(flaky.History, created, func(e FlakyEvent) bool {
+		return e.Operation == "delete" && e.ObjectID == partial.ObjectID
+	}); !ok {
+		result.fail("partial.destroyed", fmt.Sprintf("Partial object %s was not destroyed before the healthy object was created", partial.ObjectID),
+			"Let Terraform replace the tainted object: it destroys it before creating a new one")
+		return result
+	}
+	result.pass("partial.destroyed", fmt.Sprintf("Partial object %s was destroyed", partial.ObjectID))
+
+	result.Success = true
+	result.Flag = "flag{t41nt3d_r1s3s_fr0m_th3_4sh3s}"
+	result.Message = "✓ Phoenix! Terraform tainted the half-built object and replaced it on the next apply."
+	return result
+}
+
+func validateUpdateRetry(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	flaky := proof.Flaky
+	created := checkFlakyObject(&result, flaky, "update_retry")
+	if created < 0 {
+		return result
+	}
+
+	failed := -1
+	for i := created; i < len(flaky.History); i++ {
+		event := flaky.History[i]
+		if event.Operation == "update" && event.ObjectID == flaky.ObjectID && event.Outcome == "failed" {
+			failed = i
+			break
+		}
+	}
+	if failed < 0 {
+		result.fail("update.failed", "No failed update was recorded for this object",
+			"Set failure_mode = \"fail_on_update\", then change value and apply")
+		return result
+	}
+	result.pass("update.failed", fmt.Sprintf("Update attempt %d failed", flaky.History[failed].Attempt))
+
+	for _, event := range flaky.History[failed+1:] {
+		if event.Operation == "update" && event.ObjectID == flaky.ObjectID && event.Outcome == "ok" {
+			result.pass("update.retried", fmt.Sprintf("Update attempt %d succeeded on the same object", event.Attempt))
+
+			result.Success = true
+			result.Flag = "flag{upd4t3_f41l3d_upd4t3_r3tr13d}"
+			result.Message = "✓ Second attempt! A failed update leaves the object in place, untainted, and apply retries it."
+			return result
+		}
+	}
+
+	result.fail("update.retried", "The failed update was never retried on the same object",
+		"Run terraform plan: the refresh shows the change was not applied. Apply again")
+	return result
+}
+
+func validateTimeoutTuning(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	flaky := proof.Flaky
+	created := checkFlakyObject(&result, flaky, "timeout_tuning")
+	if created < 0 {
+		return result
+	}
+
+	create := flaky.History[created]
+	if create.DelaySeconds < timeoutTuningDelay {
+		result.failCount("create.delay", fmt.Sprintf("The object took %d second(s) to create, expected at least %d", create.DelaySeconds, timeoutTuningDelay),
+			fmt.Sprintf(">= %d", timeoutTuningDelay), create.DelaySeconds,
+			fmt.Sprintf("Set delay_seconds = %d or more, then recreate the object", timeoutTuningDelay))
+		return result
+	}
+	result.pass("create.delay", fmt.Sprintf("The object took %d seconds to create", create.DelaySeconds))
+
+	if create.TimeoutSeconds < create.DelaySeconds {
+		result.fail("timeouts.create", fmt.Sprintf("The create timeout was %ds, shorter than the %ds create", create.TimeoutSeconds, create.DelaySeconds),
+			"Add a timeouts { create = \"...\" } block to the resource")
+		return result
+	}
+	result.pass("timeouts.create", fmt.Sprintf("The create timeout of %ds covered the %ds create", create.TimeoutSeconds, create.DelaySeconds))
+
+	if create.Outcome != "ok" {
+		result.fail("create.succeeded", "The object's create did not succeed", "Run terraform apply again")
+		return result
+	}
+	result.pass("create.succeeded", "The create finished within its timeout")
+
+	result.Success = true
+	result.Flag = "flag{t1m30uts_c0nf1gur3d_p4t13nc3}"
+	result.Message = "✓ Patience pays! The timeouts block gave the slow create the time it needed."
+	return result
+}
@@ -0,0 +1,166 @@
+package challenges
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlakyChallengeIDs(t *testing.T) {
+	expected := []string{"retry_create", "tainted_recovery", "timeout_tuning", "update_retry"}
+	if actual := FlakyChallengeIDs(); strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected flaky challenges %v, got %v", expected, actual)
+	}
+	for _, id := range expected {
+		if _, ok := Challenges[id]; !ok {
+			t.Fatalf("flaky challenge %q is not registered", id)
+		}
+	}
+}
+
+func TestResilienceValidators(t *testing.T) {
+	cases := map[string]struct {
+		// challenge defaults to the proof's challenge
+		challenge string
+		proof     FlakyProof
+		failed    string
+	}{
+		"retry create": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "retry_create", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", Outcome: "failed", Attempt: 1, Mode: "fail_nth_create"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 2, Mode: "fail_nth_create"},
+			}},
+		},
+		"retry create for another challenge": {
+			challenge: "retry_create",
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "update_retry", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", Outcome: "failed", Attempt: 1, Mode: "fail_nth_create"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 2, Mode: "fail_nth_create"},
+			}},
+			failed: "object.challenge",
+		},
+		"retry create without history": {
+			proof:  FlakyProof{ObjectID: "flaky-2", ChallengeID: "retry_create", Status: "healthy"},
+			failed: "object.history",
+		},
+		"retry create that never failed": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "retry_create", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "none"},
+			}},
+			failed: "create.failed",
+		},
+		"retry create failing after the create": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "retry_create", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "none"},
+				{Operation: "create", Outcome: "failed", Attempt: 2, Mode: "fail_nth_create"},
+			}},
+			failed: "create.failed",
+		},
+		"retry create failing in another mode": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "retry_create", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", Outcome: "failed", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 2, Mode: "fail_nth_create"},
+			}},
+			failed: "create.failed",
+		},
+		"retry create that timed out": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "retry_create", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", Outcome: "failed", Attempt: 1, Mode: "fail_nth_create"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "timeout", Attempt: 2, Mode: "fail_nth_create"},
+			}},
+			failed: "create.succeeded",
+		},
+		"tainted recovery": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "tainted_recovery", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "partial", Attempt: 1, Mode: "partial_state"},
+				{Operation: "delete", ObjectID: "flaky-1", Outcome: "ok"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 2, Mode: "partial_state"},
+			}},
+		},
+		"untainted partial object": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "tainted_recovery", Status: "partial", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "partial", Attempt: 1, Mode: "partial_state"},
+			}},
+			failed: "object.healthy",
+		},
+		"healthy without a partial create": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "tainted_recovery", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "none"},
+			}},
+			failed: "create.partial",
+		},
+		"partial object left behind": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "tainted_recovery", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "partial", Attempt: 1, Mode: "partial_state"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 2, Mode: "partial_state"},
+				{Operation: "delete", ObjectID: "flaky-1", Outcome: "ok"},
+			}},
+			failed: "partial.destroyed",
+		},
+		"update retry": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "update_retry", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-1", Outcome: "failed", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-1", Outcome: "ok", Attempt: 2, Mode: "fail_on_update"},
+			}},
+		},
+		"update never failed": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "update_retry", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+			}},
+			failed: "update.failed",
+		},
+		"update failed on another object": {
+			proof: FlakyProof{ObjectID: "flaky-2", ChallengeID: "update_retry", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-1", Outcome: "failed", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-2", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+			}},
+			failed: "update.failed",
+		},
+		"update retried by replacing": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "update_retry", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-1", Outcome: "failed", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "delete", ObjectID: "flaky-1", Outcome: "ok"},
+				{Operation: "create", ObjectID: "flaky-2", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+				{Operation: "update", ObjectID: "flaky-2", Outcome: "ok", Attempt: 1, Mode: "fail_on_update"},
+			}},
+			failed: "update.retried",
+		},
+		"timeout tuning": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "timeout_tuning", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "none", DelaySeconds: 5, TimeoutSeconds: 10},
+			}},
+		},
+		"timeout tuning too fast": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "timeout_tuning", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "ok", Attempt: 1, Mode: "none", DelaySeconds: 4, TimeoutSeconds: 10},
+			}},
+			failed: "create.delay",
+		},
+		"timeout tuning with the default timeout": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "timeout_tuning", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "timeout", Attempt: 1, Mode: "none", DelaySeconds: 6, TimeoutSeconds: 3},
+			}},
+			failed: "timeouts.create",
+		},
+		"timeout tuning that failed": {
+			proof: FlakyProof{ObjectID: "flaky-1", ChallengeID: "timeout_tuning", Status: "healthy", History: []FlakyEvent{
+				{Operation: "create", ObjectID: "flaky-1", Outcome: "failed", Attempt: 1, Mode: "none", DelaySeconds: 6, TimeoutSeconds: 6},
+			}},
+			failed: "create.succeeded",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			challenge := tc.challenge
+			if challenge == "" {
+				challenge = tc.proof.ChallengeID
+			}
+			expectValidation(t, challenge, &ProofData{Flaky: &tc.proof}, tc.failed)
+		})
+	}
+}
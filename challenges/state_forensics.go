@@ -0,0 +1,200 @@
+package challenges
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VaultCompartment is a flag fragment stored in a nested attribute of a
+// ctfchallenge_vault.
+type VaultCompartment struct {
+	Label    string
+	Position int
+	Fragment string
+}
+
+// VaultContents is what a ctfchallenge_vault writes into its state.
+type VaultContents struct {
+	Secret       string
+	Compartments []VaultCompartment
+}
+
+// VaultSpec describes how a state forensics challenge hides its flag.
+type VaultSpec struct {
+	// Shards is the number of vaults the flag is split across. Each vault
+	// holds one shard, selected with the vault's shard argument.
+	Shards int
+	// Contents returns what the vault for the given shard (1-based) holds.
+	Contents func(flag string, shard int) VaultContents
+	// Fragments returns the pieces of the flag in order, used to tell a
+	// player who has every piece that only the order is wrong.
+	Fragments func(flag string) []string
+	Hint      string
+}
+
+// VaultSpecs are the state forensics challenges served by ctfchallenge_vault
+var VaultSpecs = map[string]VaultSpec{
+	"sealed_secret": {
+		Shards: 1,
+		Contents: func(flag string, shard int) VaultContents {
+			return VaultContents{Secret: flag}
+		},
+		Fragments: func(flag string) []string { return []string{flag} },
+		Hint:      "The vault's secret attribute is sensitive. Read it with terraform state show, terraform show -json or nonsensitive()",
+	},
+	"nested_vault": {
+		Shards: 1,
+		Contents: func(flag string, shard int) VaultContents {
+			return VaultContents{
+				Secret:       "flag{n0t_th3_fl4g_y0u_4r3_l00k1ng_f0r}",
+				Compartments: shuffledCompartments(splitFragments(flag, 5), []int{3, 1, 5, 2, 4}),
+			}
+		},
+		Fragments: func(flag string) []string { return splitFragments(flag, 5) },
+		Hint:      "The secret is a decoy. Sort the compartment blocks by position and join their fragments",
+	},
+	"cross_state_heist": {
+		Shards: 2,
+		Contents: func(flag string, shard int) VaultContents {
+			return VaultContents{Secret: splitFragments(flag, 2)[shard-1]}
+		},
+		Fragments: func(flag string) []string { return splitFragments(flag, 2) },
+		Hint:      "Shard 1 holds the start of the flag and shard 2 the end. Read the other configuration's outputs with terraform_remote_state",
+	},
+}
+
+func init() {
+	registerStateForensicsChallenges()
+}
+
+func registerStateForensicsChallenges() {
+	// Sensitive attribute
+	Challenges["sealed_secret"] = &Challenge{
+		ID:          "sealed_secret",
+		Name:        "Sealed Secret",
+		Description: "Create a ctfchallenge_vault and recover the flag from its sensitive secret attribute",
+		Points:      150,
+		Flag:        "flag{s3ns1t1v3_1s_n0t_s3cr3t}",
+		Difficulty:  "beginner",
+		Category:    "state-forensics",
+		Validator:   NewStructuredValidator(validateRecoveredFlag("sealed_secret"), ProofKindFlag),
+	}
+
+	// Nested attributes
+	Challenges["nested_vault"] = &Challenge{
+		ID:          "nested_vault",
+		Name:        "Buried Treasure",
+		Description: "Reassemble the flag from the shuffled compartments nested in a ctfchallenge_vault's state",
+		Points:      200,
+		Flag:        "flag{n3st3d_c0mp4rtm3nts_r34ss3mbl3d}",
+		Difficulty:  "intermediate",
+		Category:    "state-forensics",
+		Validator:   NewStructuredValidator(validateRecoveredFlag("nested_vault"), ProofKindFlag),
+	}
+
+	// terraform_remote_state
+	Challenges["cross_state_heist"] = &Challenge{
+		ID:          "cross_state_heist",
+		Name:        "State Heist",
+		Description: "Create the two shards of a ctfchallenge_vault in separate configurations and join them with terraform_remote_state",
+		Points:      250,
+		Flag:        "flag{r3m0t3_st4t3_h31st_c0mpl3t3}",
+		Difficulty:  "advanced",
+		Category:    "state-forensics",
+		Validator:   NewStructuredValidator(validateRecoveredFlag("cross_state_heist"), ProofKindFlag),
+	}
+}
+
+// Vault returns the contents of a vault for a state forensics challenge.
+func Vault(challengeID string, shard int) (VaultContents, error) {
+	spec, ok := VaultSpecs[challengeID]
+	if !ok {
+		return VaultContents{}, fmt.Errorf("%s is not a state forensics challenge", challengeID)
+	}
+	if shard < 1 || shard > spec.Shards {
+		if spec.Shards == 1 {
+			return VaultContents{}, fmt.Errorf("%s has a single vault; shard must be 1", challengeID)
+		}
+		return VaultContents{}, fmt.Errorf("%s is split across %d vaults; shard must be between 1 and %d", challengeID, spec.Shards, spec.Shards)
+	}
+	return spec.Contents(Challenges[challengeID].Flag, shard), nil
+}
+
+// VaultChallengeIDs returns the challenges served by ctfchallenge_vault, sorted.
+func VaultChallengeIDs() []string {
+	ids := make([]string, 0, len(VaultSpecs))
+	for id := range VaultSpecs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// validateRecoveredFlag checks a flag recovered from a vault's state.
+func validateRecoveredFlag(challengeID string) StructuredValidatorFunc {
+	return func(proof *ProofData) ValidationResult {
+		result := ValidationResult{
+			Success: false,
+			Details: []string{},
+		}
+
+		spec := VaultSpecs[challengeID]
+		flag := Challenges[challengeID].Flag
+		recovered := strings.TrimSpace(proof.RecoveredFlag)
+
+		if !strings.HasPrefix(recovered, "flag{") || !strings.HasSuffix(recovered, "}") {
+			result.fail("flag.format", "Recovered flag is not of the form flag{...}", spec.Hint)
+			return result
+		}
+		result.pass("flag.format", "Recovered flag has the form flag{...}")
+
+		if subtle.ConstantTimeCompare([]byte(recovered), []byte(flag)) != 1 {
+			if len(recovered) == len(flag) && containsAll(recovered, spec.Fragments(flag)) {
+				result.fail("flag.value", "Recovered flag has every fragment, in the wrong order", spec.Hint)
+				return result
+			}
+			result.fail("flag.value", "Recovered flag does not match the flag hidden in the vault", spec.Hint)
+			return result
+		}
+		result.pass("flag.value", "Recovered flag matches")
+
+		result.Success = true
+		result.Flag = flag
+		result.Message = fmt.Sprintf("✓ Vault cracked! %s recovered from state.", Challenges[challengeID].Name)
+		return result
+	}
+}
+
+// splitFragments splits s into n pieces of nearly equal length.
+func splitFragments(s string, n int) []string {
+	fragments := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		fragments = append(fragments, s[i*len(s)/n:(i+1)*len(s)/n])
+	}
+	return fragments
+}
+
+// shuffledCompartments stores fragments in the given order of positions.
+func shuffledCompartments(fragments []string, order []int) []VaultCompartment {
+	labels := []string{"north", "east", "south", "west", "center"}
+	compartments := make([]VaultCompartment, 0, len(order))
+	for i, position := range order {
+		compartments = append(compartments, VaultCompartment{
+			Label:    labels[i],
+			Position: position,
+			Fragment: fragments[position-1],
+		})
+	}
+	return compartments
+}
+
+func containsAll(s string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if !strings.Contains(s, fragment) {
+			return false
+		}
+	}
+	return true
+}
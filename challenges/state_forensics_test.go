@@ -0,0 +1,181 @@
+package challenges
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// recoverFlag reassembles a challenge's flag from its vaults, the way a
+// player reading state would.
+func recoverFlag(t *testing.T, challengeID string) string {
+	t.Helper()
+
+	var flag strings.Builder
+	for shard := 1; shard <= VaultSpecs[challengeID].Shards; shard++ {
+		contents, err := Vault(challengeID, shard)
+		if err != nil {
+			t.Fatalf("error opening shard %d: %s", shard, err)
+		}
+		if len(contents.Compartments) == 0 {
+			flag.WriteString(contents.Secret)
+			continue
+		}
+
+		compartments := append([]VaultCompartment{}, contents.Compartments...)
+		sort.Slice(compartments, func(i, j int) bool { return compartments[i].Position < compartments[j].Position })
+		for _, compartment := range compartments {
+			flag.WriteString(compartment.Fragment)
+		}
+	}
+	return flag.String()
+}
+
+func TestVaultChallengeIDs(t *testing.T) {
+	expected := []string{"cross_state_heist", "nested_vault", "sealed_secret"}
+	if actual := VaultChallengeIDs(); strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected vault challenges %v, got %v", expected, actual)
+	}
+}
+
+func TestVault(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		shard     int
+		expected  string
+	}{
+		"single vault":        {challenge: "sealed_secret", shard: 1},
+		"second shard":        {challenge: "cross_state_heist", shard: 2},
+		"no shard zero":       {challenge: "sealed_secret", shard: 0, expected: "sealed_secret has a single vault; shard must be 1"},
+		"single vault only":   {challenge: "nested_vault", shard: 2, expected: "nested_vault has a single vault; shard must be 1"},
+		"too many shards":     {challenge: "cross_state_heist", shard: 3, expected: "cross_state_heist is split across 2 vaults; shard must be between 1 and 2"},
+		"not state forensics": {challenge: "terraform_basics", shard: 1, expected: "terraform_basics is not a state forensics challenge"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Vault(tc.challenge, tc.shard)
+			actual := ""
+			if err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestVaultHidesFlag(t *testing.T) {
+	for _, id := range VaultChallengeIDs() {
+		flag := Challenges[id].Flag
+		t.Run(id, func(t *testing.T) {
+			if recovered := recoverFlag(t, id); recovered != flag {
+				t.Fatalf("vault reassembles to %q, expected %q", recovered, flag)
+			}
+		})
+	}
+
+	nested, _ := Vault("nested_vault", 1)
+	if strings.Contains(nested.Secret, "n3st3d") {
+		t.Fatalf("nested_vault secret %q gives the flag away", nested.Secret)
+	}
+	var inOrder strings.Builder
+	for _, compartment := range nested.Compartments {
+		inOrder.WriteString(compartment.Fragment)
+	}
+	if inOrder.String() == Challenges["nested_vault"].Flag {
+		t.Fatal("nested_vault compartments are not shuffled")
+	}
+	for shard := 1; shard <= 2; shard++ {
+		contents, _ := Vault("cross_state_heist", shard)
+		if contents.Secret == Challenges["cross_state_heist"].Flag {
+			t.Fatalf("shard %d holds the whole flag", shard)
+		}
+	}
+}
+
+func TestStateForensicsValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		recovered string
+		order     []int
+		failed    string
+		message   string
+	}{
+		"sealed secret": {
+			challenge: "sealed_secret",
+			recovered: "  flag{s3ns1t1v3_1s_n0t_s3cr3t}\n",
+		},
+		"redacted secret": {
+			challenge: "sealed_secret",
+			recovered: "(sensitive value)",
+			failed:    "flag.format",
+		},
+		"another challenge's flag": {
+			challenge: "sealed_secret",
+			recovered: "flag{n3st3d_c0mp4rtm3nts_r34ss3mbl3d}",
+			failed:    "flag.value",
+			message:   "Recovered flag does not match the flag hidden in the vault",
+		},
+		"nested vault": {
+			challenge: "nested_vault",
+			recovered: "flag{n3st3d_c0mp4rtm3nts_r34ss3mbl3d}",
+		},
+		"nested vault decoy": {
+			challenge: "nested_vault",
+			recovered: "flag{n0t_th3_fl4g_y0u_4r3_l00k1ng_f0r}",
+			failed:    "flag.value",
+			message:   "Recovered flag does not match the flag hidden in the vault",
+		},
+		"compartments in block order": {
+			challenge: "nested_vault",
+			failed:    "flag.format",
+		},
+		"middle compartments swapped": {
+			challenge: "nested_vault",
+			order:     []int{1, 3, 2, 4, 5},
+			failed:    "flag.value",
+			message:   "Recovered flag has every fragment, in the wrong order",
+		},
+		"cross state heist": {
+			challenge: "cross_state_heist",
+			recovered: "flag{r3m0t3_st4t3_h31st_c0mpl3t3}",
+		},
+		"one shard": {
+			challenge: "cross_state_heist",
+			recovered: "flag{r3m0t3_st4t3_",
+			failed:    "flag.format",
+		},
+		"shards swapped": {
+			challenge: "cross_state_heist",
+			recovered: "h31st_c0mpl3t3}flag{r3m0t3_st4t3_",
+			failed:    "flag.format",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recovered := tc.recovered
+			if recovered == "" {
+				// Join the compartments in block order, or in the order of
+				// positions given
+				nested, _ := Vault(tc.challenge, 1)
+				for _, compartment := range nested.Compartments {
+					recovered += compartment.Fragment
+				}
+				if tc.order != nil {
+					recovered = ""
+					for _, position := range tc.order {
+						recovered += splitFragments(Challenges[tc.challenge].Flag, 5)[position-1]
+					}
+				}
+			}
+
+			result := expectValidation(t, tc.challenge, &ProofData{RecoveredFlag: recovered}, tc.failed)
+			if tc.message != "" && result.Message != tc.message {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.message, result.Message)
+			}
+		})
+	}
+}
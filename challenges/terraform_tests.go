@@ -0,0 +1,348 @@
+package challenges
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	registerTerraformTestChallenges()
+}
+
+func registerTerraformTestChallenges() {
+	// First Test Run
+	Challenges["test_first_run"] = &Challenge{
+		ID:          "test_first_run",
+		Name:        "First Test Run",
+		Description: "Write a .tftest.hcl file with a run block using command = plan and an assert on an output or resource",
+		Points:      150,
+		Flag:        "flag{f1rst_t3st_run_gr33n}",
+		Difficulty:  "beginner",
+		Category:    "testing",
+		Validator:   NewStructuredValidator(validateFirstTestRunStructure, ProofKindTest),
+	}
+
+	// Expected Failures
+	Challenges["expect_failures_test"] = &Challenge{
+		ID:          "expect_failures_test",
+		Name:        "Failure Expected",
+		Description: "Write a plan run that uses expect_failures to prove a precondition on ctfchallenge_validated_resource rejects bad input",
+		Points:      200,
+		Flag:        "flag{3xp3ct_th3_f41lur3}",
+		Difficulty:  "intermediate",
+		Category:    "testing",
+		Validator:   NewStructuredValidator(validateExpectFailuresStructure, ProofKindTest),
+	}
+
+	// Mocked Providers
+	Challenges["mock_provider_test"] = &Challenge{
+		ID:          "mock_provider_test",
+		Name:        "Mock Provider",
+		Description: "Test your configuration against a mock_provider with at least 2 asserting run blocks",
+		Points:      200,
+		Flag:        "flag{m0ck_pr0v1d3r_t3st3r}",
+		Difficulty:  "intermediate",
+		Category:    "testing",
+		Validator:   NewStructuredValidator(validateMockProviderStructure, ProofKindTest),
+	}
+
+	// Test Suite Master
+	Challenges["test_suite_master"] = &Challenge{
+		ID:          "test_suite_master",
+		Name:        "Test Suite Master",
+		Description: "Build a test suite with at least 3 runs combining plan runs, expect_failures and a mock_provider",
+		Points:      300,
+		Flag:        "flag{t3st_su1t3_gr4ndm4st3r}",
+		Difficulty:  "advanced",
+		Category:    "testing",
+		Validator:   NewStructuredValidator(validateTestSuiteMasterStructure, ProofKindTest),
+	}
+}
+
+func validateFirstTestRunStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	runs := testRuns(proof)
+	if len(runs) == 0 {
+		result.fail("test.runs", "No run blocks found in the submitted test files",
+			"Example: run \"plan\" { command = plan, assert { ... } }")
+		return result
+	}
+	result.pass("test.runs", fmt.Sprintf("Found %d run block(s)", len(runs)))
+
+	run := findPlanRun(runs, true)
+	if run == nil {
+		result.fail("test.plan_run", "No run block with command = plan and assertions",
+			"Add command = plan to a run block so it asserts on the plan without creating resources")
+		return result
+	}
+	result.pass("test.plan_run", fmt.Sprintf("Run '%s' uses command = plan", run.Name))
+
+	if !checkTestAssertions(&result, "run."+run.Name, *run) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{f1rst_t3st_run_gr33n}"
+	result.Message = "✓ First test run completed! terraform test now checks your plan on every change."
+	return result
+}
+
+func validateExpectFailuresStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkExpectFailures(&result, testRuns(proof)) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{3xp3ct_th3_f41lur3}"
+	result.Message = "✓ Failure expected and caught! Your test proves the precondition guards bad input."
+	return result
+}
+
+func validateMockProviderStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	if !checkMockProvider(&result, proof.TestFiles, 2) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{m0ck_pr0v1d3r_t3st3r}"
+	result.Message = "✓ Mock provider mastered! Your tests run without touching real infrastructure."
+	return result
+}
+
+func validateTestSuiteMasterStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	runs := testRuns(proof)
+	if len(runs) < 3 {
+		result.failCount("test.runs", fmt.Sprintf("Need at least 3 run blocks (found %d)", len(runs)),
+			">= 3", len(runs), "")
+		return result
+	}
+	result.pass("test.runs", fmt.Sprintf("Found %d run blocks across %d file(s)", len(runs), len(proof.TestFiles)))
+
+	if findPlanRun(runs, true) == nil {
+		result.fail("test.plan_run", "No run block with command = plan and assertions", "")
+		return result
+	}
+	result.pass("test.plan_run", "Suite includes an asserting plan run")
+
+	// Every run that does not expect failures must assert something
+	for _, run := range runs {
+		if len(run.ExpectFailures) > 0 {
+			continue
+		}
+		if !checkTestAssertions(&result, "run."+run.Name, run) {
+			return result
+		}
+	}
+
+	if !checkExpectFailures(&result, runs) {
+		return result
+	}
+
+	if !checkMockProvider(&result, proof.TestFiles, 1) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{t3st_su1t3_gr4ndm4st3r}"
+	result.Message = "✓ Test suite mastered! Your module is covered by plans, failures and mocks."
+	return result
+}
+
+// testRuns returns the run blocks of every submitted test file
+func testRuns(proof *ProofData) []TestRun {
+	runs := []TestRun{}
+	for _, file := range proof.TestFiles {
+		runs = append(runs, file.Runs...)
+	}
+	return runs
+}
+
+// findPlanRun returns the first run with command = plan, optionally requiring
+// assertions
+func findPlanRun(runs []TestRun, withAssertions bool) *TestRun {
+	for i := range runs {
+		if runs[i].Command == "plan" && (!withAssertions || len(runs[i].Assertions) > 0) {
+			return &runs[i]
+		}
+	}
+	return nil
+}
+
+// checkTestAssertions verifies that a run has assertions with error messages
+// whose conditions reference an output or resource.
+func checkTestAssertions(result *ValidationResult, prefix string, run TestRun) bool {
+	if len(run.Assertions) == 0 {
+		result.failCount(prefix+".assertions", fmt.Sprintf("Run '%s' has no assert blocks", run.Name),
+			">= 1", 0, "Example: assert { condition = output.name == \"expected\", error_message = ... }")
+		return false
+	}
+	result.pass(prefix+".assertions", fmt.Sprintf("Run '%s' has %d assertion(s)", run.Name, len(run.Assertions)))
+
+	for i, assertion := range run.Assertions {
+		id := fmt.Sprintf("%s.assert.%d", prefix, i+1)
+
+		if !validateErrorMessage(assertion.ErrorMessage, 10) {
+			result.failCount(id+".error_message", fmt.Sprintf("Assertion %d of run '%s' has inadequate error message", i+1, run.Name),
+				">= 10 chars", len(strings.TrimSpace(assertion.ErrorMessage)),
+				"Error message must be at least 10 characters and describe what failed")
+			return false
+		}
+
+		ref := ""
+		for _, r := range assertion.References {
+			if referencesConfigObject(r) {
+				ref = r
+				break
+			}
+		}
+		if ref == "" {
+			result.fail(id+".references", fmt.Sprintf("Assertion %d of run '%s' does not reference an output or resource", i+1, run.Name),
+				"Assert on what the configuration produces, e.g. output.name or ctfchallenge_validated_resource.example.solved")
+			return false
+		}
+		result.pass(id+".references", fmt.Sprintf("Assertion %d checks %s", i+1, ref))
+	}
+	return true
+}
+
+// checkExpectFailures verifies that a plan run expects a failure from a
+// checkable object, including a ctfchallenge_validated_resource precondition.
+func checkExpectFailures(result *ValidationResult, runs []TestRun) bool {
+	var run *TestRun
+	for i := range runs {
+		if len(runs[i].ExpectFailures) > 0 {
+			run = &runs[i]
+			break
+		}
+	}
+
+	if run == nil {
+		result.fail("expect_failures.present", "No run block uses expect_failures",
+			"Example: run \"rejects_bad_input\" { command = plan, expect_failures = [ctfchallenge_validated_resource.example] }")
+		return false
+	}
+	result.pass("expect_failures.present", fmt.Sprintf("Run '%s' expects %d failure(s)", run.Name, len(run.ExpectFailures)))
+
+	if run.Command != "plan" {
+		result.fail("expect_failures.command", fmt.Sprintf("Run '%s' uses command = %s", run.Name, run.Command),
+			"Preconditions fail during planning; use command = plan so the run stops before apply")
+		return false
+	}
+	result.pass("expect_failures.command", fmt.Sprintf("Run '%s' uses command = plan", run.Name))
+
+	targetsResource := false
+	for i, target := range run.ExpectFailures {
+		id := fmt.Sprintf("expect_failures.%d", i+1)
+
+		kind := checkableObjectKind(target)
+		if kind == "" {
+			result.fail(id+".checkable", fmt.Sprintf("%s is not a checkable object", target),
+				"expect_failures accepts var.<name>, output.<name>, check.<name> or a resource address")
+			return false
+		}
+		result.pass(id+".checkable", fmt.Sprintf("%s is a checkable %s", target, kind))
+
+		if strings.HasPrefix(target, "ctfchallenge_validated_resource.") {
+			targetsResource = true
+		}
+	}
+
+	if !targetsResource {
+		result.fail("expect_failures.validated_resource", "expect_failures does not target a ctfchallenge_validated_resource",
+			"Add a precondition to a ctfchallenge_validated_resource and list the resource in expect_failures")
+		return false
+	}
+	result.pass("expect_failures.validated_resource", "Expects a ctfchallenge_validated_resource precondition to fail")
+	return true
+}
+
+// checkMockProvider verifies that a test file declares a mock_provider and
+// has at least minRuns asserting runs.
+func checkMockProvider(result *ValidationResult, files []TestFileProof, minRuns int) bool {
+	var file *TestFileProof
+	for i := range files {
+		if len(files[i].MockProviders) > 0 {
+			file = &files[i]
+			break
+		}
+	}
+
+	if file == nil {
+		result.fail("mock_provider.present", "No test file declares a mock_provider",
+			"Example: mock_provider \"ctfchallenge\" {}")
+		return false
+	}
+	result.pass("mock_provider.present", fmt.Sprintf("%s mocks provider(s): %s", file.Path, strings.Join(file.MockProviders, ", ")))
+
+	asserting := 0
+	for _, run := range file.Runs {
+		if len(run.Assertions) > 0 {
+			asserting++
+		}
+	}
+	if asserting < minRuns {
+		result.failCount("mock_provider.runs", fmt.Sprintf("%s needs at least %d run(s) with assertions (found %d)", file.Path, minRuns, asserting),
+			fmt.Sprintf(">= %d", minRuns), asserting, "Runs in the same file as the mock_provider use the mocked provider")
+		return false
+	}
+	result.pass("mock_provider.runs", fmt.Sprintf("%d asserting run(s) use the mocked provider", asserting))
+	return true
+}
+
+// checkableObjectKind returns the kind of checkable object an expect_failures
+// entry refers to, or "" when it does not refer to one.
+func checkableObjectKind(target string) string {
+	parts := strings.Split(target, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "var":
+		return "input variable"
+	case len(parts) == 2 && parts[0] == "output":
+		return "output"
+	case len(parts) == 2 && parts[0] == "check":
+		return "check block"
+	case len(parts) == 3 && parts[0] == "data":
+		return "data source"
+	case len(parts) == 2 && isResourceType(parts[0]):
+		return "resource"
+	}
+	return ""
+}
+
+// referencesConfigObject reports whether a reference points at something the
+// configuration under test produces.
+func referencesConfigObject(ref string) bool {
+	root := strings.SplitN(ref, ".", 2)[0]
+	switch root {
+	case "output", "run", "data", "module":
+		return true
+	case "var", "local", "each", "count", "self", "path", "terraform":
+		return false
+	}
+	return isResourceType(root)
+}
+
+// isResourceType reports whether name looks like a resource type (provider
+// prefix followed by an underscore).
+func isResourceType(name string) bool {
+	i := strings.Index(name, "_")
+	return i > 0 && i < len(name)-1
+}
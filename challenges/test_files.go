@@ -0,0 +1,128 @@
+package challenges
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TestFileProof contains proof parsed from a .tftest.hcl file
+type TestFileProof struct {
+	Path          string
+	Runs          []TestRun
+	MockProviders []string
+}
+
+// TestRun represents a run block in a test file
+type TestRun struct {
+	Name           string
+	Command        string
+	Assertions     []TestAssertion
+	ExpectFailures []string
+}
+
+// TestAssertion represents an assert block in a run block
+type TestAssertion struct {
+	Condition    string
+	ErrorMessage string
+	References   []string
+}
+
+// ParseTestFile parses the source of a .tftest.hcl file into proof. Only the
+// blocks and attributes inspected by the test challenges are extracted.
+func ParseTestFile(path string, src []byte) (*TestFileProof, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	proof := &TestFileProof{Path: path}
+	body := file.Body.(*hclsyntax.Body)
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "run":
+			run, runDiags := parseTestRun(block, src)
+			diags = append(diags, runDiags...)
+			proof.Runs = append(proof.Runs, run)
+		case "mock_provider":
+			if len(block.Labels) > 0 {
+				proof.MockProviders = append(proof.MockProviders, block.Labels[0])
+			}
+		}
+	}
+
+	return proof, diags
+}
+
+func parseTestRun(block *hclsyntax.Block, src []byte) (TestRun, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	run := TestRun{Command: "apply"}
+	if len(block.Labels) > 0 {
+		run.Name = block.Labels[0]
+	}
+
+	if attr, ok := block.Body.Attributes["command"]; ok {
+		run.Command = hcl.ExprAsKeyword(attr.Expr)
+	}
+
+	if attr, ok := block.Body.Attributes["expect_failures"]; ok {
+		exprs, listDiags := hcl.ExprList(attr.Expr)
+		diags = append(diags, listDiags...)
+		for _, expr := range exprs {
+			traversal, travDiags := hcl.AbsTraversalForExpr(expr)
+			diags = append(diags, travDiags...)
+			if !travDiags.HasErrors() {
+				run.ExpectFailures = append(run.ExpectFailures, traversalString(traversal))
+			}
+		}
+	}
+
+	for _, nested := range block.Body.Blocks {
+		if nested.Type != "assert" {
+			continue
+		}
+
+		assertion := TestAssertion{}
+		if attr, ok := nested.Body.Attributes["condition"]; ok {
+			assertion.Condition = string(attr.Expr.Range().SliceBytes(src))
+			for _, traversal := range attr.Expr.Variables() {
+				assertion.References = append(assertion.References, traversalString(traversal))
+			}
+		}
+		if attr, ok := nested.Body.Attributes["error_message"]; ok {
+			assertion.ErrorMessage = expressionText(attr.Expr, src)
+		}
+		run.Assertions = append(run.Assertions, assertion)
+	}
+
+	return run, diags
+}
+
+// expressionText returns the value of a literal string expression, or the
+// source text of any other expression.
+func expressionText(expr hclsyntax.Expression, src []byte) string {
+	if value, diags := expr.Value(nil); !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+		return value.AsString()
+	}
+	text := strings.TrimSpace(string(expr.Range().SliceBytes(src)))
+	return strings.Trim(text, `"`)
+}
+
+// traversalString renders the root and attribute steps of a traversal, e.g.
+// output.name or ctfchallenge_validated_resource.example.quality_score.
+func traversalString(traversal hcl.Traversal) string {
+	parts := []string{}
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			parts = append(parts, s.Name)
+		case hcl.TraverseAttr:
+			parts = append(parts, s.Name)
+		}
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,265 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// planRun is a run block planning and asserting on an output.
+const planRun = `
+run "plan" {
+  command = plan
+
+  assert {
+    condition     = output.name == "example"
+    error_message = "output.name should be example"
+  }
+}
+`
+
+// expectFailuresRun is a run block expecting a validated resource
+// precondition to fail.
+const expectFailuresRun = `
+run "rejects_bad_input" {
+  command = plan
+
+  variables {
+    instance_size = ""
+  }
+
+  expect_failures = [ctfchallenge_validated_resource.example]
+}
+`
+
+// testFiles parses test file sources, keyed by path, into proof.
+func testFiles(t *testing.T, sources map[string]string) []TestFileProof {
+	t.Helper()
+
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	files := []TestFileProof{}
+	for _, path := range paths {
+		file, diags := ParseTestFile(path, []byte(sources[path]))
+		if diags.HasErrors() {
+			t.Fatalf("error parsing %s: %s", path, diags.Error())
+		}
+		files = append(files, *file)
+	}
+	return files
+}
+
+func TestParseTestFile(t *testing.T) {
+	cases := map[string]struct {
+		src      string
+		expected string
+		err      string
+	}{
+		"plan run": {
+			src:      planRun,
+			expected: `plan command=plan assert[output.name == "example" | output.name should be example | output.name]`,
+		},
+		"default command": {
+			src:      `run "apply" {}`,
+			expected: "apply command=apply",
+		},
+		"expect failures": {
+			src:      expectFailuresRun,
+			expected: "rejects_bad_input command=plan expect[ctfchallenge_validated_resource.example]",
+		},
+		"expect failures of several objects": {
+			src:      `run "bad" { expect_failures = [var.size, output.name, check.health] }`,
+			expected: "bad command=apply expect[var.size output.name check.health]",
+		},
+		"error message template": {
+			src: `run "plan" {
+  assert {
+    condition     = ctfchallenge_validated_resource.example.quality_score > var.min
+    error_message = "score ${ctfchallenge_validated_resource.example.quality_score} too low"
+  }
+}`,
+			expected: "plan command=apply assert[ctfchallenge_validated_resource.example.quality_score > var.min | " +
+				"score ${ctfchallenge_validated_resource.example.quality_score} too low | " +
+				"ctfchallenge_validated_resource.example.quality_score var.min]",
+		},
+		"mock provider": {
+			src:      "mock_provider \"ctfchallenge\" {}\n" + planRun,
+			expected: `mock[ctfchallenge] plan command=plan assert[output.name == "example" | output.name should be example | output.name]`,
+		},
+		"no run blocks": {
+			src:      "variables {\n  instance_size = \"small\"\n}\n",
+			expected: "",
+		},
+		"empty file": {
+			src:      "",
+			expected: "",
+		},
+		"unclosed block": {
+			src: `run "plan" {`,
+			err: "Unclosed configuration block",
+		},
+		"missing equals": {
+			src: `run "plan" { command plan }`,
+			err: "Argument definition required",
+		},
+		"expect failures not a list": {
+			src: `run "bad" { expect_failures = "var.size" }`,
+			err: "Invalid expression",
+		},
+		"expect failures with a literal": {
+			src: `run "bad" { expect_failures = [var.size, "output.name"] }`,
+			err: "A single static variable reference is required",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			file, diags := ParseTestFile("main.tftest.hcl", []byte(tc.src))
+			if tc.err != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Error(), tc.err) {
+					t.Fatalf("\n\nexpected error:\n\n%q\n\ngot:\n\n%q\n\n", tc.err, diags.Error())
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("error parsing test file: %s", diags.Error())
+			}
+
+			parts := []string{}
+			if len(file.MockProviders) > 0 {
+				parts = append(parts, fmt.Sprintf("mock[%s]", strings.Join(file.MockProviders, " ")))
+			}
+			for _, run := range file.Runs {
+				parts = append(parts, fmt.Sprintf("%s command=%s", run.Name, run.Command))
+				for _, assertion := range run.Assertions {
+					parts = append(parts, fmt.Sprintf("assert[%s | %s | %s]", assertion.Condition, assertion.ErrorMessage, strings.Join(assertion.References, " ")))
+				}
+				if len(run.ExpectFailures) > 0 {
+					parts = append(parts, fmt.Sprintf("expect[%s]", strings.Join(run.ExpectFailures, " ")))
+				}
+			}
+			if actual := strings.Join(parts, " "); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTerraformTestValidators(t *testing.T) {
+	const mock = "mock_provider \"ctfchallenge\" {}\n"
+
+	cases := map[string]struct {
+		challenge string
+		files     map[string]string
+		failed    string
+	}{
+		"first run": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": planRun},
+		},
+		"first run without run blocks": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": mock},
+			failed:    "test.runs",
+		},
+		"first run applying": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": strings.Replace(planRun, "command = plan", "command = apply", 1)},
+			failed:    "test.plan_run",
+		},
+		"first run without assertions": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": `run "plan" { command = plan }`},
+			failed:    "test.plan_run",
+		},
+		"first run with a short error message": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": strings.Replace(planRun, "output.name should be example", "bad", 1)},
+			failed:    "run.plan.assert.1.error_message",
+		},
+		"first run asserting on a variable": {
+			challenge: "test_first_run",
+			files:     map[string]string{"main.tftest.hcl": strings.Replace(planRun, "output.name ==", "var.name ==", 1)},
+			failed:    "run.plan.assert.1.references",
+		},
+		"expect failures": {
+			challenge: "expect_failures_test",
+			files:     map[string]string{"main.tftest.hcl": expectFailuresRun},
+		},
+		"expect failures missing": {
+			challenge: "expect_failures_test",
+			files:     map[string]string{"main.tftest.hcl": planRun},
+			failed:    "expect_failures.present",
+		},
+		"expect failures applying": {
+			challenge: "expect_failures_test",
+			files:     map[string]string{"main.tftest.hcl": strings.Replace(expectFailuresRun, "command = plan", "command = apply", 1)},
+			failed:    "expect_failures.command",
+		},
+		"expect failures of a local": {
+			challenge: "expect_failures_test",
+			files:     map[string]string{"main.tftest.hcl": "run \"bad\" {\n  command         = plan\n  expect_failures = [local.size]\n}\n"},
+			failed:    "expect_failures.1.checkable",
+		},
+		"expect failures of a variable only": {
+			challenge: "expect_failures_test",
+			files:     map[string]string{"main.tftest.hcl": "run \"bad\" {\n  command         = plan\n  expect_failures = [var.instance_size]\n}\n"},
+			failed:    "expect_failures.validated_resource",
+		},
+		"mock provider": {
+			challenge: "mock_provider_test",
+			files:     map[string]string{"mock.tftest.hcl": mock + planRun + strings.Replace(planRun, `run "plan"`, `run "again"`, 1)},
+		},
+		"mock provider missing": {
+			challenge: "mock_provider_test",
+			files:     map[string]string{"main.tftest.hcl": planRun + strings.Replace(planRun, `run "plan"`, `run "again"`, 1)},
+			failed:    "mock_provider.present",
+		},
+		"mock provider with runs in another file": {
+			challenge: "mock_provider_test",
+			files: map[string]string{
+				"mock.tftest.hcl": mock + planRun,
+				"main.tftest.hcl": planRun,
+			},
+			failed: "mock_provider.runs",
+		},
+		"test suite": {
+			challenge: "test_suite_master",
+			files: map[string]string{
+				"main.tftest.hcl": planRun + expectFailuresRun,
+				"mock.tftest.hcl": mock + strings.Replace(planRun, `run "plan"`, `run "mocked"`, 1),
+			},
+		},
+		"test suite with two runs": {
+			challenge: "test_suite_master",
+			files:     map[string]string{"main.tftest.hcl": mock + planRun + expectFailuresRun},
+			failed:    "test.runs",
+		},
+		"test suite with a run without assertions": {
+			challenge: "test_suite_master",
+			files: map[string]string{
+				"main.tftest.hcl": planRun + expectFailuresRun + `run "apply" {}`,
+				"mock.tftest.hcl": mock + strings.Replace(planRun, `run "plan"`, `run "mocked"`, 1),
+			},
+			failed: "run.apply.assertions",
+		},
+		"test suite without a mock provider": {
+			challenge: "test_suite_master",
+			files: map[string]string{
+				"main.tftest.hcl": planRun + expectFailuresRun + strings.Replace(planRun, `run "plan"`, `run "again"`, 1),
+			},
+			failed: "mock_provider.present",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectValidation(t, tc.challenge, &ProofData{TestFiles: testFiles(t, tc.files)}, tc.failed)
+		})
+	}
+}
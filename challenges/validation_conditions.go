@@ -2,6 +2,8 @@ package challenges
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -16,9 +18,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Precondition Guardian",
 		Description: "Use preconditions to validate inputs before resource creation",
 		Points:      150,
+		Flag:        "flag{pr3c0nd1t10n_gu4rd14n_m4st3r}",
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validatePreconditionChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validatePreconditionStructure, ProofKindResource),
+			NewLegacyValidator(validatePreconditionChallenge,
+				"uses_precondition", "condition_expression", "checks_input", "error_message", "in_lifecycle_block", "validates"),
+		),
 	}
 
 	// Postcondition Basics
@@ -27,9 +34,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Postcondition Validator",
 		Description: "Use postconditions with 'self' to validate resource attributes after creation",
 		Points:      175,
+		Flag:        "flag{p0stc0nd1t10n_v4l1d4t0r_3xp3rt}",
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validatePostconditionChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validatePostconditionStructure, ProofKindResource, ProofKindDataSource),
+			NewLegacyValidator(validatePostconditionChallenge,
+				"uses_postcondition", "uses_self", "validated_attribute", "validates_after_creation", "error_message", "in_lifecycle_block"),
+		),
 	}
 
 	// Combined Pre/Post Conditions
@@ -38,9 +50,15 @@ func registerValidationConditionChallenges() {
 		Name:        "Condition Master",
 		Description: "Combine preconditions and postconditions in a single resource",
 		Points:      200,
+		Flag:        "flag{c0mb1n3d_c0nd1t10ns_m4st3r}",
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validateCombinedConditionsChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateCombinedConditionsStructure, ProofKindResource),
+			NewLegacyValidator(validateCombinedConditionsChallenge,
+				"uses_precondition", "uses_postcondition", "postcondition_uses_self", "precondition_uses_self",
+				"precondition_validates", "postcondition_validates", "precondition_error", "postcondition_error"),
+		),
 	}
 
 	// Data Source Validation
@@ -49,9 +67,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Data Source Validator",
 		Description: "Use postconditions to validate data source outputs",
 		Points:      160,
+		Flag:        "flag{d4t4_s0urc3_v4l1d4t0r_pr0}",
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validateDataSourceConditionChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateDataSourceConditionStructure, ProofKindDataSource),
+			NewLegacyValidator(validateDataSourceConditionChallenge,
+				"uses_data_source", "uses_postcondition", "uses_self", "validated_data_attribute", "validation_purpose"),
+		),
 	}
 
 	// Output Validation
@@ -62,7 +85,11 @@ func registerValidationConditionChallenges() {
 		Points:      180,
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validateOutputConditionChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateOutputConditionStructure, ProofKindResource, ProofKindDataSource, ProofKindModule),
+			NewLegacyValidator(validateOutputConditionChallenge,
+				"uses_output_block", "uses_precondition", "validates_before_output", "enforces_module_contract", "consumer_friendly_error"),
+		),
 	}
 
 	// Complex Validation Chain
@@ -71,9 +98,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Validation Chain Architect",
 		Description: "Create a chain of resources with interconnected pre/postconditions",
 		Points:      250,
+		Flag:        "flag{v4l1d4t10n_ch41n_4rch1t3ct}",
 		Difficulty:  "advanced",
 		Category:    "validation",
-		Validator:   validateValidationChainChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateValidationChainStructure, ProofKindResource),
+			NewLegacyValidator(validateValidationChainChallenge,
+				"resource_count", "total_conditions", "conditions_interconnected", "validation_flow", "uses_depends_on"),
+		),
 	}
 
 	// Module Contract Challenge
@@ -82,9 +114,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Module Contract Designer",
 		Description: "Design a module with comprehensive pre/postconditions for input validation and output guarantees",
 		Points:      300,
+		Flag:        "flag{m0dul3_c0ntr4ct_d3s1gn3r_m4st3r}",
 		Difficulty:  "advanced",
 		Category:    "validation",
-		Validator:   validateModuleContractChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateModuleContractStructure, ProofKindModule),
+			NewLegacyValidator(validateModuleContractChallenge,
+				"module_name", "input_validations", "output_validations"),
+		),
 	}
 
 	// Self-Reference Master
@@ -93,9 +130,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Self-Reference Master",
 		Description: "Master the use of 'self' in postconditions to validate multiple attributes",
 		Points:      190,
+		Flag:        "flag{s3lf_r3f3r3nc3_m4st3r_pr0}",
 		Difficulty:  "intermediate",
 		Category:    "validation",
-		Validator:   validateSelfReferenceChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateSelfReferenceStructure, ProofKindResource),
+			NewLegacyValidator(validateSelfReferenceChallenge,
+				"uses_self", "self_references", "postcondition_count"),
+		),
 	}
 
 	// Conditional Validation
@@ -104,9 +146,14 @@ func registerValidationConditionChallenges() {
 		Name:        "Conditional Validation Expert",
 		Description: "Use complex boolean logic in condition blocks with multiple checks",
 		Points:      220,
+		Flag:        "flag{c0nd1t10n4l_v4l1d4t10n_3xp3rt}",
 		Difficulty:  "advanced",
 		Category:    "validation",
-		Validator:   validateConditionalValidationChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateConditionalValidationStructure, ProofKindResource),
+			NewLegacyValidator(validateConditionalValidationChallenge,
+				"uses_and", "uses_or", "uses_functions"),
+		),
 	}
 
 	// Error Message Designer
@@ -115,47 +162,17 @@ func registerValidationConditionChallenges() {
 		Name:        "Error Message Designer",
 		Description: "Create helpful, informative error messages for all validation failures",
 		Points:      140,
+		Flag:        "flag{3rr0r_m3ss4g3_d3s1gn3r_pr0}",
 		Difficulty:  "beginner",
 		Category:    "validation",
-		Validator:   validateErrorMessageChallenge,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateErrorMessageStructure, ProofKindResource, ProofKindDataSource),
+			NewLegacyValidator(validateErrorMessageChallenge,
+				"error_message_count", "uses_interpolation", "provides_context"),
+		),
 	}
 }
 
-// validateValidationChallengeStructure validates validation challenges using structured proof
-func validateValidationChallengeStructure(c *Challenge, proof *ProofData) ValidationResult {
-	result := ValidationResult{
-		Success: false,
-		Details: []string{},
-	}
-
-	switch c.ID {
-	case "precondition_guardian":
-		return validatePreconditionStructure(proof)
-	case "postcondition_validator":
-		return validatePostconditionStructure(proof)
-	case "condition_master":
-		return validateCombinedConditionsStructure(proof)
-	case "data_validator":
-		return validateDataSourceConditionStructure(proof)
-	case "output_contract":
-		return validateOutputConditionStructure(proof)
-	case "validation_chain":
-		return validateValidationChainStructure(proof)
-	case "module_contract":
-		return validateModuleContractStructure(proof)
-	case "self_reference_master":
-		return validateSelfReferenceStructure(proof)
-	case "conditional_validation":
-		return validateConditionalValidationStructure(proof)
-	case "error_message_designer":
-		return validateErrorMessageStructure(proof)
-	default:
-		result.Message = "Unknown validation challenge"
-	}
-
-	return result
-}
-
 func validatePreconditionStructure(proof *ProofData) ValidationResult {
 	result := ValidationResult{
 		Success: false,
@@ -163,8 +180,8 @@ func validatePreconditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.Resources) == 0 {
-		result.Message = "No resources provided. You must create a resource with a precondition in its lifecycle block."
-		result.Details = append(result.Details, "Expected: At least one resource with lifecycle.precondition")
+		result.fail("resource.present", "No resources provided. You must create a resource with a precondition in its lifecycle block.",
+			"Expected: At least one resource with lifecycle.precondition")
 		return result
 	}
 
@@ -179,42 +196,41 @@ func validatePreconditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if validResource == nil {
-		result.Message = "No preconditions found in any resource lifecycle block"
-		result.Details = append(result.Details, "Add a lifecycle block with precondition to your resource")
-		result.Details = append(result.Details, "Example: lifecycle { precondition { condition = ..., error_message = ... } }")
+		result.fail("precondition.present", "No preconditions found in any resource lifecycle block",
+			"Example: lifecycle { precondition { condition = ..., error_message = ... } }")
 		return result
 	}
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ Found resource '%s' with lifecycle block", validResource.ResourceName))
+	result.pass("precondition.present", fmt.Sprintf("Found resource '%s' with lifecycle block", validResource.ResourceName))
 
 	// Validate each precondition
 	for i, precond := range validResource.Lifecycle.Preconditions {
-		result.Details = append(result.Details, fmt.Sprintf("Checking precondition %d...", i+1))
+		prefix := fmt.Sprintf("precondition.%d", i+1)
 
 		// Check condition expression exists and is not empty
 		if precond.Condition == "" {
-			result.Message = fmt.Sprintf("Precondition %d has empty condition expression", i+1)
-			result.Details = append(result.Details, "✗ Condition expression is required")
+			result.fail(prefix+".condition", fmt.Sprintf("Precondition %d has empty condition expression", i+1),
+				"Condition expression is required")
 			return result
 		}
-		result.Details = append(result.Details, fmt.Sprintf("  ✓ Has condition: %s", truncate(precond.Condition, 60)))
+		result.pass(prefix+".condition", fmt.Sprintf("Precondition %d has condition: %s", i+1, truncate(precond.Condition, 60)))
 
 		// Check that precondition does NOT use 'self' (resource doesn't exist yet)
 		if conditionUsesSelf(precond.Condition) {
-			result.Message = fmt.Sprintf("Precondition %d incorrectly uses 'self'", i+1)
-			result.Details = append(result.Details, "  ✗ Preconditions should NOT use 'self' - the resource doesn't exist yet")
-			result.Details = append(result.Details, "  Hint: Use var.* or local.* to validate inputs before creation")
+			result.fail(prefix+".no_self", fmt.Sprintf("Precondition %d incorrectly uses 'self'", i+1),
+				"Preconditions should NOT use 'self' - the resource doesn't exist yet. Use var.* or local.* to validate inputs before creation")
 			return result
 		}
-		result.Details = append(result.Details, "  ✓ Does not use 'self' (correct for precondition)")
+		result.pass(prefix+".no_self", fmt.Sprintf("Precondition %d does not use 'self' (correct for precondition)", i+1))
 
 		// Check error message
 		if !validateErrorMessage(precond.ErrorMessage, 10) {
-			result.Message = fmt.Sprintf("Precondition %d has inadequate error message", i+1)
-			result.Details = append(result.Details, "  ✗ Error message must be at least 10 characters and descriptive")
+			result.failCount(prefix+".error_message", fmt.Sprintf("Precondition %d has inadequate error message", i+1),
+				">= 10 chars", len(strings.TrimSpace(precond.ErrorMessage)),
+				"Error message must be at least 10 characters and descriptive")
 			return result
 		}
-		result.Details = append(result.Details, fmt.Sprintf("  ✓ Has descriptive error message (%d chars)", len(precond.ErrorMessage)))
+		result.pass(prefix+".error_message", fmt.Sprintf("Precondition %d has descriptive error message (%d chars)", i+1, len(precond.ErrorMessage)))
 	}
 
 	result.Success = true
@@ -230,8 +246,8 @@ func validatePostconditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.Resources) == 0 && len(proof.DataSources) == 0 {
-		result.Message = "No resources or data sources provided"
-		result.Details = append(result.Details, "You must create a resource or data source with a postcondition")
+		result.fail("resource.present", "No resources or data sources provided",
+			"You must create a resource or data source with a postcondition")
 		return result
 	}
 
@@ -264,46 +280,50 @@ func validatePostconditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if !hasPostcondition {
-		result.Message = "No postconditions found in lifecycle blocks"
-		result.Details = append(result.Details, "Add a lifecycle block with postcondition to your resource/data source")
-		result.Details = append(result.Details, "Postconditions validate resource state AFTER creation")
+		result.fail("postcondition.present", "No postconditions found in lifecycle blocks",
+			"Add a lifecycle block with postcondition to your resource/data source. Postconditions validate resource state AFTER creation")
 		return result
 	}
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ Found '%s' with postcondition", resourceName))
+	result.pass("postcondition.present", fmt.Sprintf("Found '%s' with postcondition", resourceName))
 
 	// Validate each postcondition
 	for i, postcond := range postconditions {
-		result.Details = append(result.Details, fmt.Sprintf("Checking postcondition %d...", i+1))
+		prefix := fmt.Sprintf("postcondition.%d", i+1)
 
 		if postcond.Condition == "" {
-			result.Message = fmt.Sprintf("Postcondition %d has empty condition", i+1)
+			result.fail(prefix+".condition", fmt.Sprintf("Postcondition %d has empty condition", i+1), "")
 			return result
 		}
-		result.Details = append(result.Details, fmt.Sprintf("  ✓ Has condition: %s", truncate(postcond.Condition, 60)))
+		result.pass(prefix+".condition", fmt.Sprintf("Postcondition %d has condition: %s", i+1, truncate(postcond.Condition, 60)))
 
 		// Postconditions MUST use 'self' to reference the resource
 		if !conditionUsesSelf(postcond.Condition) {
-			result.Message = fmt.Sprintf("Postcondition %d does not use 'self'", i+1)
-			result.Details = append(result.Details, "  ✗ Postconditions must use 'self' to reference resource attributes")
-			result.Details = append(result.Details, "  Example: self.solved == true or self.status == \"active\"")
+			result.fail(prefix+".uses_self", fmt.Sprintf("Postcondition %d does not use 'self'", i+1),
+				"Postconditions must use 'self' to reference resource attributes, e.g. self.solved == true or self.status == \"active\"")
 			return result
 		}
-		result.Details = append(result.Details, "  ✓ Uses 'self' to reference resource (correct for postcondition)")
+		result.pass(prefix+".uses_self", fmt.Sprintf("Postcondition %d uses 'self' to reference resource (correct for postcondition)", i+1))
 
 		// Extract what attribute is being validated
 		selfRefs := extractSelfReferences(postcond.Condition)
 		if len(selfRefs) > 0 {
-			result.Details = append(result.Details, fmt.Sprintf("  ✓ Validates attributes: %s", strings.Join(selfRefs, ", ")))
+			result.AddCheck(Check{
+				ID:      prefix + ".attributes",
+				Status:  CheckPass,
+				Actual:  strings.Join(selfRefs, ", "),
+				Message: fmt.Sprintf("Postcondition %d validates attributes: %s", i+1, strings.Join(selfRefs, ", ")),
+			})
 		}
 
 		// Check error message
 		if !validateErrorMessage(postcond.ErrorMessage, 10) {
-			result.Message = "Postcondition error message too short"
-			result.Details = append(result.Details, "  ✗ Error message must be descriptive (min 10 chars)")
+			result.failCount(prefix+".error_message", "Postcondition error message too short",
+				">= 10 chars", len(strings.TrimSpace(postcond.ErrorMessage)),
+				"Error message must be descriptive")
 			return result
 		}
-		result.Details = append(result.Details, "  ✓ Has descriptive error message")
+		result.pass(prefix+".error_message", fmt.Sprintf("Postcondition %d has descriptive error message", i+1))
 	}
 
 	result.Success = true
@@ -319,8 +339,8 @@ func validateCombinedConditionsStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.Resources) == 0 {
-		result.Message = "No resources provided"
-		result.Details = append(result.Details, "Create a resource with BOTH precondition and postcondition in lifecycle")
+		result.fail("resource.present", "No resources provided",
+			"Create a resource with BOTH precondition and postcondition in lifecycle")
 		return result
 	}
 
@@ -337,9 +357,6 @@ func validateCombinedConditionsStructure(proof *ProofData) ValidationResult {
 	}
 
 	if validResource == nil {
-		result.Message = "No resource found with both precondition and postcondition"
-		result.Details = append(result.Details, "You must use BOTH in the same resource's lifecycle block")
-
 		// Give specific feedback
 		hasPre := false
 		hasPost := false
@@ -348,35 +365,35 @@ func validateCombinedConditionsStructure(proof *ProofData) ValidationResult {
 			if r.Lifecycle != nil {
 				if len(r.Lifecycle.Preconditions) > 0 {
 					hasPre = true
-					result.Details = append(result.Details, fmt.Sprintf("  Resource '%s' has preconditions", r.ResourceName))
 				}
 				if len(r.Lifecycle.Postconditions) > 0 {
 					hasPost = true
-					result.Details = append(result.Details, fmt.Sprintf("  Resource '%s' has postconditions", r.ResourceName))
 				}
 			}
 		}
 
+		hint := "You must use BOTH in the same resource's lifecycle block"
 		if hasPre && !hasPost {
-			result.Details = append(result.Details, "Missing: postconditions (add postcondition block to validate after creation)")
+			hint = "Missing: postconditions (add postcondition block to validate after creation)"
 		} else if hasPost && !hasPre {
-			result.Details = append(result.Details, "Missing: preconditions (add precondition block to validate before creation)")
+			hint = "Missing: preconditions (add precondition block to validate before creation)"
 		}
 
+		result.fail("conditions.combined", "No resource found with both precondition and postcondition", hint)
 		return result
 	}
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ Found resource '%s' with both condition types", validResource.ResourceName))
+	result.pass("conditions.combined", fmt.Sprintf("Found resource '%s' with both condition types", validResource.ResourceName))
 
 	// Validate preconditions don't use self
 	for i, precond := range validResource.Lifecycle.Preconditions {
 		if conditionUsesSelf(precond.Condition) {
-			result.Message = fmt.Sprintf("Precondition %d incorrectly uses 'self'", i+1)
-			result.Details = append(result.Details, "  ✗ Preconditions should NOT use 'self'")
+			result.fail(fmt.Sprintf("precondition.%d.no_self", i+1), fmt.Sprintf("Precondition %d incorrectly uses 'self'", i+1),
+				"Preconditions should NOT use 'self'")
 			return result
 		}
 	}
-	result.Details = append(result.Details, fmt.Sprintf("  ✓ %d precondition(s) correctly validate inputs", len(validResource.Lifecycle.Preconditions)))
+	result.pass("precondition.no_self", fmt.Sprintf("%d precondition(s) correctly validate inputs", len(validResource.Lifecycle.Preconditions)))
 
 	// Validate postconditions DO use self
 	selfCount := 0
@@ -387,28 +404,30 @@ func validateCombinedConditionsStructure(proof *ProofData) ValidationResult {
 	}
 
 	if selfCount == 0 {
-		result.Message = "Postconditions must use 'self' to reference resource attributes"
-		result.Details = append(result.Details, "  ✗ None of your postconditions use 'self'")
+		result.fail("postcondition.uses_self", "Postconditions must use 'self' to reference resource attributes",
+			"None of your postconditions use 'self'")
 		return result
 	}
-	result.Details = append(result.Details, fmt.Sprintf("  ✓ %d postcondition(s) correctly use 'self'", selfCount))
+	result.pass("postcondition.uses_self", fmt.Sprintf("%d postcondition(s) correctly use 'self'", selfCount))
 
 	// Verify error messages are descriptive
-	for _, pre := range validResource.Lifecycle.Preconditions {
+	for i, pre := range validResource.Lifecycle.Preconditions {
 		if len(pre.ErrorMessage) < 10 {
-			result.Message = "Precondition error messages must be descriptive (min 10 chars)"
+			result.failCount(fmt.Sprintf("precondition.%d.error_message", i+1), "Precondition error messages must be descriptive (min 10 chars)",
+				">= 10 chars", len(pre.ErrorMessage), "")
 			return result
 		}
 	}
 
-	for _, post := range validResource.Lifecycle.Postconditions {
+	for i, post := range validResource.Lifecycle.Postconditions {
 		if len(post.ErrorMessage) < 10 {
-			result.Message = "Postcondition error messages must be descriptive (min 10 chars)"
+			result.failCount(fmt.Sprintf("postcondition.%d.error_message", i+1), "Postcondition error messages must be descriptive (min 10 chars)",
+				">= 10 chars", len(post.ErrorMessage), "")
 			return result
 		}
 	}
 
-	result.Details = append(result.Details, "  ✓ All error messages are descriptive")
+	result.pass("error_messages", "All error messages are descriptive")
 
 	result.Success = true
 	result.Flag = "flag{c0mb1n3d_c0nd1t10ns_m4st3r}"
@@ -423,8 +442,8 @@ func validateDataSourceConditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.DataSources) == 0 {
-		result.Message = "No data sources provided"
-		result.Details = append(result.Details, "Create a data source with postcondition to validate fetched data")
+		result.fail("data_source.present", "No data sources provided",
+			"Create a data source with postcondition to validate fetched data")
 		return result
 	}
 
@@ -438,28 +457,37 @@ func validateDataSourceConditionStructure(proof *ProofData) ValidationResult {
 	}
 
 	if validDataSource == nil {
-		result.Message = "No data source with postconditions found"
-		result.Details = append(result.Details, "Data sources use postconditions to validate fetched data")
+		result.fail("postcondition.present", "No data source with postconditions found",
+			"Data sources use postconditions to validate fetched data")
 		return result
 	}
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ Found data source '%s' with postconditions", validDataSource.DataSourceName))
+	result.pass("postcondition.present", fmt.Sprintf("Found data source '%s' with postconditions", validDataSource.DataSourceName))
 
 	// Validate postconditions use self
 	for i, postcond := range validDataSource.Lifecycle.Postconditions {
+		prefix := fmt.Sprintf("postcondition.%d", i+1)
+
 		if !conditionUsesSelf(postcond.Condition) {
-			result.Message = fmt.Sprintf("Data source postcondition %d must use 'self'", i+1)
-			result.Details = append(result.Details, "  ✗ Use 'self' to reference data source attributes")
+			result.fail(prefix+".uses_self", fmt.Sprintf("Data source postcondition %d must use 'self'", i+1),
+				"Use 'self' to reference data source attributes")
 			return result
 		}
 
 		selfRefs := extractSelfReferences(postcond.Condition)
-		result.Details = append(result.Details, fmt.Sprintf("  ✓ Validates data attributes: %s", strings.Join(selfRefs, ", ")))
+		result.AddCheck(Check{
+			ID:      prefix + ".uses_self",
+			Status:  CheckPass,
+			Actual:  strings.Join(selfRefs, ", "),
+			Message: fmt.Sprintf("Validates data attributes: %s", strings.Join(selfRefs, ", ")),
+		})
 
 		if !validateErrorMessage(postcond.ErrorMessage, 15) {
-			result.Message = "Data source error messages should be detailed (min 15 chars)"
+			result.failCount(prefix+".error_message", "Data source error messages should be detailed (min 15 chars)",
+				">= 15 chars", len(strings.TrimSpace(postcond.ErrorMessage)), "")
 			return result
 		}
+		result.pass(prefix+".error_message", fmt.Sprintf("Postcondition %d has a detailed error message", i+1))
 	}
 
 	result.Success = true
@@ -472,8 +500,14 @@ func validateOutputConditionStructure(proof *ProofData) ValidationResult {
 	result := ValidationResult{
 		Success: false,
 		Message: "Output condition validation requires manual proof_of_work",
-		Details: []string{"Use proof_of_work with uses_output_block, uses_precondition, etc."},
+		Details: []string{},
 	}
+	result.AddCheck(Check{
+		ID:      "output.structure",
+		Status:  CheckSkip,
+		Message: "Structured proof is not inspected for output conditions",
+		Hint:    "Use proof_of_work with uses_output_block, uses_precondition, etc.",
+	})
 	return result
 }
 
@@ -484,8 +518,8 @@ func validateValidationChainStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.Resources) < 3 {
-		result.Message = fmt.Sprintf("Validation chain requires at least 3 resources (found %d)", len(proof.Resources))
-		result.Details = append(result.Details, "Create a chain of resources with interconnected conditions")
+		result.failCount("chain.resources", fmt.Sprintf("Validation chain requires at least 3 resources (found %d)", len(proof.Resources)),
+			">= 3", len(proof.Resources), "Create a chain of resources with interconnected conditions")
 		return result
 	}
 
@@ -499,13 +533,13 @@ func validateValidationChainStructure(proof *ProofData) ValidationResult {
 	}
 
 	if totalConditions < 4 {
-		result.Message = fmt.Sprintf("Validation chain needs at least 4 conditions (found %d)", totalConditions)
-		result.Details = append(result.Details, "Add more pre/postconditions across your resources")
+		result.failCount("chain.conditions", fmt.Sprintf("Validation chain needs at least 4 conditions (found %d)", totalConditions),
+			">= 4", totalConditions, "Add more pre/postconditions across your resources")
 		return result
 	}
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ %d resources in chain", len(proof.Resources)))
-	result.Details = append(result.Details, fmt.Sprintf("✓ %d total conditions", totalConditions))
+	result.pass("chain.resources", fmt.Sprintf("%d resources in chain", len(proof.Resources)))
+	result.pass("chain.conditions", fmt.Sprintf("%d total conditions", totalConditions))
 
 	// Check for depends_on usage
 	hasDependencies := false
@@ -517,12 +551,12 @@ func validateValidationChainStructure(proof *ProofData) ValidationResult {
 	}
 
 	if !hasDependencies {
-		result.Message = "Validation chain should use depends_on for proper ordering"
-		result.Details = append(result.Details, "  ✗ Add depends_on meta-argument to establish dependencies")
+		result.fail("chain.depends_on", "Validation chain should use depends_on for proper ordering",
+			"Add depends_on meta-argument to establish dependencies")
 		return result
 	}
 
-	result.Details = append(result.Details, "✓ Uses depends_on for proper ordering")
+	result.pass("chain.depends_on", "Uses depends_on for proper ordering")
 	result.Success = true
 	result.Flag = "flag{v4l1d4t10n_ch41n_4rch1t3ct}"
 	result.Message = "✓ Validation chain completed! You've built an interconnected validation system."
@@ -536,27 +570,26 @@ func validateModuleContractStructure(proof *ProofData) ValidationResult {
 	}
 
 	if proof.Module == nil {
-		result.Message = "No module proof provided"
-		result.Details = append(result.Details, "Create a module with input/output validations")
+		result.fail("module.present", "No module proof provided", "Create a module with input/output validations")
 		return result
 	}
 
 	m := proof.Module
-	result.Details = append(result.Details, fmt.Sprintf("✓ Module '%s' provided", m.ModuleName))
+	result.pass("module.present", fmt.Sprintf("Module '%s' provided", m.ModuleName))
 
 	if len(m.InputValidations) < 2 {
-		result.Message = fmt.Sprintf("Module needs at least 2 input validations (found %d)", len(m.InputValidations))
-		result.Details = append(result.Details, "  ✗ Add variable validation blocks or preconditions")
+		result.failCount("module.input_validations", fmt.Sprintf("Module needs at least 2 input validations (found %d)", len(m.InputValidations)),
+			">= 2", len(m.InputValidations), "Add variable validation blocks or preconditions")
 		return result
 	}
-	result.Details = append(result.Details, fmt.Sprintf("  ✓ %d input validations", len(m.InputValidations)))
+	result.pass("module.input_validations", fmt.Sprintf("%d input validations", len(m.InputValidations)))
 
 	if len(m.OutputValidations) < 2 {
-		result.Message = fmt.Sprintf("Module needs at least 2 output validations (found %d)", len(m.OutputValidations))
-		result.Details = append(result.Details, "  ✗ Add postconditions to outputs")
+		result.failCount("module.output_validations", fmt.Sprintf("Module needs at least 2 output validations (found %d)", len(m.OutputValidations)),
+			">= 2", len(m.OutputValidations), "Add postconditions to outputs")
 		return result
 	}
-	result.Details = append(result.Details, fmt.Sprintf("  ✓ %d output validations", len(m.OutputValidations)))
+	result.pass("module.output_validations", fmt.Sprintf("%d output validations", len(m.OutputValidations)))
 
 	// Verify error messages
 	shortMessages := 0
@@ -567,12 +600,12 @@ func validateModuleContractStructure(proof *ProofData) ValidationResult {
 	}
 
 	if shortMessages > 0 {
-		result.Message = fmt.Sprintf("%d validation(s) have error messages that are too short", shortMessages)
-		result.Details = append(result.Details, "  ✗ Error messages should be consumer-friendly (min 20 chars)")
+		result.failCount("module.error_messages", fmt.Sprintf("%d validation(s) have error messages that are too short", shortMessages),
+			"0 short messages", shortMessages, "Error messages should be consumer-friendly (min 20 chars)")
 		return result
 	}
 
-	result.Details = append(result.Details, "  ✓ All error messages are consumer-friendly")
+	result.pass("module.error_messages", "All error messages are consumer-friendly")
 	result.Success = true
 	result.Flag = "flag{m0dul3_c0ntr4ct_d3s1gn3r_m4st3r}"
 	result.Message = "✓ Module contract completed! Your module has a clear, validated interface."
@@ -586,13 +619,14 @@ func validateSelfReferenceStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(proof.Resources) == 0 {
-		result.Message = "No resources provided"
+		result.fail("resource.present", "No resources provided", "")
 		return result
 	}
 
 	// Find resource with multiple self references
 	allSelfRefs := make(map[string]bool)
 	postcondCount := 0
+	usesComplexLogic := false
 
 	for _, r := range proof.Resources {
 		if r.Lifecycle != nil {
@@ -605,20 +639,24 @@ func validateSelfReferenceStructure(proof *ProofData) ValidationResult {
 
 				// Check for complex logic
 				if strings.Contains(postcond.Condition, "&&") || strings.Contains(postcond.Condition, "||") {
-					result.Details = append(result.Details, "  ✓ Uses complex boolean logic")
+					usesComplexLogic = true
 				}
 			}
 		}
 	}
 
+	if usesComplexLogic {
+		result.pass("self.complex_logic", "Uses complex boolean logic")
+	}
+
 	if len(allSelfRefs) < 3 {
-		result.Message = fmt.Sprintf("Must reference at least 3 different attributes with 'self' (found %d)", len(allSelfRefs))
-		result.Details = append(result.Details, "Add more postconditions that validate different attributes")
+		result.failCount("self.references", fmt.Sprintf("Must reference at least 3 different attributes with 'self' (found %d)", len(allSelfRefs)),
+			">= 3", len(allSelfRefs), "Add more postconditions that validate different attributes")
 		return result
 	}
 
 	if postcondCount < 2 {
-		result.Message = "Must have at least 2 postconditions"
+		result.failCount("self.postconditions", "Must have at least 2 postconditions", ">= 2", postcondCount, "")
 		return result
 	}
 
@@ -626,9 +664,16 @@ func validateSelfReferenceStructure(proof *ProofData) ValidationResult {
 	for ref := range allSelfRefs {
 		refList = append(refList, ref)
 	}
+	sort.Strings(refList)
 
-	result.Details = append(result.Details, fmt.Sprintf("✓ %d unique self references: %s", len(refList), strings.Join(refList, ", ")))
-	result.Details = append(result.Details, fmt.Sprintf("✓ %d postconditions", postcondCount))
+	result.AddCheck(Check{
+		ID:       "self.references",
+		Status:   CheckPass,
+		Expected: ">= 3",
+		Actual:   strconv.Itoa(len(refList)),
+		Message:  fmt.Sprintf("%d unique self references: %s", len(refList), strings.Join(refList, ", ")),
+	})
+	result.pass("self.postconditions", fmt.Sprintf("%d postconditions", postcondCount))
 
 	result.Success = true
 	result.Flag = "flag{s3lf_r3f3r3nc3_m4st3r_pr0}"
@@ -678,29 +723,28 @@ func validateConditionalValidationStructure(proof *ProofData) ValidationResult {
 	}
 
 	if !hasAnd {
-		result.Message = "Must use && operator in conditions"
+		result.fail("logic.and", "Must use && operator in conditions", "")
 		return result
 	}
-	result.Details = append(result.Details, "✓ Uses && operator")
+	result.pass("logic.and", "Uses && operator")
 
 	if !hasOr {
-		result.Message = "Must use || operator in conditions"
+		result.fail("logic.or", "Must use || operator in conditions", "")
 		return result
 	}
-	result.Details = append(result.Details, "✓ Uses || operator")
+	result.pass("logic.or", "Uses || operator")
 
 	if !hasFunctions {
-		result.Message = "Must use Terraform functions in conditions"
-		result.Details = append(result.Details, "  Try: length(), can(), try(), contains(), etc.")
+		result.fail("logic.functions", "Must use Terraform functions in conditions", "Try: length(), can(), try(), contains(), etc.")
 		return result
 	}
-	result.Details = append(result.Details, "✓ Uses Terraform functions")
+	result.pass("logic.functions", "Uses Terraform functions")
 
 	if maxComplexity < 2 {
-		result.Message = "Conditions not complex enough (need multiple operators)"
+		result.failCount("logic.complexity", "Conditions not complex enough (need multiple operators)", ">= 2 operators", maxComplexity, "")
 		return result
 	}
-	result.Details = append(result.Details, fmt.Sprintf("✓ Complexity score: %d/10", min(maxComplexity+3, 10)))
+	result.pass("logic.complexity", fmt.Sprintf("Complexity score: %d/10", min(maxComplexity+3, 10)))
 
 	result.Success = true
 	result.Flag = "flag{c0nd1t10n4l_v4l1d4t10n_3xp3rt}"
@@ -736,7 +780,8 @@ func validateErrorMessageStructure(proof *ProofData) ValidationResult {
 	}
 
 	if len(allMessages) < 3 {
-		result.Message = fmt.Sprintf("Need at least 3 error messages (found %d)", len(allMessages))
+		result.failCount("messages.count", fmt.Sprintf("Need at least 3 error messages (found %d)", len(allMessages)),
+			">= 3", len(allMessages), "")
 		return result
 	}
 
@@ -763,23 +808,24 @@ func validateErrorMessageStructure(proof *ProofData) ValidationResult {
 	}
 
 	if shortCount > 0 {
-		result.Message = fmt.Sprintf("%d error message(s) too short (min 20 chars)", shortCount)
+		result.failCount("messages.length", fmt.Sprintf("%d error message(s) too short (min 20 chars)", shortCount),
+			"0 short messages", shortCount, "")
 		return result
 	}
-	result.Details = append(result.Details, fmt.Sprintf("✓ %d error messages, all descriptive", len(allMessages)))
+	result.pass("messages.length", fmt.Sprintf("%d error messages, all descriptive", len(allMessages)))
 
 	if !hasInterpolation {
-		result.Message = "Error messages should use interpolation to show actual values"
-		result.Details = append(result.Details, "  Example: \"Value must be positive, got ${var.count}\"")
+		result.fail("messages.interpolation", "Error messages should use interpolation to show actual values",
+			"Example: \"Value must be positive, got ${var.count}\"")
 		return result
 	}
-	result.Details = append(result.Details, "✓ Uses interpolation to show actual values")
+	result.pass("messages.interpolation", "Uses interpolation to show actual values")
 
 	if !hasContext {
-		result.Message = "Error messages should provide context about what failed"
+		result.fail("messages.context", "Error messages should provide context about what failed", "")
 		return result
 	}
-	result.Details = append(result.Details, "✓ Messages provide helpful context")
+	result.pass("messages.context", "Messages provide helpful context")
 
 	result.Success = true
 	result.Flag = "flag{3rr0r_m3ss4g3_d3s1gn3r_pr0}"
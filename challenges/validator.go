@@ -1,6 +1,7 @@
 package challenges
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/sha256"
 	"encoding/base64"
@@ -19,7 +20,19 @@ type Challenge struct {
 	Flag        string
 	Difficulty  string
 	Category    string
-	Validator   func(input map[string]interface{}) (bool, string, error) // Legacy validator
+	Validator   Validator
+	// Version is bumped whenever the challenge requirements change, so that
+	// completions recorded against an older definition can be re-validated.
+	// Zero means the original definition (version 1).
+	Version int
+}
+
+// DefinitionVersion returns the version of the challenge requirements.
+func (c *Challenge) DefinitionVersion() int {
+	if c.Version < 1 {
+		return 1
+	}
+	return c.Version
 }
 
 // ValidationResult contains the result of proof validation
@@ -28,6 +41,26 @@ type ValidationResult struct {
 	Flag    string
 	Message string
 	Details []string
+	Checks  []Check
+}
+
+// CheckStatus is the outcome of a single validation check
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip"
+)
+
+// Check is the structured outcome of one criterion evaluated by a validator
+type Check struct {
+	ID       string
+	Status   CheckStatus
+	Expected string
+	Actual   string
+	Message  string
+	Hint     string
 }
 
 // ProofData contains all types of proof that can be submitted
@@ -35,8 +68,25 @@ type ProofData struct {
 	Resources   []ResourceProof
 	DataSources []DataSourceProof
 	Module      *ModuleProof
-	Manual      map[string]interface{}
-	Source      string
+	CheckBlocks []CheckBlockProof
+	Refactor    *RefactorProof
+	TestFiles   []TestFileProof
+	Variables   []VariableProof
+	Providers   *ProviderProof
+	Expression  *ExpressionProof
+	// EncodedOutput is output produced with encoding or template functions
+	EncodedOutput string
+	// RecoveredFlag is a flag recovered from state
+	RecoveredFlag string
+	// Drift is a drift target's update history observed by the provider
+	Drift *DriftProof
+	// Flaky is a flaky object's operation history recorded by the provider
+	Flaky *FlakyProof
+	// Chain is a player's progress through a puzzle chain recorded by the
+	// provider
+	Chain  *ChainProof
+	Manual map[string]interface{}
+	Source string
 }
 
 // ResourceProof contains proof from a Terraform resource
@@ -64,6 +114,66 @@ type ModuleProof struct {
 	ResourcesCount    int
 }
 
+// CheckBlockProof contains proof from a Terraform check block
+type CheckBlockProof struct {
+	Name             string
+	ScopedDataSource *DataSourceProof
+	Assertions       []ConditionBlock
+}
+
+// RefactorProof describes a configuration refactor: the resource addresses
+// before and after it, and the moved, import and removed blocks used
+type RefactorProof struct {
+	PreviousAddresses []string
+	CurrentAddresses  []string
+	Moved             []MovedBlock
+	Imports           []ImportBlock
+	Removed           []RemovedBlock
+}
+
+// MovedBlock represents a moved block
+type MovedBlock struct {
+	From string
+	To   string
+}
+
+// ImportBlock represents an import block
+type ImportBlock struct {
+	To string
+	ID string
+}
+
+// RemovedBlock represents a removed block
+type RemovedBlock struct {
+	From    string
+	Destroy bool
+}
+
+// ProviderProof describes the provider instances a configuration uses and
+// how they are passed into modules
+type ProviderProof struct {
+	Instances []ProviderInstanceProof
+	Modules   []ModuleProviders
+}
+
+// ProviderInstanceProof identifies a provider instance by a resource it
+// created. InstanceName and PlayerName are the resource's provider_instance
+// and player_name attributes.
+type ProviderInstanceProof struct {
+	Alias           string
+	InstanceName    string
+	PlayerName      string
+	ResourceAddress string
+}
+
+// ModuleProviders represents the providers passed into a module call and the
+// configuration_aliases the module declares
+type ModuleProviders struct {
+	ModuleName           string
+	Providers            map[string]string
+	ConfigurationAliases []string
+}
+
 // LifecycleConfig represents lifecycle block configuration
 type LifecycleConfig struct {
 	CreateBeforeDestroy bool             `json:"create_before_destroy"`
@@ -104,7 +214,7 @@ func registerBasicChallenges() {
 		Flag:        "flag{t3rr4f0rm_d3p3nd3nc13s}",
 		Difficulty:  "beginner",
 		Category:    "fundamentals",
-		Validator:   validateBasics,
+		Validator:   NewLegacyValidator(validateBasics, "dependencies"),
 	}
 
 	Challenges["expression_expert"] = &Challenge{
@@ -115,7 +225,9 @@ func registerBasicChallenges() {
 		Flag:        "flag{3xpr3ss10ns_unl0ck3d}",
 		Difficulty:  "intermediate",
 		Category:    "expressions",
-		Validator:   validateExpressions,
+		Version:     2,
+		Validator: NewStructuredValidator(validateExpressionTask("expression_expert", "flag{3xpr3ss10ns_unl0ck3d}",
+			"✓ Expression mastered! base64encode(sha256()) computed by the provider from your own expression."), ProofKindExpression),
 	}
 
 	Challenges["state_secrets"] = &Challenge{
@@ -126,7 +238,7 @@ func registerBasicChallenges() {
 		Flag:        "flag{st4t3_m4n4g3m3nt_m4st3r}",
 		Difficulty:  "beginner",
 		Category:    "state",
-		Validator:   validateState,
+		Validator:   NewLegacyValidator(validateState, "resource_count"),
 	}
 
 	Challenges["module_master"] = &Challenge{
@@ -137,7 +249,10 @@ func registerBasicChallenges() {
 		Flag:        "flag{m0dul3_c0mp0s1t10n_pr0}",
 		Difficulty:  "advanced",
 		Category:    "modules",
-		Validator:   validateModules,
+		Validator: CombineValidators(
+			NewStructuredValidator(validateModuleContractStructure, ProofKindModule),
+			NewLegacyValidator(validateModules, "module_output"),
+		),
 	}
 
 	Challenges["dynamic_blocks"] = &Challenge{
@@ -148,29 +263,35 @@ func registerBasicChallenges() {
 		Flag:        "flag{dyn4m1c_bl0cks_r0ck}",
 		Difficulty:  "intermediate",
 		Category:    "advanced-syntax",
-		Validator:   validateDynamicBlocks,
+		Validator:   NewLegacyValidator(validateDynamicBlocks, "dynamic_block_count"),
 	}
 
 	Challenges["for_each_wizard"] = &Challenge{
 		ID:          "for_each_wizard",
 		Name:        "For-Each Wizard",
-		Description: "Use for_each to manage multiple resources elegantly",
+		Description: "Use for_each to create one terraform_data resource per us-east-1 server in ctfchallenge_dataset, keyed by name with the server's size as input, submit it as a resource proof with its instance keys and jsonencode the map of instance key to output",
 		Points:      250,
 		Flag:        "flag{f0r_34ch_1s_p0w3rful}",
 		Difficulty:  "intermediate",
 		Category:    "loops",
-		Validator:   validateForEach,
+		Version:     2,
+		Validator: NewStructuredValidator(validateForEachTask(
+			"flag{f0r_34ch_1s_p0w3rful}", "✓ for_each mastered! One instance per server, each addressed by name.",
+			"for_each = { for s in servers : s.name => s if s.region == \"us-east-1\" }"), ProofKindEncoded, ProofKindResource),
 	}
 
 	Challenges["data_source_detective"] = &Challenge{
 		ID:          "data_source_detective",
 		Name:        "Data Source Detective",
-		Description: "Query and filter data sources effectively",
+		Description: "Query ctfchallenge_dataset for the servers tagged critical and jsonencode their names, in dataset order",
 		Points:      150,
 		Flag:        "flag{d4t4_s0urc3_sl3uth}",
 		Difficulty:  "beginner",
 		Category:    "data-sources",
-		Validator:   validateDataSource,
+		Version:     2,
+		Validator: NewStructuredValidator(validateDatasetTask(expectedCriticalNames,
+			"flag{d4t4_s0urc3_sl3uth}", "✓ Case closed! The data source filtered the fleet down to its critical servers.",
+			"the tag filter and servers[*].name"), ProofKindEncoded),
 	}
 
 	Challenges["cryptographic_compute"] = &Challenge{
@@ -181,61 +302,75 @@ func registerBasicChallenges() {
 		Flag:        "flag{crypt0_func_m4st3r}",
 		Difficulty:  "advanced",
 		Category:    "functions",
-		Validator:   validateCrypto,
+		Version:     2,
+		Validator: NewStructuredValidator(validateExpressionTask("cryptographic_compute", "flag{crypt0_func_m4st3r}",
+			"✓ Cryptographic functions mastered! md5(sha256()) computed by the provider from your own expression."), ProofKindExpression),
 	}
 }
 
-// ValidateProof validates the proof data and returns a result
-func (c *Challenge) ValidateProof(proof *ProofData) ValidationResult {
-	// If we have structured proof (resources, data sources, module), use enhanced validation
-	if len(proof.Resources) > 0 || len(proof.DataSources) > 0 || proof.Module != nil {
-		return c.validateStructuredProof(proof)
+// ValidateProof validates the proof data and returns a result. ctx is passed
+// through to the challenge's Validator so that cancellation is honoured.
+func (c *Challenge) ValidateProof(ctx context.Context, proof *ProofData) (ValidationResult, error) {
+	if !SupportsProof(c.Validator, proof) {
+		return unsupportedProofResult(c.Validator, proof), nil
 	}
 
-	// Fall back to legacy validator for manual proof
-	success, flag, err := c.Validator(proof.Manual)
-	result := ValidationResult{
-		Success: success,
-		Flag:    flag,
-		Details: []string{},
+	result, err := c.Validator.Validate(ctx, proof)
+	if err != nil {
+		return ValidationResult{}, err
 	}
 
-	if err != nil {
-		result.Message = err.Error()
-	} else if success {
-		result.Message = fmt.Sprintf("✓ Challenge '%s' completed successfully!", c.Name)
-	} else {
-		result.Message = "Challenge requirements not met"
+	if result.Message == "" {
+		if result.Success {
+			result.Message = fmt.Sprintf("✓ Challenge '%s' completed successfully!", c.Name)
+		} else {
+			result.Message = "Challenge requirements not met"
+		}
 	}
 
-	return result
+	return result, nil
 }
 
-// validateStructuredProof validates proof based on actual resource/module structure
-func (c *Challenge) validateStructuredProof(proof *ProofData) ValidationResult {
-	// Delegate to category-specific validators
-	switch c.Category {
-	case "validation":
-		return validateValidationChallengeStructure(c, proof)
-	case "meta-arguments":
-		return validateMetaArgumentStructure(c, proof)
-	case "modules":
-		return validateModuleStructure(c, proof)
-	default:
-		// Fall back to legacy validation
-		success, flag, err := c.Validator(proof.Manual)
-		result := ValidationResult{
-			Success: success,
-			Flag:    flag,
-			Details: []string{},
-		}
-		if err != nil {
-			result.Message = err.Error()
-		}
-		return result
+// AddCheck records a check and mirrors it into Details for human-readable output
+func (r *ValidationResult) AddCheck(c Check) {
+	r.Checks = append(r.Checks, c)
+
+	glyph := "✓"
+	switch c.Status {
+	case CheckFail:
+		glyph = "✗"
+	case CheckSkip:
+		glyph = "-"
+	}
+
+	line := fmt.Sprintf("%s %s", glyph, c.Message)
+	if c.Status == CheckFail && (c.Expected != "" || c.Actual != "") {
+		line += fmt.Sprintf(" (expected %s, got %s)", c.Expected, c.Actual)
+	}
+	r.Details = append(r.Details, line)
+
+	if c.Hint != "" {
+		r.Details = append(r.Details, fmt.Sprintf("  Hint: %s", c.Hint))
 	}
 }
 
+// pass records a passing check
+func (r *ValidationResult) pass(id, message string) {
+	r.AddCheck(Check{ID: id, Status: CheckPass, Message: message})
+}
+
+// fail records a failing check and sets the result message
+func (r *ValidationResult) fail(id, message, hint string) {
+	r.Message = message
+	r.AddCheck(Check{ID: id, Status: CheckFail, Message: message, Hint: hint})
+}
+
+// failCount records a failing check comparing an expected and actual count
+func (r *ValidationResult) failCount(id, message string, expected string, actual int, hint string) {
+	r.Message = message
+	r.AddCheck(Check{ID: id, Status: CheckFail, Expected: expected, Actual: strconv.Itoa(actual), Message: message, Hint: hint})
+}
+
 // Helper function to check if condition uses 'self' reference
 func conditionUsesSelf(condition string) bool {
 	return strings.Contains(condition, "self.")
@@ -269,45 +404,10 @@ func validateBasics(input map[string]interface{}) (bool, string, error) {
 	return false, "", fmt.Errorf("provide 'dependencies' as a comma-separated string in proof_of_work")
 }
 
-func validateExpressions(input map[string]interface{}) (bool, string, error) {
-	correctFlag := "flag{3xpr3ss10ns_unl0ck3d}"
-
-	if result, ok := input["computed_value"].(string); ok {
-		inputString := "terraformexpressionsrock"
-		hashBytes := sha256.Sum256([]byte(inputString))
-		hexString := hex.EncodeToString(hashBytes[:])
-		expectedB64 := base64.StdEncoding.EncodeToString([]byte(hexString))
-		wrongB64 := base64.StdEncoding.EncodeToString(hashBytes[:])
-
-		if result == expectedB64 {
-			return true, correctFlag, nil
-		}
-
-		if result == wrongB64 {
-			return false, "", fmt.Errorf("you base64-encoded the raw SHA256 bytes instead of the hex string.\n\n"+
-				"Terraform's sha256() returns a HEX STRING, not raw bytes.\n"+
-				"Use: base64encode(sha256(\"%s\"))\n\n"+
-				"The sha256() function returns: %s\n"+
-				"Then base64encode() of that hex string gives: %s",
-				inputString, hexString, expectedB64)
-		}
-
-		return false, "", fmt.Errorf("computed value doesn't match expected result.\n\n"+
-			"Expected computation in Terraform:\n"+
-			"  base64encode(sha256(\"%s\"))\n\n"+
-			"Step by step:\n"+
-			"  1. sha256(\"%s\") = %s\n"+
-			"  2. base64encode(\"%s\") = %s\n\n"+
-			"Your result: %s\n\n"+
-			"Hint: Copy this into terraform console to test:\n"+
-			"  base64encode(sha256(\"%s\"))",
-			inputString,
-			inputString, hexString,
-			hexString, expectedB64,
-			result,
-			inputString)
-	}
-	return false, "", fmt.Errorf("provide 'computed_value' in proof_of_work")
+// expectedExpressionExpert computes base64encode(sha256(input)).
+func expectedExpressionExpert(input string) string {
+	hashBytes := sha256.Sum256([]byte(input))
+	return base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(hashBytes[:])))
 }
 
 func validateState(input map[string]interface{}) (bool, string, error) {
@@ -354,107 +454,44 @@ func validateDynamicBlocks(input map[string]interface{}) (bool, string, error) {
 	return false, "", fmt.Errorf("provide 'dynamic_block_count' in proof_of_work")
 }
 
-func validateForEach(input map[string]interface{}) (bool, string, error) {
-	correctFlag := "flag{f0r_34ch_1s_p0w3rful}"
-
-	if items, ok := input["items"].(string); ok {
-		requiredItems := []string{"alpha", "beta", "gamma", "delta"}
-		matchCount := 0
-		for _, item := range requiredItems {
-			if strings.Contains(items, item) {
-				matchCount++
-			}
-		}
-		if matchCount == len(requiredItems) {
-			return true, correctFlag, nil
-		}
-
-		missing := []string{}
-		for _, item := range requiredItems {
-			if !strings.Contains(items, item) {
-				missing = append(missing, item)
-			}
-		}
-		return false, "", fmt.Errorf("missing required items: %s. Need all of: alpha, beta, gamma, delta", strings.Join(missing, ", "))
-	}
-	return false, "", fmt.Errorf("provide 'items' in proof_of_work")
+// expectedCryptoCompute computes md5(sha256(input)).
+func expectedCryptoCompute(input string) string {
+	shaBytes := sha256.Sum256([]byte(input))
+	md5Hash := md5.Sum([]byte(hex.EncodeToString(shaBytes[:])))
+	return hex.EncodeToString(md5Hash[:])
 }
 
-func validateDataSource(input map[string]interface{}) (bool, string, error) {
-	correctFlag := "flag{d4t4_s0urc3_sl3uth}"
-
-	if filteredCount, ok := input["filtered_count"].(string); ok {
-		count, err := strconv.Atoi(filteredCount)
-		if err != nil {
-			return false, "", fmt.Errorf("filtered_count must be a number")
-		}
-		if count == 7 {
-			return true, correctFlag, nil
-		}
-		return false, "", fmt.Errorf("incorrect filter result (expected 7, got %d)", count)
-	}
-	return false, "", fmt.Errorf("provide 'filtered_count' in proof_of_work")
-}
-
-func validateCrypto(input map[string]interface{}) (bool, string, error) {
-	correctFlag := "flag{crypt0_func_m4st3r}"
-
-	if hash, ok := input["crypto_hash"].(string); ok {
-		secret := "terraform_ctf_11_2025"
-		shaBytes := sha256.Sum256([]byte(secret))
-		shaHex := hex.EncodeToString(shaBytes[:])
-		md5Hash := md5.Sum([]byte(shaHex))
-		expected := hex.EncodeToString(md5Hash[:])
-
-		if hash == expected {
-			return true, correctFlag, nil
-		}
-
-		return false, "", fmt.Errorf("cryptographic hash doesn't match expected value.\n\n"+
-			"Expected computation in Terraform:\n"+
-			"  md5(sha256(\"%s\"))\n\n"+
-			"Step by step:\n"+
-			"  1. sha256(\"%s\") = %s\n"+
-			"  2. md5(\"%s\") = %s\n\n"+
-			"Your result: %s\n\n"+
-			"Hint: Use terraform console:\n"+
-			"  md5(sha256(\"%s\"))",
-			secret,
-			secret, shaHex,
-			shaHex, expected,
-			hash,
-			secret)
+// XOR returns the exclusive or of all numbers.
+func XOR(numbers []int) int {
+	result := 0
+	for _, num := range numbers {
+		result ^= num
 	}
-	return false, "", fmt.Errorf("provide 'crypto_hash' in proof_of_work")
+	return result
 }
 
-func ValidatePuzzleInput(inputs map[string]interface{}) (bool, string) {
-	numbers := []int{}
-
-	for i := 1; i <= 5; i++ {
-		key := fmt.Sprintf("input_%d", i)
-		if valStr, ok := inputs[key].(string); ok {
-			val, err := strconv.Atoi(valStr)
-			if err == nil {
-				numbers = append(numbers, val)
-			}
-		}
+// FormatFlag wraps a flag body in the flag{...} format used by every
+// challenge. A value that is already wrapped is returned unchanged.
+func FormatFlag(body string) (string, error) {
+	if strings.HasPrefix(body, "flag{") && strings.HasSuffix(body, "}") {
+		body = strings.TrimSuffix(strings.TrimPrefix(body, "flag{"), "}")
 	}
-
-	if len(numbers) != 5 {
-		return false, "Provide exactly 5 numbers (input_1 through input_5)"
+	if body == "" {
+		return "", fmt.Errorf("flag body must not be empty")
 	}
-
-	xorResult := 0
-	for _, num := range numbers {
-		xorResult ^= num
+	if strings.ContainsAny(body, "{} \t\n") {
+		return "", fmt.Errorf("flag body must not contain braces or whitespace: %q", body)
 	}
+	return "flag{" + body + "}", nil
+}
 
-	if xorResult == 0 {
-		return true, "Puzzle solved! XOR of all inputs equals zero."
-	}
+// MaxHintLevel is the most revealing hint level.
+const MaxHintLevel = 2
 
-	return false, fmt.Sprintf("XOR result: %d (must be 0). Try again!", xorResult)
+// HintCost returns the point penalty for requesting a hint at the given level.
+// Hints cost 10, 20 or 30 points for levels 0, 1 and 2.
+func HintCost(level int) int {
+	return (level + 1) * 10
 }
 
 func GetHint(challengeID string, level int) string {
@@ -467,7 +504,7 @@ func GetHint(challengeID string, level int) string {
 		"expression_expert": {
 			"Look at Terraform's hash and encoding functions",
 			"Combine sha256() and base64encode() functions",
-			"In terraform console, run: base64encode(sha256(\"terraformexpressionsrock\"))",
+			"Submit expression = \"base64encode(sha256(\\\"terraformexpressionsrock\\\"))\" on the flag validator",
 		},
 		"state_secrets": {
 			"The answer to life, the universe, and everything...",
@@ -485,19 +522,19 @@ func GetHint(challengeID string, level int) string {
 			"Create at least 5 dynamic blocks using count or for_each inside the dynamic block",
 		},
 		"for_each_wizard": {
-			"Use for_each with a set or map",
-			"The required items are Greek letters",
-			"Create resources for: alpha, beta, gamma, delta",
+			"Use for_each with a map built from ctfchallenge_dataset",
+			"Key the map by server name and keep only the us-east-1 servers",
+			"Submit encoded_output = jsonencode({ for k, r in terraform_data.server : k => r.output }) with input = each.value.size, plus a resource_proof with meta_arguments = { for_each = jsonencode(keys(terraform_data.server)) }",
 		},
 		"data_source_detective": {
-			"Use a data source and count the results",
-			"Filter or process the data source output",
-			"The expected filtered count is 7",
+			"Use the ctfchallenge_dataset data source and its filters",
+			"Filter the dataset to the servers tagged critical",
+			"Submit encoded_output = jsonencode(data.ctfchallenge_dataset.critical.servers[*].name)",
 		},
 		"cryptographic_compute": {
 			"Chain multiple hash functions",
 			"Start with sha256, then md5 the result",
-			"In terraform console, run: md5(sha256(\"terraform_ctf_11_2025\"))",
+			"Submit expression = \"md5(sha256(\\\"terraform_ctf_11_2025\\\"))\" on the flag validator",
 		},
 	}
 
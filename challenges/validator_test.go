@@ -0,0 +1,43 @@
+package challenges
+
+import (
+	"context"
+	"testing"
+)
+
+// expectValidation validates proof against a challenge and checks that the
+// first failing check is failed, or that it succeeds with the challenge's
+// flag when failed is "".
+func expectValidation(t *testing.T, challengeID string, proof *ProofData, failed string) ValidationResult {
+	t.Helper()
+
+	challenge, ok := Challenges[challengeID]
+	if !ok {
+		t.Fatalf("unknown challenge %q", challengeID)
+	}
+	result, err := challenge.ValidateProof(context.Background(), proof)
+	if err != nil {
+		t.Fatalf("error validating proof: %s", err)
+	}
+
+	if actual := failedCheck(result); actual != failed || result.Success != (failed == "") {
+		t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q (success %t): %s\n\n%v\n\n", failed, actual, result.Success, result.Message, result.Details)
+	}
+	if result.Success && result.Flag != challenge.Flag {
+		t.Fatalf("expected flag %q, got %q", challenge.Flag, result.Flag)
+	}
+	if !result.Success && result.Flag != "" {
+		t.Fatalf("failed validation revealed flag %q", result.Flag)
+	}
+	return result
+}
+
+// failedCheck returns the ID of the first failing check, or "".
+func failedCheck(result ValidationResult) string {
+	for _, check := range result.Checks {
+		if check.Status == CheckFail {
+			return check.ID
+		}
+	}
+	return ""
+}
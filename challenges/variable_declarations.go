@@ -0,0 +1,222 @@
+package challenges
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// VariableProof contains proof parsed from a variable block
+type VariableProof struct {
+	Name        string
+	TypeExpr    string
+	Type        cty.Type
+	Defaults    *typeexpr.Defaults
+	Default     cty.Value
+	HasDefault  bool
+	Nullable    bool
+	Sensitive   bool
+	Validations []VariableValidation
+}
+
+// VariableValidation represents a validation block in a variable block
+type VariableValidation struct {
+	Condition              hcl.Expression
+	ErrorMessage           hcl.Expression
+	ConditionText          string
+	ErrorMessageText       string
+	ConditionReferences    []string
+	ErrorMessageReferences []string
+}
+
+// ParseVariableDeclarations parses HCL source containing variable blocks,
+// such as the contents of variables.tf. Other blocks are ignored.
+func ParseVariableDeclarations(filename string, src []byte) ([]VariableProof, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	variables := []VariableProof{}
+	body := file.Body.(*hclsyntax.Body)
+
+	for _, block := range body.Blocks {
+		if block.Type != "variable" || len(block.Labels) == 0 {
+			continue
+		}
+		variable, varDiags := parseVariable(block, src)
+		diags = append(diags, varDiags...)
+		variables = append(variables, variable)
+	}
+
+	return variables, diags
+}
+
+func parseVariable(block *hclsyntax.Block, src []byte) (VariableProof, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	variable := VariableProof{
+		Name:     block.Labels[0],
+		Type:     cty.DynamicPseudoType,
+		Default:  cty.NilVal,
+		Nullable: true,
+	}
+
+	if attr, ok := block.Body.Attributes["type"]; ok {
+		variable.TypeExpr = strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+		ty, defaults, typeDiags := typeexpr.TypeConstraintWithDefaults(attr.Expr)
+		diags = append(diags, typeDiags...)
+		if !typeDiags.HasErrors() {
+			variable.Type = ty
+			variable.Defaults = defaults
+		}
+	}
+
+	if attr, ok := block.Body.Attributes["nullable"]; ok {
+		variable.Nullable = boolAttribute(attr, &diags)
+	}
+
+	if attr, ok := block.Body.Attributes["sensitive"]; ok {
+		variable.Sensitive = boolAttribute(attr, &diags)
+	}
+
+	if attr, ok := block.Body.Attributes["default"]; ok {
+		value, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			if variable.Defaults != nil {
+				value = variable.Defaults.Apply(value)
+			}
+			converted, err := convert.Convert(value, variable.Type)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid default value for variable",
+					Detail:   fmt.Sprintf("The default value of variable %q does not match its type constraint: %s.", variable.Name, err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				variable.Default = converted
+				variable.HasDefault = true
+			}
+		}
+	}
+
+	for _, nested := range block.Body.Blocks {
+		if nested.Type != "validation" {
+			continue
+		}
+
+		validation := VariableValidation{}
+		if attr, ok := nested.Body.Attributes["condition"]; ok {
+			validation.Condition = attr.Expr
+			validation.ConditionText = strings.TrimSpace(string(attr.Expr.Range().SliceBytes(src)))
+			for _, traversal := range attr.Expr.Variables() {
+				validation.ConditionReferences = append(validation.ConditionReferences, traversalString(traversal))
+			}
+		}
+		if attr, ok := nested.Body.Attributes["error_message"]; ok {
+			validation.ErrorMessage = attr.Expr
+			validation.ErrorMessageText = expressionText(attr.Expr, src)
+			for _, traversal := range attr.Expr.Variables() {
+				validation.ErrorMessageReferences = append(validation.ErrorMessageReferences, traversalString(traversal))
+			}
+		}
+		variable.Validations = append(variable.Validations, validation)
+	}
+
+	return variable, diags
+}
+
+// boolAttribute evaluates a literal bool attribute, recording a diagnostic
+// when it is not one.
+func boolAttribute(attr *hclsyntax.Attribute, diags *hcl.Diagnostics) bool {
+	value, valDiags := attr.Expr.Value(nil)
+	*diags = append(*diags, valDiags...)
+	if valDiags.HasErrors() {
+		return false
+	}
+	value, err := convert.Convert(value, cty.Bool)
+	if err != nil || value.IsNull() || !value.IsKnown() {
+		*diags = append(*diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid argument value",
+			Detail:   fmt.Sprintf("The %s argument must be true or false.", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+		return false
+	}
+	return value.True()
+}
+
+// Assign converts a sample value the way Terraform does for a root module
+// input: null handling, optional attribute defaults and type conversion.
+func (v VariableProof) Assign(sample cty.Value) (cty.Value, error) {
+	value := sample
+	if value.IsNull() && !v.Nullable {
+		if !v.HasDefault {
+			return cty.NilVal, fmt.Errorf("null is not allowed because the variable is not nullable")
+		}
+		value = v.Default
+	}
+
+	if v.Defaults != nil {
+		value = v.Defaults.Apply(value)
+	}
+
+	value, err := convert.Convert(value, v.Type)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("rejected by type constraint: %s", err)
+	}
+	return value, nil
+}
+
+// Evaluate assigns a sample value to the variable and checks each validation
+// condition against it. It reports whether the value was accepted and, if
+// not, why.
+func (v VariableProof) Evaluate(sample cty.Value) (bool, string) {
+	value, err := v.Assign(sample)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(map[string]cty.Value{v.Name: value}),
+		},
+		Functions: ExpressionFunctions(),
+	}
+
+	for i, validation := range v.Validations {
+		if validation.Condition == nil {
+			continue
+		}
+
+		result, diags := validation.Condition.Value(ctx)
+		if diags.HasErrors() {
+			return false, fmt.Sprintf("validation %d could not be evaluated: %s", i+1, diags.Error())
+		}
+		result, err := convert.Convert(result, cty.Bool)
+		if err != nil || result.IsNull() || !result.IsKnown() {
+			return false, fmt.Sprintf("validation %d condition did not produce true or false", i+1)
+		}
+		if result.True() {
+			continue
+		}
+
+		message := validation.ErrorMessageText
+		if validation.ErrorMessage != nil {
+			if msg, msgDiags := validation.ErrorMessage.Value(ctx); !msgDiags.HasErrors() && msg.Type() == cty.String && msg.IsKnown() && !msg.IsNull() {
+				message = msg.AsString()
+			}
+		}
+		return false, fmt.Sprintf("validation %d failed: %s", i+1, message)
+	}
+
+	return true, ""
+}
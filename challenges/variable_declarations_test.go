@@ -0,0 +1,221 @@
+package challenges
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestParseVariableDeclarations(t *testing.T) {
+	cases := map[string]struct {
+		src      string
+		expected string
+		err      string
+	}{
+		"untyped": {
+			src:      `variable "name" {}`,
+			expected: "name type=dynamic nullable",
+		},
+		"typed with a default": {
+			src:      "variable \"replicas\" {\n  type    = number\n  default = \"3\"\n}\n",
+			expected: "replicas type=number default=3 nullable",
+		},
+		"sensitive and not nullable": {
+			src:      "variable \"api_token\" {\n  type      = string\n  sensitive = true\n  nullable  = false\n}\n",
+			expected: "api_token type=string sensitive",
+		},
+		"optional attribute default": {
+			src:      "variable \"settings\" {\n  type    = object({ name = string, replicas = optional(number, 1) })\n  default = { name = \"app\" }\n}\n",
+			expected: "settings type=object({ name = string, replicas = optional(number, 1) }) default={\"name\":\"app\",\"replicas\":1} nullable",
+		},
+		"validation": {
+			src: `variable "environment" {
+  type = string
+
+  validation {
+    condition     = contains(["dev", "prod"], var.environment)
+    error_message = "Environment ${var.environment} is not dev or prod."
+  }
+}`,
+			expected: `environment type=string nullable validation[contains(["dev", "prod"], var.environment) | var.environment | ` +
+				`Environment ${var.environment} is not dev or prod. | var.environment]`,
+		},
+		"other blocks": {
+			src:      "locals {\n  a = 1\n}\n\nvariable \"b\" {}\n\noutput \"c\" {\n  value = 1\n}\n",
+			expected: "b type=dynamic nullable",
+		},
+		"several variables": {
+			src:      "variable \"a\" {}\nvariable \"b\" {\n  sensitive = \"true\"\n}\n",
+			expected: "a type=dynamic nullable b type=dynamic nullable sensitive",
+		},
+		"no variables": {
+			src:      "",
+			expected: "",
+		},
+		"malformed hcl": {
+			src: `variable "a" {`,
+			err: "Unclosed configuration block",
+		},
+		"unknown type": {
+			src: "variable \"a\" {\n  type = strng\n}\n",
+			err: "Invalid type specification",
+		},
+		"default of the wrong type": {
+			src: "variable \"a\" {\n  type    = number\n  default = \"three\"\n}\n",
+			err: "Invalid default value for variable",
+		},
+		"sensitive not a bool": {
+			src: "variable \"a\" {\n  sensitive = \"yes\"\n}\n",
+			err: "The sensitive argument must be true or false.",
+		},
+		"nullable referencing a variable": {
+			src: "variable \"a\" {\n  nullable = var.b\n}\n",
+			err: "Variables not allowed",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			variables, diags := ParseVariableDeclarations("variables.tf", []byte(tc.src))
+			if tc.err != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Error(), tc.err) {
+					t.Fatalf("\n\nexpected error:\n\n%q\n\ngot:\n\n%q\n\n", tc.err, diags.Error())
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("error parsing variables: %s", diags.Error())
+			}
+
+			parts := []string{}
+			for _, variable := range variables {
+				part := variable.Name + " type=" + variable.TypeExpr
+				if variable.TypeExpr == "" {
+					part = variable.Name + " type=dynamic"
+				}
+				if variable.HasDefault {
+					encoded, err := ctyjson.SimpleJSONValue{Value: variable.Default}.MarshalJSON()
+					if err != nil {
+						t.Fatalf("error encoding default: %s", err)
+					}
+					part += " default=" + string(encoded)
+				}
+				if variable.Nullable {
+					part += " nullable"
+				}
+				if variable.Sensitive {
+					part += " sensitive"
+				}
+				for _, validation := range variable.Validations {
+					part += fmt.Sprintf(" validation[%s | %s | %s | %s]", validation.ConditionText, strings.Join(validation.ConditionReferences, " "),
+						validation.ErrorMessageText, strings.Join(validation.ErrorMessageReferences, " "))
+				}
+				parts = append(parts, part)
+			}
+			if actual := strings.Join(parts, " "); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestVariableEvaluate(t *testing.T) {
+	const declarations = `
+variable "environment" {
+  type = string
+
+  validation {
+    condition     = contains(["dev", "staging", "prod"], var.environment)
+    error_message = "Environment ${var.environment} is not dev, staging or prod."
+  }
+}
+
+variable "api_token" {
+  type      = string
+  sensitive = true
+  nullable  = false
+
+  validation {
+    condition     = length(var.api_token) >= 16
+    error_message = "The API token must be at least 16 characters long."
+  }
+}
+
+variable "port" {
+  type    = number
+  default = 8080
+
+  validation {
+    condition     = var.port > 0
+    error_message = "Port must be positive."
+  }
+
+  validation {
+    condition     = var.port < 65536
+    error_message = "Port must be below 65536."
+  }
+}
+
+variable "settings" {
+  type     = object({ name = string, replicas = optional(number, 1) })
+  nullable = false
+  default  = { name = "default" }
+}
+
+variable "broken" {
+  validation {
+    condition     = var.broken.missing == 1
+    error_message = "Always evaluated against a string."
+  }
+}
+
+variable "not_bool" {
+  validation {
+    condition     = "maybe"
+    error_message = "The condition is not a bool."
+  }
+}
+`
+	variables, diags := ParseVariableDeclarations("variables.tf", []byte(declarations))
+	if diags.HasErrors() {
+		t.Fatalf("error parsing variables: %s", diags.Error())
+	}
+	byName := map[string]VariableProof{}
+	for _, variable := range variables {
+		byName[variable.Name] = variable
+	}
+
+	cases := map[string]struct {
+		variable string
+		sample   string
+		reason   string
+	}{
+		"valid string":           {variable: "environment", sample: `"prod"`},
+		"failed validation":      {variable: "environment", sample: `"production"`, reason: "validation 1 failed: Environment production is not dev, staging or prod."},
+		"number for a string":    {variable: "environment", sample: `3`, reason: "validation 1 failed: Environment 3 is not dev, staging or prod."},
+		"list for a string":      {variable: "environment", sample: `["dev"]`, reason: "rejected by type constraint: string required"},
+		"long token":             {variable: "api_token", sample: `"abcdefghijklmnop"`},
+		"short token":            {variable: "api_token", sample: `"short"`, reason: "validation 1 failed: The API token must be at least 16 characters long."},
+		"null token":             {variable: "api_token", sample: `null`, reason: "null is not allowed because the variable is not nullable"},
+		"second validation":      {variable: "port", sample: `70000`, reason: "validation 2 failed: Port must be below 65536."},
+		"numeric string port":    {variable: "port", sample: `"443"`},
+		"null uses no default":   {variable: "port", sample: `null`, reason: "validation 1 could not be evaluated: variables.tf:27,21-33: Operation failed; Error during operation: argument must not be null."},
+		"optional attribute":     {variable: "settings", sample: `{ name = "app" }`},
+		"missing attribute":      {variable: "settings", sample: `{ replicas = 2 }`, reason: `rejected by type constraint: attribute "name" is required`},
+		"null uses the default":  {variable: "settings", sample: `null`},
+		"condition type error":   {variable: "broken", sample: `"text"`, reason: "validation 1 could not be evaluated: variables.tf:45,31-39: Unsupported attribute; Can't access attributes on a primitive-typed value (string)."},
+		"condition not a bool":   {variable: "not_bool", sample: `"x"`, reason: "validation 1 condition did not produce true or false"},
+		"no validation to apply": {variable: "settings", sample: `{ name = "app", replicas = 3 }`},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, reason := byName[tc.variable].Evaluate(mustParseValue(tc.sample))
+			if ok != (tc.reason == "") || reason != tc.reason {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q (accepted %t)\n\n", tc.reason, reason, ok)
+			}
+		})
+	}
+}
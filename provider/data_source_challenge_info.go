@@ -42,6 +42,23 @@ func dataSourceChallengeInfo() *schema.Resource {
 				Computed:    true,
 				Description: "Challenge category",
 			},
+			"version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Version of the challenge definition, bumped when requirements change",
+			},
+			"proof_kinds": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Proof kinds accepted by the challenge (manual, resource, data_source, module)",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"proof_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "proof_of_work keys read by the challenge's validator",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -61,6 +78,14 @@ func dataSourceChallengeInfoRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("points", challenge.Points)
 	d.Set("difficulty", challenge.Difficulty)
 	d.Set("category", challenge.Category)
+	d.Set("version", challenge.DefinitionVersion())
+
+	proofKinds := []string{}
+	for _, kind := range challenge.Validator.SupportedProofKinds() {
+		proofKinds = append(proofKinds, string(kind))
+	}
+	d.Set("proof_kinds", proofKinds)
+	d.Set("proof_keys", challenge.Validator.ExpectedProofKeys())
 	d.SetId(challengeID)
 
 	return diags
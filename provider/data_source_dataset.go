@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+func dataSourceDataset() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDatasetRead,
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return servers in this region",
+			},
+			"environment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return servers in this environment (prod, staging, dev)",
+			},
+			"size": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return servers of this size (small, medium, large)",
+			},
+			"tag": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return servers with this tag",
+			},
+			"servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Matching servers, in dataset order",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"environment": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cpu": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"ports": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
+			},
+			"server_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of matching servers",
+			},
+		},
+	}
+}
+
+func dataSourceDatasetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	filter := challenges.DatasetFilter{
+		Region:      d.Get("region").(string),
+		Environment: d.Get("environment").(string),
+		Size:        d.Get("size").(string),
+		Tag:         d.Get("tag").(string),
+	}
+
+	var serverList []interface{}
+	for _, server := range challenges.QueryDataset(filter) {
+		serverList = append(serverList, map[string]interface{}{
+			"name":        server.Name,
+			"region":      server.Region,
+			"size":        server.Size,
+			"environment": server.Environment,
+			"cpu":         server.CPU,
+			"tags":        server.Tags,
+			"ports":       server.Ports,
+		})
+	}
+
+	d.Set("servers", serverList)
+	d.Set("server_count", len(serverList))
+	d.SetId(fmt.Sprintf("dataset-%s-%s-%s-%s", filter.Region, filter.Environment, filter.Size, filter.Tag))
+
+	return diags
+}
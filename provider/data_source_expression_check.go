@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func dataSourceExpressionCheck() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceExpressionCheckRead,
+		Schema: map[string]*schema.Schema{
+			"challenge_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The challenge to check the expression against",
+			},
+			"expression": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "A self-contained Terraform expression, e.g. \"base64encode(sha256(\\\"text\\\"))\"",
+			},
+			"result": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Result of the expression; non-string results are JSON encoded",
+			},
+			"result_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Type of the result",
+			},
+			"functions_used": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Functions called by the expression, in order of appearance",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"passed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the expression solves the challenge",
+			},
+			"message": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Evaluation or validation result message",
+			},
+			"validation_details": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Detailed validation results",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceExpressionCheckRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	challengeID := d.Get("challenge_id").(string)
+	src := d.Get("expression").(string)
+
+	challenge, exists := challenges.Challenges[challengeID]
+	if !exists {
+		return diag.Errorf("Unknown challenge: %s", challengeID)
+	}
+
+	sum := sha256.Sum256([]byte(src))
+	d.SetId(fmt.Sprintf("expression-%s-%s", challengeID, hex.EncodeToString(sum[:8])))
+
+	// Evaluation errors are reported through message so the sandbox can be
+	// used to iterate on an expression without failing the plan.
+	expr, exprDiags := challenges.ParseExpression(src)
+	if exprDiags.HasErrors() {
+		d.Set("result", "")
+		d.Set("result_type", "")
+		d.Set("functions_used", []string{})
+		d.Set("passed", false)
+		d.Set("message", exprDiags.Error())
+		d.Set("validation_details", []string{})
+		return diags
+	}
+
+	result, resultType, err := renderValue(expr.Value)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	validation, err := challenge.ValidateProof(ctx, &challenges.ProofData{Expression: expr, Source: "expression"})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("result", result)
+	d.Set("result_type", resultType)
+	d.Set("functions_used", expr.Functions)
+	d.Set("passed", validation.Success)
+	d.Set("message", validation.Message)
+	d.Set("validation_details", validation.Details)
+
+	return diags
+}
+
+// renderValue returns a string result as is and any other value JSON encoded,
+// along with its type.
+func renderValue(value cty.Value) (string, string, error) {
+	if value.IsNull() {
+		return "null", value.Type().FriendlyName(), nil
+	}
+	if value.Type() == cty.String {
+		return value.AsString(), "string", nil
+	}
+
+	encoded, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode expression result: %w", err)
+	}
+	return string(encoded), value.Type().FriendlyName(), nil
+}
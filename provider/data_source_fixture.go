@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+func dataSourceFixture() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFixtureRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Name of the fixture file, e.g. players.csv",
+				ValidateFunc: validation.StringInSlice(challenges.FixtureNames(), false),
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Content of the fixture file",
+			},
+			"sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA256 checksum of the content",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Local path of a copy of the fixture, for functions that read files such as templatefile",
+			},
+		},
+	}
+}
+
+func dataSourceFixtureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	name := d.Get("name").(string)
+	content, ok := challenges.Fixture(name)
+	if !ok {
+		return diag.Errorf("Unknown fixture: %s", name)
+	}
+
+	path, err := writeFixture(name, content)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sum := sha256.Sum256(content)
+	d.SetId(fmt.Sprintf("fixture-%s", name))
+	d.Set("content", string(content))
+	d.Set("sha256", hex.EncodeToString(sum[:]))
+	d.Set("path", path)
+
+	return diags
+}
+
+// writeFixture copies a fixture to ~/.ctfchallenge/fixtures, or the system
+// temporary directory when the home directory is unavailable. The file is
+// only rewritten when its content differs.
+func writeFixture(name string, content []byte) (string, error) {
+	dir := filepath.Join(os.TempDir(), "ctfchallenge", "fixtures")
+	if home, err := os.UserHomeDir(); err == nil {
+		dir = filepath.Join(home, ".ctfchallenge", "fixtures")
+	}
+	path := filepath.Join(dir, name)
+
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return "", fmt.Errorf("writing fixture %s: %w", name, err)
+	}
+	return path, nil
+}
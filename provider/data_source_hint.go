@@ -49,7 +49,7 @@ func dataSourceHintRead(ctx context.Context, d *schema.ResourceData, m interface
 	}
 
 	hint := challenges.GetHint(challengeID, level)
-	cost := (level + 1) * 10 // Hints cost 10, 20, or 30 points
+	cost := challenges.HintCost(level)
 
 	d.Set("hint", hint)
 	d.Set("cost", cost)
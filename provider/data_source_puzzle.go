@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+func dataSourcePuzzle() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePuzzleRead,
+		Schema: map[string]*schema.Schema{
+			"puzzle_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      challenges.DefaultPuzzleType,
+				Description:  "Puzzle to generate",
+				ValidateFunc: validation.StringInSlice(challenges.PuzzleTypes(), false),
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Player whose puzzle to generate. Defaults to the provider's player_name",
+			},
+			"chain": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Puzzle chain whose stage to generate for the provider's player: " + strings.Join(challenges.PuzzleChainIDs(), ", "),
+				ValidateFunc:  validation.StringInSlice(challenges.PuzzleChainIDs(), false),
+				RequiredWith:  []string{"stage"},
+				ConflictsWith: []string{"player_name"},
+			},
+			"stage": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Stage of the chain to generate, numbered from 1",
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"chain"},
+			},
+			"stage_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				Description:  "secret_output of the previous stage of the chain, which unlocks the stage",
+				RequiredWith: []string{"chain"},
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Puzzle name",
+			},
+			"difficulty": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Difficulty of the puzzle",
+			},
+			"prompt": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The puzzle to solve, including the data needed to solve it",
+			},
+			"parameters": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The puzzle's data, for use in expressions",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"input_schema": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The inputs a ctfchallenge_puzzle_box playing this puzzle reads",
+			},
+			"seed": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Seed the puzzle was generated from",
+			},
+		},
+	}
+}
+
+func dataSourcePuzzleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	config := providerConfig(m)
+	puzzleType := d.Get("puzzle_type").(string)
+	player := d.Get("player_name").(string)
+	if player == "" {
+		player = config.PlayerName
+	}
+
+	// Chain stages are generated for the provider's player, once unlocked
+	stage, err := puzzleBoxStage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	lock, err := stageLock(m, stage)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if lock != "" {
+		return diag.Errorf("%s", lock)
+	}
+
+	salt := config.EventSalt
+	if stage != nil {
+		salt = stage.chain.StageSalt(stage.stage, player, salt)
+	}
+	puzzle, seed, err := generatePuzzle(puzzleType, player, salt)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("player_name", player)
+	d.Set("name", puzzle.Name())
+	for key, value := range puzzleDetails(puzzle, seed) {
+		d.Set(key, value)
+	}
+	d.SetId(fmt.Sprintf("%s-%s", puzzleType, seed))
+
+	return diags
+}
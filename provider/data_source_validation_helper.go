@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceValidationHelper() *schema.Resource {
@@ -15,13 +18,14 @@ func dataSourceValidationHelper() *schema.Resource {
 			"resource_id": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "ID of the resource to validate",
+				Description: "ID of the ctfchallenge_validated_resource to validate",
 			},
 			"validation_type": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "basic",
-				Description: "Type of validation (basic, strict, comprehensive)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "basic",
+				Description:  "Type of validation (basic, strict, comprehensive)",
+				ValidateFunc: validation.StringInSlice([]string{"basic", "strict", "comprehensive"}, false),
 			},
 			// Computed attributes for validation
 			"is_valid": {
@@ -49,33 +53,197 @@ func dataSourceValidationHelper() *schema.Resource {
 	}
 }
 
+// validationReport is the helper's assessment of a recorded validated
+// resource.
+type validationReport struct {
+	score           int
+	valid           bool
+	recommendations []string
+}
+
+// messageExpectations are phrases that tell the reader what a valid value
+// looks like.
+var messageExpectations = []string{"must", "should", "expected", "expect", "between", "at least", "at most", "one of", "only"}
+
+// recordedRule is a rule of a validated resource as recorded in its
+// progress record.
+type recordedRule struct {
+	index     int
+	ruleType  string
+	attribute string
+	message   string
+	result    string
+}
+
+// String identifies the rule in recommendations, as in the resource's own
+// diagnostics.
+func (r recordedRule) String() string {
+	return fmt.Sprintf("rule[%d] (%s)", r.index, r.ruleType)
+}
+
+// recordedRules reads the rules recorded for a validated resource.
+func recordedRules(record *progressRecord) []recordedRule {
+	n, _ := strconv.Atoi(record.Attributes["rules"])
+	rules := make([]recordedRule, 0, n)
+	for i := 0; i < n; i++ {
+		prefix := fmt.Sprintf("rule.%d.", i)
+		rules = append(rules, recordedRule{
+			index:     i,
+			ruleType:  record.Attributes[prefix+"type"],
+			attribute: record.Attributes[prefix+"attribute"],
+			message:   record.Attributes[prefix+"message"],
+			result:    record.Attributes[prefix+"result"],
+		})
+	}
+	return rules
+}
+
+// minQualityScore is the quality_score strict validation expects, the
+// threshold used in the postcondition examples.
+const minQualityScore = 50
+
+// assessValidatedResource scores a validated resource out of 100 from the
+// rules the provider evaluated: half its quality_score, 10 points for each
+// rule type that passed, up to 30, and up to 20 for the least descriptive
+// rule message.
+func assessValidatedResource(record *progressRecord, validationType string) validationReport {
+	report := validationReport{recommendations: []string{}}
+	recommend := func(format string, args ...interface{}) {
+		report.recommendations = append(report.recommendations, fmt.Sprintf(format, args...))
+	}
+
+	rules := recordedRules(record)
+	qualityScore, _ := strconv.Atoi(record.Attributes["quality_score"])
+
+	// Outcome
+	if !record.Validated {
+		recommend("Fix the values so that required_value is not blank and every rule passes")
+	}
+	if len(rules) == 0 {
+		recommend("Add rule blocks, e.g. a length or regex rule on required_value, so the provider checks the values")
+	}
+
+	// Rules
+	passedTypes := make(map[string]bool)
+	skipped := 0
+	for _, rule := range rules {
+		switch rule.result {
+		case rulePassed:
+			passedTypes[rule.ruleType] = true
+		case ruleSkipped:
+			skipped++
+			recommend("%s was skipped because optional_value is empty; set optional_value or check another attribute", rule)
+		default:
+			recommend("%s fails on %s", rule, rule.attribute)
+		}
+	}
+	report.score += 10 * min(len(passedTypes), 3)
+	if len(rules) > 0 && len(passedTypes) < 3 {
+		var unused []string
+		for _, ruleType := range validationRuleTypes {
+			if !passedTypes[ruleType] {
+				unused = append(unused, ruleType)
+			}
+		}
+		recommend("Rules of %d type(s) passed; add passing rules of other types, such as %s", len(passedTypes), strings.Join(unused, ", "))
+	}
+
+	// Quality
+	report.score += qualityScore / 2
+	if qualityScore < 100 {
+		recommend("quality_score is %d; see the quality score rubric of ctfchallenge_validated_resource to reach 100", qualityScore)
+	}
+
+	// Messages
+	messageScore := 0
+	for i, rule := range rules {
+		score := scoreErrorMessage(rule.message)
+		if i == 0 || score < messageScore {
+			messageScore = score
+		}
+		switch {
+		case rule.message == "":
+			recommend("Add a message to %s explaining what a valid value looks like", rule)
+		case score < 20:
+			recommend("Make the message of %s more descriptive: state what is expected, e.g. \"required_value must be at least 8 characters\"", rule)
+		}
+	}
+	report.score += messageScore
+
+	basic := record.Validated && len(passedTypes) > 0
+	strict := basic && len(passedTypes) >= 2 && messageScore > 0 && qualityScore >= minQualityScore
+	switch validationType {
+	case "basic":
+		report.valid = basic
+	case "strict":
+		report.valid = strict
+	case "comprehensive":
+		report.valid = strict && skipped == 0 && len(passedTypes) >= 3 && messageScore == 20 && qualityScore == 100
+	}
+
+	if len(report.recommendations) == 0 {
+		recommend("No recommendations: every rule passes, they cover three types, quality_score is 100 and every message is descriptive")
+	}
+	return report
+}
+
+// scoreErrorMessage scores an error message out of 20: 5 for having one, 5
+// for being at least a short sentence and 10 for stating what is expected.
+func scoreErrorMessage(message string) int {
+	if strings.TrimSpace(message) == "" {
+		return 0
+	}
+
+	score := 5
+	if len(message) >= 20 && len(strings.Fields(message)) >= 4 {
+		score += 5
+	}
+
+	lower := strings.ToLower(message)
+	for _, phrase := range messageExpectations {
+		if strings.Contains(lower, phrase) {
+			score += 10
+			break
+		}
+	}
+	return score
+}
+
 func dataSourceValidationHelperRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	resourceID := d.Get("resource_id").(string)
 	validationType := d.Get("validation_type").(string)
 
-	// Simulate validation
-	score := 85
-	isValid := true
-	status := "passed"
+	if providerConfig(m).Progress == nil {
+		return diag.Errorf("Progress tracking is disabled: ctfchallenge_validation_helper reads validated resources from the provider's progress_file")
+	}
 
-	if validationType == "strict" {
-		score = 90
-	} else if validationType == "comprehensive" {
-		score = 95
+	record, err := importRecord(m, resourceID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if record == nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Unknown resource",
+			Detail:   fmt.Sprintf("No ctfchallenge_validated_resource with ID %q has been recorded. Pass the id of a ctfchallenge_validated_resource managed with the same progress_file.", resourceID),
+		}}
+	}
+	if record.ResourceType != "ctfchallenge_validated_resource" {
+		return diag.Errorf("%s is a %s, not a ctfchallenge_validated_resource", resourceID, record.ResourceType)
 	}
 
-	recommendations := []interface{}{
-		"Consider adding more validation rules",
-		"Document your validation logic",
-		"Add error recovery mechanisms",
+	report := assessValidatedResource(record, validationType)
+	status := "failed"
+	if report.valid {
+		status = "passed"
 	}
 
-	d.Set("is_valid", isValid)
-	d.Set("validation_score", score)
+	d.Set("is_valid", report.valid)
+	d.Set("validation_score", report.score)
 	d.Set("status", status)
-	d.Set("recommendations", recommendations)
+	d.Set("recommendations", report.recommendations)
 	d.SetId(fmt.Sprintf("validation-%s", resourceID))
 
 	return diags
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceValidationHelperSchema(t *testing.T) {
+	if err := dataSourceValidationHelper().InternalValidate(nil, false); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDataSourceValidationHelperRead(t *testing.T) {
+	lengthRule := map[string]interface{}{"type": "length", "min_length": 3, "message": "required_value must be at least 3 characters"}
+	cidrRule := map[string]interface{}{"type": "cidr", "message": "required_value must be a network address"}
+	regexRule := map[string]interface{}{"type": "regex", "pattern": `^10\.`, "message": "required_value must be in 10.0.0.0/8"}
+	terseRule := map[string]interface{}{"type": "cidr", "message": "bad"}
+	semverRule := map[string]interface{}{"type": "semver", "attribute": "optional_value", "message": "optional_value must be a version"}
+
+	cases := map[string]struct {
+		raw            map[string]interface{}
+		validationType string
+		valid          bool
+		score          int
+		recommendation string
+	}{
+		"no rules": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "abcdefgh"},
+			validationType: "basic",
+			score:          25,
+			recommendation: "Add rule blocks",
+		},
+		"failed validation": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.1/8", "rule": []interface{}{lengthRule, cidrRule}},
+			validationType: "basic",
+			score:          65,
+			recommendation: "rule[1] (cidr) fails on required_value",
+		},
+		"basic": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "abcdefgh", "rule": []interface{}{lengthRule}},
+			validationType: "basic",
+			valid:          true,
+			score:          65,
+			recommendation: "Rules of 1 type(s) passed; add passing rules of other types, such as regex",
+		},
+		"strict with one rule type": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "abcdefgh", "rule": []interface{}{lengthRule}},
+			validationType: "strict",
+			score:          65,
+		},
+		"strict": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, cidrRule}},
+			validationType: "strict",
+			valid:          true,
+			score:          85,
+		},
+		"strict with a terse message": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, terseRule}},
+			validationType: "strict",
+			valid:          true,
+			score:          70,
+			recommendation: "Make the message of rule[1] (cidr) more descriptive",
+		},
+		"comprehensive with a terse message": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, terseRule, regexRule}},
+			validationType: "comprehensive",
+			score:          85,
+		},
+		"comprehensive with a skipped rule": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, cidrRule, regexRule, semverRule}},
+			validationType: "comprehensive",
+			score:          100,
+			recommendation: "rule[3] (semver) was skipped because optional_value is empty",
+		},
+		"comprehensive": {
+			raw:            map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, cidrRule, regexRule}},
+			validationType: "comprehensive",
+			valid:          true,
+			score:          100,
+			recommendation: "No recommendations",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := testProviderConfig(t)
+			resource := schema.TestResourceDataRaw(t, resourceValidatedResource().Schema, tc.raw)
+			if diags := recordProgress(meta, "validated-a", validatedResourceRecord(resource)); diags.HasError() {
+				t.Fatalf("error recording progress: %v", diags)
+			}
+
+			d := schema.TestResourceDataRaw(t, dataSourceValidationHelper().Schema, map[string]interface{}{
+				"resource_id":     "validated-a",
+				"validation_type": tc.validationType,
+			})
+			if diags := dataSourceValidationHelperRead(context.Background(), d, meta); diags.HasError() {
+				t.Fatalf("error reading validation helper: %v", diags)
+			}
+
+			status := "failed"
+			if tc.valid {
+				status = "passed"
+			}
+			if d.Get("is_valid").(bool) != tc.valid || d.Get("validation_score").(int) != tc.score || d.Get("status").(string) != status {
+				t.Fatalf("\n\nexpected:\n\nvalid=%t score=%d status=%s\n\ngot:\n\nvalid=%t score=%d status=%s\n\n",
+					tc.valid, tc.score, status, d.Get("is_valid"), d.Get("validation_score"), d.Get("status"))
+			}
+			if d.Id() != "validation-validated-a" {
+				t.Fatalf("expected ID validation-validated-a, got %s", d.Id())
+			}
+
+			recommendations := d.Get("recommendations").([]interface{})
+			if len(recommendations) == 0 {
+				t.Fatal("expected recommendations")
+			}
+			if tc.recommendation == "" {
+				return
+			}
+			for _, recommendation := range recommendations {
+				if strings.Contains(recommendation.(string), tc.recommendation) {
+					return
+				}
+			}
+			t.Fatalf("\n\nexpected a recommendation containing:\n\n%q\n\ngot:\n\n%q\n\n", tc.recommendation, recommendations)
+		})
+	}
+}
+
+func TestDataSourceValidationHelperReadErrors(t *testing.T) {
+	cases := map[string]struct {
+		id       string
+		player   string
+		disabled bool
+		expected string
+	}{
+		"unknown resource": {
+			id:       "validated-missing",
+			expected: "Unknown resource",
+		},
+		"another resource type": {
+			id:       "flag-basics",
+			expected: "flag-basics is a ctfchallenge_flag_validator, not a ctfchallenge_validated_resource",
+		},
+		"another player's resource": {
+			id:       "validated-a",
+			player:   "bob",
+			expected: `progress record validated-a belongs to player "alice", not "bob"`,
+		},
+		"progress disabled": {
+			id:       "validated-a",
+			disabled: true,
+			expected: "Progress tracking is disabled",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := testProviderConfig(t)
+			resource := schema.TestResourceDataRaw(t, resourceValidatedResource().Schema, map[string]interface{}{"name": "a", "required_value": "abcdefgh"})
+			recordProgress(meta, "validated-a", validatedResourceRecord(resource))
+			recordProgress(meta, "flag-basics", &progressRecord{ResourceType: "ctfchallenge_flag_validator", ChallengeID: "terraform_basics"})
+			if tc.player != "" {
+				meta.PlayerName = tc.player
+			}
+			if tc.disabled {
+				meta.Progress = nil
+			}
+
+			d := schema.TestResourceDataRaw(t, dataSourceValidationHelper().Schema, map[string]interface{}{"resource_id": tc.id})
+			diags := dataSourceValidationHelperRead(context.Background(), d, meta)
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tc.expected) {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%v\n\n", tc.expected, diags)
+			}
+		})
+	}
+}
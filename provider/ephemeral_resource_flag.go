@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &flagEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &flagEphemeralResource{}
+)
+
+// flagEphemeralResource reveals the flag of a challenge the player has
+// already validated. The flag exists only for the duration of a Terraform
+// run and is never written to state or plan files.
+type flagEphemeralResource struct {
+	config *ProviderConfig
+}
+
+type flagEphemeralResourceModel struct {
+	ChallengeID types.String `tfsdk:"challenge_id"`
+	ValidatorID types.String `tfsdk:"validator_id"`
+	Flag        types.String `tfsdk:"flag"`
+	FlagHash    types.String `tfsdk:"flag_hash"`
+	Points      types.Int64  `tfsdk:"points"`
+	Timestamp   types.String `tfsdk:"timestamp"`
+}
+
+// NewFlagEphemeralResource returns the ctfchallenge_flag ephemeral resource.
+func NewFlagEphemeralResource() ephemeral.EphemeralResource {
+	return &flagEphemeralResource{}
+}
+
+func (r *flagEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_flag"
+}
+
+func (r *flagEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reveals the flag of a validated challenge for the duration of a run without storing it in state",
+		Attributes: map[string]schema.Attribute{
+			"challenge_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the validated challenge",
+			},
+			"validator_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "ID of the ctfchallenge_flag_validator that validated the challenge. Defaults to the player's latest validated completion",
+			},
+			"flag": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The flag revealed for the challenge",
+			},
+			"flag_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 hash of the flag, matching the flag_hash of the validator",
+			},
+			"points": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Points awarded for the challenge",
+			},
+			"timestamp": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp when the challenge was completed",
+			},
+		},
+	}
+}
+
+func (r *flagEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	config, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf("Expected *ProviderConfig, got %T", req.ProviderData),
+		)
+		return
+	}
+	r.config = config
+}
+
+func (r *flagEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data flagEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	challengeID := data.ChallengeID.ValueString()
+	challenge, exists := challenges.Challenges[challengeID]
+	if !exists {
+		resp.Diagnostics.AddAttributeError(path.Root("challenge_id"), "Unknown challenge", fmt.Sprintf("Unknown challenge: %s", challengeID))
+		return
+	}
+
+	if challenge.Flag == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("challenge_id"), "No flag available", fmt.Sprintf("Challenge '%s' does not award a flag", challengeID))
+		return
+	}
+
+	config := providerConfig(r.config)
+	if config.Progress == nil {
+		resp.Diagnostics.AddError(
+			"Progress tracking disabled",
+			"The flag is revealed from recorded progress, but no progress_file is configured for the provider.",
+		)
+		return
+	}
+
+	validatorID := data.ValidatorID.ValueString()
+	var record *progressRecord
+	var err error
+	if validatorID == "" {
+		validatorID, record, err = config.Progress.FindLatest("ctfchallenge_flag_validator", config.PlayerName, challengeID)
+	} else {
+		record, err = importRecord(config, validatorID)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read progress", err.Error())
+		return
+	}
+
+	if record == nil || record.ResourceType != "ctfchallenge_flag_validator" || record.ChallengeID != challengeID || !record.Validated {
+		resp.Diagnostics.AddError(
+			"Challenge not validated",
+			fmt.Sprintf("No validated completion of '%s' was found for player %q. Apply a ctfchallenge_flag_validator for this challenge first.",
+				challengeID, config.PlayerName),
+		)
+		return
+	}
+
+	if record.ChallengeVersion != challenge.DefinitionVersion() {
+		resp.Diagnostics.AddError(
+			"Challenge requirements changed",
+			fmt.Sprintf("%s was validated against version %d of '%s', but the current version is %d. Apply the validator again to re-validate your proof.",
+				validatorID, record.ChallengeVersion, challengeID, challenge.DefinitionVersion()),
+		)
+		return
+	}
+
+	data.ValidatorID = types.StringValue(validatorID)
+	data.Flag = types.StringValue(challenge.Flag)
+	data.FlagHash = types.StringValue(hashFlag(challenge.Flag))
+	data.Points = types.Int64Value(int64(record.Points))
+	data.Timestamp = types.StringValue(record.Timestamp)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
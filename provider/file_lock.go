@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockFile takes an exclusive lock on path+".lock", blocking until other
+// processes release it, and returns a function releasing it. Terraform runs
+// a provider process per configuration, so files shared between
+// configurations, such as the progress and cloud files, must be locked
+// around every load, modify and save.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating directory for %s: %w", path, err)
+	}
+
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file for %s: %w", path, err)
+	}
+	if err := lockExclusive(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// writeFileAtomic replaces the file at path with data. It writes a
+// temporary file in the same directory and renames it over path, so that
+// readers see either the old or the new content and a failed write never
+// truncates the file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// frameworkProvider serves the parts of the provider that SDKv2 cannot
+// express, such as provider-defined functions and ephemeral resources. It is muxed with Provider()
+// in main.go, so its schema must match the SDKv2 provider schema exactly.
+type frameworkProvider struct{}
+
+var (
+	_ fwprovider.ProviderWithFunctions          = &frameworkProvider{}
+	_ fwprovider.ProviderWithEphemeralResources = &frameworkProvider{}
+)
+
+// frameworkProviderModel maps the provider configuration block.
+type frameworkProviderModel struct {
+	PlayerName   types.String `tfsdk:"player_name"`
+	APIEndpoint  types.String `tfsdk:"api_endpoint"`
+	ProgressFile types.String `tfsdk:"progress_file"`
+	InstanceName types.String `tfsdk:"instance_name"`
+	CloudFile    types.String `tfsdk:"cloud_file"`
+	EventSalt    types.String `tfsdk:"event_salt"`
+}
+
+// NewFrameworkProvider returns the terraform-plugin-framework half of the provider.
+func NewFrameworkProvider() fwprovider.Provider {
+	return &frameworkProvider{}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req fwprovider.MetadataRequest, resp *fwprovider.MetadataResponse) {
+	resp.TypeName = "ctfchallenge"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req fwprovider.SchemaRequest, resp *fwprovider.SchemaResponse) {
+	sdkSchema := Provider().Schema
+
+	resp.Schema = fwschema.Schema{
+		Attributes: map[string]fwschema.Attribute{
+			"player_name": fwschema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["player_name"].Description,
+			},
+			"api_endpoint": fwschema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["api_endpoint"].Description,
+			},
+			"progress_file": fwschema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["progress_file"].Description,
+			},
+			"instance_name": fwschema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["instance_name"].Description,
+			},
+			"cloud_file": fwschema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["cloud_file"].Description,
+			},
+			"event_salt": fwschema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: sdkSchema["event_salt"].Description,
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req fwprovider.ConfigureRequest, resp *fwprovider.ConfigureResponse) {
+	var data frameworkProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Apply the same environment defaults as the SDKv2 schema
+	config := newProviderConfig(
+		stringOrEnv(data.PlayerName, "TF_CTF_PLAYER", "anonymous"),
+		stringOrEnv(data.APIEndpoint, "TF_CTF_API", ""),
+		stringOrEnv(data.ProgressFile, "TF_CTF_PROGRESS_FILE", ""),
+		stringOrEnv(data.InstanceName, "TF_CTF_INSTANCE", ""),
+		stringOrEnv(data.CloudFile, "TF_CTF_CLOUD_FILE", ""),
+		stringOrEnv(data.EventSalt, "TF_CTF_EVENT_SALT", ""),
+	)
+
+	resp.EphemeralResourceData = config
+}
+
+// stringOrEnv returns the configured value, falling back to the environment
+// variable and then to def.
+func stringOrEnv(value types.String, env, def string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return def
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewFlagEphemeralResource,
+	}
+}
+
+func (p *frameworkProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewXORFunction,
+		NewFlagFormatFunction,
+		NewChallengePointsFunction,
+		NewCheckHintCostFunction,
+	}
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+var _ function.Function = &challengePointsFunction{}
+
+type challengePointsFunction struct{}
+
+// NewChallengePointsFunction returns the provider::ctfchallenge::challenge_points function.
+func NewChallengePointsFunction() function.Function {
+	return &challengePointsFunction{}
+}
+
+func (f *challengePointsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "challenge_points"
+}
+
+func (f *challengePointsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Points awarded for a challenge",
+		Description: "Returns the points awarded for completing the challenge with the given ID. Fails for unknown challenge IDs.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "challenge_id",
+				Description: "ID of the challenge",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *challengePointsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var challengeID string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &challengeID))
+	if resp.Error != nil {
+		return
+	}
+
+	challenge, exists := challenges.Challenges[challengeID]
+	if !exists {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unknown challenge: %s", challengeID))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, int64(challenge.Points)))
+}
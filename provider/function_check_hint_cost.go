@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+var _ function.Function = &checkHintCostFunction{}
+
+type checkHintCostFunction struct{}
+
+// NewCheckHintCostFunction returns the provider::ctfchallenge::check_hint_cost function.
+func NewCheckHintCostFunction() function.Function {
+	return &checkHintCostFunction{}
+}
+
+func (f *checkHintCostFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "check_hint_cost"
+}
+
+func (f *checkHintCostFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Point penalty of a hint",
+		Description: "Returns the point penalty for requesting the hint at the given level for a challenge, without requesting the hint itself.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "challenge_id",
+				Description: "ID of the challenge",
+			},
+			function.Int64Parameter{
+				Name:        "level",
+				Description: "Hint level (0-2, higher levels are more revealing)",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *checkHintCostFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var challengeID string
+	var level int64
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &challengeID, &level))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, exists := challenges.Challenges[challengeID]; !exists {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unknown challenge: %s", challengeID))
+		return
+	}
+	if level < 0 || level > challenges.MaxHintLevel {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Hint level must be between 0 and %d, got %d", challenges.MaxHintLevel, level))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, int64(challenges.HintCost(int(level)))))
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckHintCostFunction(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		level     int64
+		expected  int64
+		err       string
+	}{
+		"level 0": {
+			challenge: "expression_expert",
+			level:     0,
+			expected:  10,
+		},
+		"level 2": {
+			challenge: "expression_expert",
+			level:     2,
+			expected:  30,
+		},
+		"negative level": {
+			challenge: "expression_expert",
+			level:     -1,
+			err:       "Hint level must be between 0 and 2, got -1",
+		},
+		"level past the last hint": {
+			challenge: "expression_expert",
+			level:     3,
+			err:       "Hint level must be between 0 and 2, got 3",
+		},
+		"unknown challenge": {
+			challenge: "no_such_challenge",
+			level:     0,
+			err:       "Unknown challenge: no_such_challenge",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tc.challenge), types.Int64Value(tc.level)}),
+			}
+			resp := &function.RunResponse{Result: function.NewResultData(types.Int64Unknown())}
+			NewCheckHintCostFunction().Run(context.Background(), req, resp)
+
+			if tc.err != "" {
+				if resp.Error == nil || resp.Error.Text != tc.err {
+					t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%v\n\n", tc.err, resp.Error)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+			if actual := resp.Result.Value(); !actual.Equal(types.Int64Value(tc.expected)) {
+				t.Fatalf("\n\nexpected:\n\n%d\n\ngot:\n\n%s\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
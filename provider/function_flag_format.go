@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+var _ function.Function = &flagFormatFunction{}
+
+type flagFormatFunction struct{}
+
+// NewFlagFormatFunction returns the provider::ctfchallenge::flag_format function.
+func NewFlagFormatFunction() function.Function {
+	return &flagFormatFunction{}
+}
+
+func (f *flagFormatFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "flag_format"
+}
+
+func (f *flagFormatFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Format a value as a CTF flag",
+		Description: "Wraps the given value in the flag{...} format. A value that is already wrapped is returned unchanged. Fails if the value is empty or contains braces or whitespace.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "value",
+				Description: "Flag body, with or without the flag{...} wrapper",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *flagFormatFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	flag, err := challenges.FormatFlag(value)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, flag))
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+var _ function.Function = &xorFunction{}
+
+type xorFunction struct{}
+
+// NewXORFunction returns the provider::ctfchallenge::xor function.
+func NewXORFunction() function.Function {
+	return &xorFunction{}
+}
+
+func (f *xorFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "xor"
+}
+
+func (f *xorFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Exclusive or of a list of integers",
+		Description: "Returns the bitwise exclusive or (XOR) of every number in the list, as used by the puzzle box XOR puzzle.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "numbers",
+				Description: "Whole numbers to combine",
+				ElementType: types.Int64Type,
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *xorFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var numbers []int64
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &numbers))
+	if resp.Error != nil {
+		return
+	}
+
+	ints := make([]int, 0, len(numbers))
+	for _, n := range numbers {
+		ints = append(ints, int(n))
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, int64(challenges.XOR(ints))))
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceGetter is satisfied by both *schema.ResourceData and
+// *schema.ResourceDiff, so proof extraction can run during plan and apply.
+type resourceGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// configKnown reports whether every given top-level attribute in the raw
+// configuration is wholly known. Validation is deferred to apply otherwise.
+func configKnown(d *schema.ResourceDiff, keys ...string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	for _, key := range keys {
+		if !config.GetAttr(key).IsWhollyKnown() {
+			return false
+		}
+	}
+	return true
+}
+
+// historyProofKeys are the proof inputs validated against the simulated cloud
+// or the progress file, which apply can change before the proof is read.
+var historyProofKeys = []string{"drift_target_id", "flaky_id", "puzzle_box_id"}
+
+// configOnlyProof reports whether the proof depends only on configuration, so
+// the plan-time outcome is the one apply will record.
+func configOnlyProof(d resourceGetter) bool {
+	for _, key := range historyProofKeys {
+		if _, ok := d.GetOk(key); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// previewText renders the plan-time outcome stored in the preview attribute.
+func previewText(success bool, message string) string {
+	if success {
+		return fmt.Sprintf("pass: %s", message)
+	}
+	return fmt.Sprintf("fail: %s", message)
+}
@@ -0,0 +1,213 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// progressStore persists completion records outside Terraform state so that
+// resources can be imported again after a workspace loses its state.
+type progressStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// progressFile is the on-disk layout of the progress store.
+type progressFile struct {
+	Records map[string]*progressRecord `json:"records"`
+}
+
+// progressRecord is the persisted outcome of a single resource, keyed by
+// resource ID in progressFile.
+type progressRecord struct {
+	ResourceType     string            `json:"resource_type"`
+	Player           string            `json:"player"`
+	ChallengeID      string            `json:"challenge_id,omitempty"`
+	Validated        bool              `json:"validated"`
+	Message          string            `json:"message,omitempty"`
+	Points           int               `json:"points,omitempty"`
+	ProofSource      string            `json:"proof_source,omitempty"`
+	FlagHash         string            `json:"flag_hash,omitempty"`
+	Timestamp        string            `json:"timestamp,omitempty"`
+	LastValidatedAt  string            `json:"last_validated_at,omitempty"`
+	ChallengeVersion int               `json:"challenge_version,omitempty"`
+	Attributes       map[string]string `json:"attributes,omitempty"`
+}
+
+// defaultProgressFile returns ~/.ctfchallenge/progress.json, or "" when the
+// home directory cannot be determined.
+func defaultProgressFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ctfchallenge", "progress.json")
+}
+
+func newProgressStore(path string) *progressStore {
+	if path == "" {
+		return nil
+	}
+	return &progressStore{path: path}
+}
+
+func (s *progressStore) load() (*progressFile, error) {
+	file := &progressFile{Records: make(map[string]*progressRecord)}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading progress file %s: %w", s.path, err)
+	}
+
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("parsing progress file %s: %w", s.path, err)
+	}
+	if file.Records == nil {
+		file.Records = make(map[string]*progressRecord)
+	}
+	return file, nil
+}
+
+func (s *progressStore) save(file *progressFile) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating progress directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("writing progress file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// lock serialises changes to the progress file, within this process and
+// across the provider processes of other configurations sharing it. Reads
+// need no lock: save replaces the file in a single rename.
+func (s *progressStore) lock() (func(), error) {
+	s.mu.Lock()
+	unlock, err := lockFile(s.path)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlock()
+		s.mu.Unlock()
+	}, nil
+}
+
+// Get returns the record stored for a resource ID, or nil when there is none.
+// A nil store behaves as an empty one.
+func (s *progressStore) Get(id string) (*progressRecord, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return file.Records[id], nil
+}
+
+// Put stores the record for a resource ID.
+func (s *progressStore) Put(id string, record *progressRecord) error {
+	if s == nil {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	file.Records[id] = record
+	return s.save(file)
+}
+
+// Delete removes the record for a resource ID.
+func (s *progressStore) Delete(id string) error {
+	if s == nil {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := file.Records[id]; !ok {
+		return nil
+	}
+	delete(file.Records, id)
+	return s.save(file)
+}
+
+// FindLatest returns the ID and record of the most recent validated record
+// for a player matching resourceType and challengeID.
+func (s *progressStore) FindLatest(resourceType, player, challengeID string) (string, *progressRecord, error) {
+	return s.FindLatestWith(resourceType, player, challengeID, nil)
+}
+
+// FindLatestWith is FindLatest restricted to records whose attributes
+// include every entry of attributes.
+func (s *progressStore) FindLatestWith(resourceType, player, challengeID string, attributes map[string]string) (string, *progressRecord, error) {
+	if s == nil {
+		return "", nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var latestID string
+	var latest *progressRecord
+	for id, record := range file.Records {
+		if record.ResourceType != resourceType || record.Player != player || record.ChallengeID != challengeID || !record.Validated {
+			continue
+		}
+		if !hasAttributes(record, attributes) {
+			continue
+		}
+		// IDs embed a sortable creation timestamp
+		if latest == nil || id > latestID {
+			latestID, latest = id, record
+		}
+	}
+	return latestID, latest, nil
+}
+
+// hasAttributes reports whether a record's attributes include every entry of
+// attributes.
+func hasAttributes(record *progressRecord, attributes map[string]string) bool {
+	for key, value := range attributes {
+		if record.Attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
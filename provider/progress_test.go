@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestProgressStoreConcurrentPut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+
+	// Each store stands in for the provider process of another
+	// configuration sharing the progress file
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 8; i++ {
+		store := newProgressStore(path)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				errs <- store.Put(fmt.Sprintf("record-%d-%d", i, j), &progressRecord{ResourceType: "ctfchallenge_flag_validator", Validated: true})
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("error saving record: %s", err)
+		}
+	}
+
+	file, err := newProgressStore(path).load()
+	if err != nil {
+		t.Fatalf("error loading progress: %s", err)
+	}
+	if len(file.Records) != 200 {
+		t.Fatalf("expected 200 records, got %d: concurrent saves lost records", len(file.Records))
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, entry := range entries {
+		if name := entry.Name(); name != "progress.json" && name != "progress.json.lock" {
+			t.Errorf("unexpected file %s left next to the progress file", name)
+		}
+	}
+}
+
+func TestProgressStoreDelete(t *testing.T) {
+	store := newProgressStore(filepath.Join(t.TempDir(), "nested", "progress.json"))
+	if err := store.Put("a", &progressRecord{Player: "alice"}); err != nil {
+		t.Fatalf("error saving record: %s", err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("error deleting record: %s", err)
+	}
+	if err := store.Delete("missing"); err != nil {
+		t.Fatalf("error deleting missing record: %s", err)
+	}
+	if record, err := store.Get("a"); err != nil || record != nil {
+		t.Fatalf("expected no record, got %v (%v)", record, err)
+	}
+}
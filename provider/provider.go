@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -23,18 +24,54 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("TF_CTF_API", ""),
 				Description: "Optional API endpoint for score tracking",
 			},
+			"progress_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_CTF_PROGRESS_FILE", ""),
+				Description: "Path of the local file recording completed challenges, used when importing resources. Defaults to ~/.ctfchallenge/progress.json",
+			},
+			"instance_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_CTF_INSTANCE", ""),
+				Description: "Name identifying this provider instance, usually its alias. Resources record it in provider_instance. Defaults to \"default\"",
+			},
+			"cloud_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_CTF_CLOUD_FILE", ""),
+				Description: "Path of the JSON file backing the simulated cloud resources (ctfchallenge_sim_*). Defaults to ~/.ctfchallenge/cloud.json",
+			},
+			"event_salt": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_CTF_EVENT_SALT", ""),
+				Description: "Salt mixed with player_name to generate each player's puzzles. Organisers set one per event; the same salt and player_name always give the same puzzles",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"ctfchallenge_flag_validator":     resourceFlagValidator(),
 			"ctfchallenge_puzzle_box":         resourcePuzzleBox(),
 			"ctfchallenge_meta_challenge":     resourceMetaChallenge(),
 			"ctfchallenge_validated_resource": resourceValidatedResource(), // ADD THIS LINE
+			"ctfchallenge_vault":              resourceVault(),
+			"ctfchallenge_sim_network":        resourceSimNetwork(),
+			"ctfchallenge_sim_subnet":         resourceSimSubnet(),
+			"ctfchallenge_sim_instance":       resourceSimInstance(),
+			"ctfchallenge_sim_bucket":         resourceSimBucket(),
+			"ctfchallenge_drift_target":       resourceDriftTarget(),
+			"ctfchallenge_flaky":              resourceFlaky(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"ctfchallenge_hint":              dataSourceHint(),
 			"ctfchallenge_list":              dataSourceChallengeList(),
 			"ctfchallenge_challenge_info":    dataSourceChallengeInfo(),
 			"ctfchallenge_validation_helper": dataSourceValidationHelper(),
+			"ctfchallenge_expression_check":  dataSourceExpressionCheck(),
+			"ctfchallenge_fixture":           dataSourceFixture(),
+			"ctfchallenge_dataset":           dataSourceDataset(),
+			"ctfchallenge_puzzle":            dataSourcePuzzle(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -42,17 +79,114 @@ func Provider() *schema.Provider {
 
 // ProviderConfig holds the provider configuration.
 type ProviderConfig struct {
-	PlayerName  string
-	APIEndpoint string
+	PlayerName   string
+	APIEndpoint  string
+	InstanceName string
+	EventSalt    string
+	Progress     *progressStore
+	Cloud        *simCloud
+}
+
+// defaultInstanceName identifies a provider instance without instance_name.
+const defaultInstanceName = "default"
+
+// Instance returns the name resources record in provider_instance.
+func (c *ProviderConfig) Instance() string {
+	if c.InstanceName == "" {
+		return defaultInstanceName
+	}
+	return c.InstanceName
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	config := &ProviderConfig{
-		PlayerName:  d.Get("player_name").(string),
-		APIEndpoint: d.Get("api_endpoint").(string),
-	}
+	config := newProviderConfig(
+		d.Get("player_name").(string),
+		d.Get("api_endpoint").(string),
+		d.Get("progress_file").(string),
+		d.Get("instance_name").(string),
+		d.Get("cloud_file").(string),
+		d.Get("event_salt").(string),
+	)
 
 	return config, diags
 }
+
+// newProviderConfig builds the configuration shared by the SDKv2 and
+// framework halves of the provider.
+func newProviderConfig(playerName, apiEndpoint, progressFile, instanceName, cloudFile, eventSalt string) *ProviderConfig {
+	if progressFile == "" {
+		progressFile = defaultProgressFile()
+	}
+	if cloudFile == "" {
+		cloudFile = defaultCloudFile()
+	}
+
+	return &ProviderConfig{
+		PlayerName:   playerName,
+		APIEndpoint:  apiEndpoint,
+		InstanceName: instanceName,
+		EventSalt:    eventSalt,
+		Progress:     newProgressStore(progressFile),
+		Cloud:        newSimCloud(cloudFile),
+	}
+}
+
+// providerConfig returns the configured provider, or an empty configuration
+// when the provider has not been configured.
+func providerConfig(m interface{}) *ProviderConfig {
+	if config, ok := m.(*ProviderConfig); ok && config != nil {
+		return config
+	}
+	return &ProviderConfig{}
+}
+
+// setProviderInstance records which provider instance and player manage a
+// resource, so that validators can tell aliased instances apart.
+func setProviderInstance(d *schema.ResourceData, m interface{}) {
+	config := providerConfig(m)
+	d.Set("provider_instance", config.Instance())
+	d.Set("player_name", config.PlayerName)
+}
+
+// recordProgress persists a record, returning a warning when it cannot be
+// written. Progress is a convenience for imports and never fails an apply.
+func recordProgress(m interface{}, id string, record *progressRecord) diag.Diagnostics {
+	config := providerConfig(m)
+	record.Player = config.PlayerName
+	if err := config.Progress.Put(id, record); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Failed to record progress",
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+// forgetProgress removes a persisted record, returning a warning on failure.
+func forgetProgress(m interface{}, id string) diag.Diagnostics {
+	if err := providerConfig(m).Progress.Delete(id); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Failed to update progress",
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+// importRecord looks up the persisted record for an imported resource ID. A
+// record written by a different player is treated as an error.
+func importRecord(m interface{}, id string) (*progressRecord, error) {
+	config := providerConfig(m)
+	record, err := config.Progress.Get(id)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	if record.Player != config.PlayerName {
+		return nil, fmt.Errorf("progress record %s belongs to player %q, not %q", id, record.Player, config.PlayerName)
+	}
+	return record, nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestMuxServer(t *testing.T) {
+	ctx := context.Background()
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		Provider().GRPCProvider,
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("unexpected error diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	for _, name := range []string{"xor", "flag_format", "challenge_points", "check_hint_cost"} {
+		if _, ok := resp.Functions[name]; !ok {
+			t.Errorf("function %s not served", name)
+		}
+	}
+	if _, ok := resp.EphemeralResourceSchemas["ctfchallenge_flag"]; !ok {
+		t.Errorf("ephemeral resource ctfchallenge_flag not served")
+	}
+	if _, ok := resp.ResourceSchemas["ctfchallenge_flag_validator"]; !ok {
+		t.Errorf("resource ctfchallenge_flag_validator not served")
+	}
+}
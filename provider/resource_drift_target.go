@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+// simDriftTarget is a drift target in the simulated cloud. Besides its
+// attributes it keeps the history the drift challenges are validated from.
+type simDriftTarget struct {
+	ID          string            `json:"id"`
+	ChallengeID string            `json:"challenge_id"`
+	Name        string            `json:"name"`
+	Size        int               `json:"size"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	UpstreamID  string            `json:"upstream_id,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+
+	Revision              int  `json:"revision"`
+	Refreshes             int  `json:"refreshes"`
+	Drifts                int  `json:"drifts"`
+	DriftsSinceRevert     int  `json:"drifts_since_revert"`
+	Reverted              bool `json:"reverted"`
+	ReconciledRefreshes   int  `json:"reconciled_refreshes"`
+	ReconciledSinceRevert int  `json:"reconciled_since_revert"`
+
+	// UpstreamRevision is the upstream's revision when a downstream target
+	// was created, and Generation numbers the downstream targets created for
+	// an upstream. Downstreams counts them on the upstream.
+	UpstreamRevision int `json:"upstream_revision,omitempty"`
+	Generation       int `json:"generation,omitempty"`
+	Downstreams      int `json:"downstreams,omitempty"`
+
+	// DownstreamDestroyedAt is the upstream's revision plus one when a
+	// downstream target was last destroyed, or 0. A downstream created at a
+	// later revision replaced it across the upstream's update, which is
+	// what ReplacedWithUpstream records.
+	DownstreamDestroyedAt int  `json:"downstream_destroyed_at,omitempty"`
+	ReplacedWithUpstream  bool `json:"replaced_with_upstream,omitempty"`
+
+	History []string `json:"history"`
+}
+
+// driftHistoryLength is the number of events kept in a drift target's history.
+const driftHistoryLength = 20
+
+func (t *simDriftTarget) record(format string, args ...interface{}) {
+	t.History = append(t.History, fmt.Sprintf(format, args...))
+	if len(t.History) > driftHistoryLength {
+		t.History = t.History[len(t.History)-driftHistoryLength:]
+	}
+}
+
+// applyDriftSchedule changes the target outside Terraform according to its
+// challenge's schedule (see challenges.DriftSchedules), returning the
+// attribute that drifted or "".
+func applyDriftSchedule(t *simDriftTarget) string {
+	if t.Labels == nil {
+		t.Labels = make(map[string]string)
+	}
+
+	switch t.ChallengeID {
+	case "drift_ignore":
+		t.Labels["patched_by"] = fmt.Sprintf("ops-bot-%d", t.Refreshes)
+		return "labels.patched_by"
+	case "refresh_only":
+		if t.Refreshes == 1 {
+			t.Size *= 2
+			return "size"
+		}
+	case "replace_trigger":
+		if t.UpstreamID == "" && t.Refreshes == 1 {
+			t.Labels["rotated_by"] = "key-rotation-job"
+			return "labels.rotated_by"
+		}
+	}
+	return ""
+}
+
+func resourceDriftTarget() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDriftTargetCreate,
+		ReadContext:   resourceDriftTargetRead,
+		UpdateContext: resourceDriftTargetUpdate,
+		DeleteContext: resourceDriftTargetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"challenge_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The drift challenge whose schedule the target follows",
+				ValidateFunc: validation.StringInSlice(challenges.DriftChallengeIDs(), false),
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the target",
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Size of the target",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Labels of the target",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"upstream_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "ID of an upstream drift target this target is derived from",
+			},
+			"revision": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of updates Terraform has applied to the target",
+			},
+			"refresh_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of times the target has been refreshed",
+			},
+			"drift_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of times the target has changed outside Terraform",
+			},
+			"in_sync": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "For a target with upstream_id, whether it was created after the upstream's latest update",
+			},
+			"history": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Recent events in the target's life, oldest first",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceDriftTargetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	target := &simDriftTarget{
+		ID:          newSimID("drift"),
+		ChallengeID: d.Get("challenge_id").(string),
+		Name:        d.Get("name").(string),
+		Size:        d.Get("size").(int),
+		Labels:      driftLabels(d),
+		UpstreamID:  d.Get("upstream_id").(string),
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	var inSync bool
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if target.UpstreamID != "" {
+			upstream, ok := file.DriftTargets[target.UpstreamID]
+			if !ok {
+				return fmt.Errorf("InvalidUpstreamID: drift target %s does not exist", target.UpstreamID)
+			}
+			upstream.Downstreams++
+			upstream.record("downstream %s created", target.ID)
+			target.UpstreamRevision = upstream.Revision
+			target.Generation = upstream.Downstreams
+			target.ReplacedWithUpstream = upstream.DownstreamDestroyedAt > 0 && upstream.DownstreamDestroyedAt-1 < upstream.Revision
+			upstream.DownstreamDestroyedAt = 0
+			inSync = true
+		}
+		target.record("created")
+		file.DriftTargets[target.ID] = target
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(target.ID)
+	setProviderInstance(d, m)
+	setDriftTarget(d, target, inSync)
+	return nil
+}
+
+// resourceDriftTargetRead refreshes the target from the simulated cloud,
+// letting its drift schedule change it first. A refresh whose prior state
+// already matches a drifted target counts as reconciled, but only when that
+// state was saved after the latest refresh: terraform plan refreshes without
+// saving, so each state an apply saves counts once however often it is read.
+func resourceDriftTargetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var target simDriftTarget
+	var inSync bool
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		t, ok := file.DriftTargets[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+
+		saved := d.Get("refresh_count").(int) == t.Refreshes
+		if saved && t.Drifts > 0 && d.Get("size").(int) == t.Size && reflect.DeepEqual(driftLabels(d), nonNilLabels(t.Labels)) {
+			t.ReconciledRefreshes++
+			t.ReconciledSinceRevert++
+		}
+
+		t.Refreshes++
+		if attribute := applyDriftSchedule(t); attribute != "" {
+			t.Drifts++
+			t.DriftsSinceRevert++
+			t.record("refresh %d: %s changed outside Terraform", t.Refreshes, attribute)
+		}
+
+		if upstream, ok := file.DriftTargets[t.UpstreamID]; ok {
+			inSync = upstream.Revision == t.UpstreamRevision
+		}
+		target = *t
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	setDriftTarget(d, &target, inSync)
+	return nil
+}
+
+// resourceDriftTargetUpdate applies the configuration to the target. An
+// update that changes a drifted size or labels back reverts the drift.
+func resourceDriftTargetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var target simDriftTarget
+	var inSync bool
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		t, ok := file.DriftTargets[d.Id()]
+		if !ok {
+			return fmt.Errorf("drift target %s %w", d.Id(), errSimNotFound)
+		}
+
+		size := d.Get("size").(int)
+		labels := driftLabels(d)
+		reverted := t.Drifts > 0 && (size != t.Size || !reflect.DeepEqual(labels, nonNilLabels(t.Labels)))
+
+		t.Name = d.Get("name").(string)
+		t.Size = size
+		t.Labels = labels
+		t.Revision++
+		if reverted {
+			t.Reverted = true
+			t.DriftsSinceRevert = 0
+			t.ReconciledSinceRevert = 0
+			t.record("apply: revision %d reverted drift", t.Revision)
+		} else {
+			t.record("apply: revision %d", t.Revision)
+		}
+
+		if upstream, ok := file.DriftTargets[t.UpstreamID]; ok {
+			inSync = upstream.Revision == t.UpstreamRevision
+		}
+		target = *t
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	setDriftTarget(d, &target, inSync)
+	return nil
+}
+
+func resourceDriftTargetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if t, ok := file.DriftTargets[d.Id()]; ok {
+			if upstream, ok := file.DriftTargets[t.UpstreamID]; ok {
+				upstream.DownstreamDestroyedAt = upstream.Revision + 1
+				upstream.record("downstream %s destroyed", t.ID)
+			}
+		}
+		delete(file.DriftTargets, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setDriftTarget(d *schema.ResourceData, t *simDriftTarget, inSync bool) {
+	d.Set("challenge_id", t.ChallengeID)
+	d.Set("name", t.Name)
+	d.Set("size", t.Size)
+	d.Set("labels", t.Labels)
+	d.Set("upstream_id", t.UpstreamID)
+	d.Set("revision", t.Revision)
+	d.Set("refresh_count", t.Refreshes)
+	d.Set("drift_count", t.Drifts)
+	d.Set("in_sync", inSync)
+	d.Set("history", t.History)
+}
+
+// nonNilLabels returns labels, or an empty map when there are none, for
+// comparison with the labels attribute.
+func nonNilLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+	return labels
+}
+
+// driftTargetProof builds drift proof from a target's history in the
+// simulated cloud.
+func driftTargetProof(m interface{}, id string) (*challenges.DriftProof, error) {
+	var proof *challenges.DriftProof
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		t, ok := file.DriftTargets[id]
+		if !ok {
+			return fmt.Errorf("drift target %s %w", id, errSimNotFound)
+		}
+
+		proof = &challenges.DriftProof{
+			TargetID:              t.ID,
+			ChallengeID:           t.ChallengeID,
+			Refreshes:             t.Refreshes,
+			Revision:              t.Revision,
+			Drifts:                t.Drifts,
+			DriftsSinceRevert:     t.DriftsSinceRevert,
+			Reverted:              t.Reverted,
+			ReconciledRefreshes:   t.ReconciledRefreshes,
+			ReconciledSinceRevert: t.ReconciledSinceRevert,
+			UpstreamID:            t.UpstreamID,
+			UpstreamRevision:      t.UpstreamRevision,
+			Generation:            t.Generation,
+			ReplacedWithUpstream:  t.ReplacedWithUpstream,
+		}
+		if upstream, ok := file.DriftTargets[t.UpstreamID]; ok {
+			proof.CurrentUpstreamRevision = upstream.Revision
+		}
+		return nil
+	})
+	return proof, err
+}
+
+// driftLabels converts the labels attribute into a map of strings.
+func driftLabels(d *schema.ResourceData) map[string]string {
+	labels := make(map[string]string)
+	for k, v := range d.Get("labels").(map[string]interface{}) {
+		labels[k] = v.(string)
+	}
+	return labels
+}
@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// createDriftTarget creates a drift target from config and returns its state.
+func createDriftTarget(t *testing.T, meta *ProviderConfig, config map[string]interface{}) *terraform.InstanceState {
+	t.Helper()
+
+	d := schema.TestResourceDataRaw(t, resourceDriftTarget().Schema, config)
+	if diags := resourceDriftTargetCreate(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("error creating drift target: %v", diags)
+	}
+	return d.State()
+}
+
+// driftTargetRun creates a drift target, then runs terraform commands
+// against it: "plan" refreshes it and discards the result, "apply" refreshes
+// it and saves the result as state, as an apply without changes does.
+func driftTargetRun(t *testing.T, meta *ProviderConfig, challengeID string, commands ...string) string {
+	t.Helper()
+
+	state := createDriftTarget(t, meta, map[string]interface{}{"challenge_id": challengeID, "name": "target"})
+	for _, command := range commands {
+		d := resourceDriftTarget().Data(state)
+		if diags := resourceDriftTargetRead(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("error refreshing drift target: %v", diags)
+		}
+		if command == "apply" {
+			state = d.State()
+		}
+	}
+	return state.ID
+}
+
+func TestResourceDriftTargetReconciled(t *testing.T) {
+	cases := map[string]struct {
+		challenge string
+		commands  []string
+		expected  int
+	}{
+		"drift not yet saved": {
+			challenge: "drift_ignore",
+			commands:  []string{"apply"},
+			expected:  0,
+		},
+		"applies": {
+			challenge: "drift_ignore",
+			commands:  []string{"apply", "apply", "apply", "apply"},
+			expected:  3,
+		},
+		"plans after an apply": {
+			challenge: "drift_ignore",
+			commands:  []string{"apply", "apply", "plan", "plan", "plan"},
+			expected:  2,
+		},
+		"plans between applies": {
+			challenge: "drift_ignore",
+			commands:  []string{"apply", "plan", "apply", "plan", "apply"},
+			expected:  2,
+		},
+		"plans after a refresh-only apply": {
+			challenge: "refresh_only",
+			commands:  []string{"plan", "apply", "plan", "plan", "plan"},
+			expected:  1,
+		},
+		"applies after a refresh-only apply": {
+			challenge: "refresh_only",
+			commands:  []string{"plan", "apply", "apply", "plan", "apply", "plan"},
+			expected:  3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := testProviderConfig(t)
+			id := driftTargetRun(t, meta, tc.challenge, tc.commands...)
+
+			proof, err := driftTargetProof(meta, id)
+			if err != nil {
+				t.Fatalf("error reading drift proof: %s", err)
+			}
+			if proof.ReconciledRefreshes != tc.expected || proof.ReconciledSinceRevert != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%d\n\ngot:\n\n%d %d\n\n", tc.expected, proof.ReconciledRefreshes, proof.ReconciledSinceRevert)
+			}
+		})
+	}
+}
+
+func TestResourceDriftTargetReplacedWithUpstream(t *testing.T) {
+	// Each step destroys the downstream target, updates the upstream or
+	// creates a new downstream target, in the order an apply would
+	cases := map[string]struct {
+		steps    []string
+		expected bool
+	}{
+		"replace_triggered_by": {
+			steps:    []string{"destroy", "update", "create"},
+			expected: true,
+		},
+		"created before the old one is destroyed": {
+			steps:    []string{"update", "create", "destroy"},
+			expected: false,
+		},
+		"replaced in a later apply": {
+			steps:    []string{"update", "destroy", "create"},
+			expected: false,
+		},
+		"replaced in an earlier apply": {
+			steps:    []string{"destroy", "create", "update"},
+			expected: false,
+		},
+		"destroyed before two updates": {
+			steps:    []string{"destroy", "update", "update", "create"},
+			expected: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := testProviderConfig(t)
+			upstream := createDriftTarget(t, meta, map[string]interface{}{"challenge_id": "replace_trigger", "name": "upstream"})
+			downstreamConfig := map[string]interface{}{"challenge_id": "replace_trigger", "name": "downstream", "upstream_id": upstream.ID}
+			downstream := createDriftTarget(t, meta, downstreamConfig)
+
+			var latest string
+			for _, step := range tc.steps {
+				var diags diag.Diagnostics
+				switch step {
+				case "destroy":
+					diags = resourceDriftTargetDelete(context.Background(), resourceDriftTarget().Data(downstream), meta)
+				case "update":
+					d := schema.TestResourceDataRaw(t, resourceDriftTarget().Schema, map[string]interface{}{"challenge_id": "replace_trigger", "name": "upstream", "size": 2})
+					d.SetId(upstream.ID)
+					diags = resourceDriftTargetUpdate(context.Background(), d, meta)
+				case "create":
+					latest = createDriftTarget(t, meta, downstreamConfig).ID
+				}
+				if diags.HasError() {
+					t.Fatalf("error in step %s: %v", step, diags)
+				}
+			}
+
+			proof, err := driftTargetProof(meta, latest)
+			if err != nil {
+				t.Fatalf("error reading drift proof: %s", err)
+			}
+			if proof.Generation != 2 || proof.ReplacedWithUpstream != tc.expected {
+				t.Fatalf("\n\nexpected:\n\ngeneration 2, replaced with upstream %t\n\ngot:\n\ngeneration %d, replaced with upstream %t\n\n", tc.expected, proof.Generation, proof.ReplacedWithUpstream)
+			}
+		})
+	}
+}
@@ -2,12 +2,17 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
 )
 
@@ -17,6 +22,14 @@ func resourceFlagValidator() *schema.Resource {
 		ReadContext:   resourceFlagValidatorRead,
 		UpdateContext: resourceFlagValidatorUpdate,
 		DeleteContext: resourceFlagValidatorDelete,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			upgradeStep(0, resourceFlagValidatorV0(), resourceFlagValidatorStateUpgradeV0),
+		},
+		CustomizeDiff: resourceFlagValidatorCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceFlagValidatorImport,
+		},
 		Schema: map[string]*schema.Schema{
 			"challenge_id": {
 				Type:        schema.TypeString,
@@ -96,6 +109,244 @@ func resourceFlagValidator() *schema.Resource {
 					},
 				},
 			},
+			"check_proof": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Proof from check blocks with their scoped data sources and assertions",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"check_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the check block",
+						},
+						"scoped_data_source": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Data source declared inside the check block",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"data_source_type": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Type of the data source",
+									},
+									"data_source_name": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Name/identifier of the data source",
+									},
+									"attributes": {
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Description: "Data source attributes",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"assertions": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "JSON-encoded assert blocks",
+						},
+					},
+				},
+			},
+			"refactor_proof": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Proof of a refactor using moved, import and removed blocks",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"previous_addresses": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Resource instance addresses before the refactor",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"current_addresses": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Resource instance addresses after the refactor",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"moved": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "moved blocks",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"from": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Address before the move",
+									},
+									"to": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Address after the move",
+									},
+								},
+							},
+						},
+						"import": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "import blocks",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"to": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Address the object is imported into",
+									},
+									"id": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Import ID of the existing object",
+									},
+								},
+							},
+						},
+						"removed": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "removed blocks",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"from": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Address of the resource that is no longer managed",
+									},
+									"destroy": {
+										Type:        schema.TypeBool,
+										Optional:    true,
+										Default:     true,
+										Description: "Value of lifecycle.destroy in the removed block",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"test_files": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Paths of .tftest.hcl files to validate",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"test_files_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Combined SHA-256 hash of the validated test files, used to re-validate when they change",
+			},
+			"provider_proof": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Proof of the provider instances used and the providers passed into modules",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "A provider instance, identified by a resource it created",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"alias": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Default:     "",
+										Description: "Alias of the provider block, empty for the default instance",
+									},
+									"resource_address": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Address of a resource created by the instance",
+									},
+									"provider_instance": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "provider_instance attribute of the resource",
+									},
+									"player_name": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "player_name attribute of the resource",
+									},
+								},
+							},
+						},
+						"module": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "A module call that is passed providers",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"module_name": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Name of the module call",
+									},
+									"providers": {
+										Type:        schema.TypeMap,
+										Required:    true,
+										Description: "The module call's providers argument, mapping addresses in the module to provider instances",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									"configuration_aliases": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "configuration_aliases declared in the module's required_providers",
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"expression": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A self-contained Terraform expression evaluated by the provider",
+			},
+			"encoded_output": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Output produced with encoding or template functions, e.g. jsonencode(...) or templatefile(...)",
+			},
+			"recovered_flag": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A flag recovered from the state of a ctfchallenge_vault",
+			},
+			"drift_target_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of a ctfchallenge_drift_target whose update history the provider validates",
+			},
+			"flaky_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of a ctfchallenge_flaky whose operation history the provider validates",
+			},
+			"puzzle_box_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of a ctfchallenge_puzzle_box playing a stage of a puzzle chain whose recorded stages the provider validates",
+			},
+			"variable_declarations": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "HCL source of variable blocks to validate, e.g. file(\"variables.tf\")",
+			},
 			"module_proof": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -126,6 +377,17 @@ func resourceFlagValidator() *schema.Resource {
 					},
 				},
 			},
+			"fail_on_plan": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail the plan when the plan-time validation preview does not pass",
+			},
+			"preview": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Plan-time validation outcome, prefixed with pass: or fail:",
+			},
 			"validated": {
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -140,7 +402,18 @@ func resourceFlagValidator() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Sensitive:   true,
-				Description: "The flag revealed upon successful completion",
+				Description: "The flag revealed upon successful completion. Empty when flag_storage is hash",
+			},
+			"flag_storage": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{flagStoragePlaintext, flagStorageHash}, false),
+				Description:  "How the flag is kept in state: plaintext (default) or hash, which stores only flag_hash",
+			},
+			"flag_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "SHA-256 hash of the flag, set in every flag_storage mode",
 			},
 			"points": {
 				Type:        schema.TypeInt,
@@ -152,22 +425,95 @@ func resourceFlagValidator() *schema.Resource {
 				Computed:    true,
 				Description: "Timestamp when the challenge was completed",
 			},
+			"last_validated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the most recent validation run",
+			},
+			"challenge_version": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Version of the challenge definition the completion was validated against",
+			},
 			"proof_source": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "Source of the proof",
 			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
 			"validation_details": {
 				Type:        schema.TypeList,
 				Computed:    true,
 				Description: "Detailed validation results",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"checks": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Structured results for each criterion evaluated by the validator",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier of the check, e.g. precondition.1.error_message",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Outcome of the check (pass, fail, skip)",
+						},
+						"expected": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Expected value, when the check compares values",
+						},
+						"actual": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Actual value found in the proof",
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Description of the check outcome",
+						},
+						"hint": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Suggestion for fixing a failed check",
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagnostics) {
+const (
+	flagStoragePlaintext = "plaintext"
+	flagStorageHash      = "hash"
+)
+
+// hashFlag returns the hex-encoded SHA-256 hash of a flag, or "" for no flag.
+func hashFlag(flag string) string {
+	if flag == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(flag))
+	return hex.EncodeToString(sum[:])
+}
+
+func extractProofData(ctx context.Context, d resourceGetter, m interface{}) (*challenges.ProofData, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	proofData := &challenges.ProofData{
 		Resources:   []challenges.ResourceProof{},
@@ -179,6 +525,9 @@ func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagn
 	if v, ok := d.GetOk("resource_proof"); ok {
 		resourceProofList := v.([]interface{})
 		for _, rp := range resourceProofList {
+			if err := ctx.Err(); err != nil {
+				return nil, append(diags, diag.FromErr(err)...)
+			}
 			resourceProof := rp.(map[string]interface{})
 
 			proof := challenges.ResourceProof{
@@ -228,6 +577,9 @@ func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagn
 	if v, ok := d.GetOk("data_source_proof"); ok {
 		dataProofList := v.([]interface{})
 		for _, dp := range dataProofList {
+			if err := ctx.Err(); err != nil {
+				return nil, append(diags, diag.FromErr(err)...)
+			}
 			dataProof := dp.(map[string]interface{})
 
 			proof := challenges.DataSourceProof{
@@ -259,6 +611,267 @@ func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagn
 		}
 	}
 
+	// Extract check block proofs
+	if v, ok := d.GetOk("check_proof"); ok {
+		checkProofList := v.([]interface{})
+		for _, cp := range checkProofList {
+			if err := ctx.Err(); err != nil {
+				return nil, append(diags, diag.FromErr(err)...)
+			}
+			checkProof := cp.(map[string]interface{})
+
+			proof := challenges.CheckBlockProof{
+				Name: checkProof["check_name"].(string),
+			}
+
+			// Extract scoped data source
+			if scoped, ok := checkProof["scoped_data_source"].([]interface{}); ok && len(scoped) > 0 && scoped[0] != nil {
+				dataProof := scoped[0].(map[string]interface{})
+				dataSource := &challenges.DataSourceProof{
+					DataSourceType: dataProof["data_source_type"].(string),
+					DataSourceName: dataProof["data_source_name"].(string),
+					Attributes:     make(map[string]interface{}),
+				}
+				if attrs, ok := dataProof["attributes"].(map[string]interface{}); ok {
+					for k, v := range attrs {
+						dataSource.Attributes[k] = v
+					}
+				}
+				proof.ScopedDataSource = dataSource
+			}
+
+			// Extract assertions
+			if assertionsJSON, ok := checkProof["assertions"].(string); ok && assertionsJSON != "" {
+				var assertions []challenges.ConditionBlock
+				if err := json.Unmarshal([]byte(assertionsJSON), &assertions); err == nil {
+					proof.Assertions = assertions
+				} else {
+					diags = append(diags, diag.Diagnostic{
+						Severity: diag.Warning,
+						Summary:  "Failed to parse assertions",
+						Detail:   fmt.Sprintf("Check %s: %v", proof.Name, err),
+					})
+				}
+			}
+
+			proofData.CheckBlocks = append(proofData.CheckBlocks, proof)
+		}
+
+		if len(proofData.CheckBlocks) > 0 {
+			proofData.Source = fmt.Sprintf("checks:%d", len(proofData.CheckBlocks))
+		}
+	}
+
+	// Extract refactor proof
+	if v, ok := d.GetOk("refactor_proof"); ok {
+		refactorProofList := v.([]interface{})
+		if len(refactorProofList) > 0 && refactorProofList[0] != nil {
+			refactorProof := refactorProofList[0].(map[string]interface{})
+
+			proof := challenges.RefactorProof{
+				PreviousAddresses: expandStringList(refactorProof["previous_addresses"]),
+				CurrentAddresses:  expandStringList(refactorProof["current_addresses"]),
+			}
+
+			if moved, ok := refactorProof["moved"].([]interface{}); ok {
+				for _, m := range moved {
+					block := m.(map[string]interface{})
+					proof.Moved = append(proof.Moved, challenges.MovedBlock{
+						From: block["from"].(string),
+						To:   block["to"].(string),
+					})
+				}
+			}
+
+			if imports, ok := refactorProof["import"].([]interface{}); ok {
+				for _, i := range imports {
+					block := i.(map[string]interface{})
+					proof.Imports = append(proof.Imports, challenges.ImportBlock{
+						To: block["to"].(string),
+						ID: block["id"].(string),
+					})
+				}
+			}
+
+			if removed, ok := refactorProof["removed"].([]interface{}); ok {
+				for _, r := range removed {
+					block := r.(map[string]interface{})
+					proof.Removed = append(proof.Removed, challenges.RemovedBlock{
+						From:    block["from"].(string),
+						Destroy: block["destroy"].(bool),
+					})
+				}
+			}
+
+			proofData.Refactor = &proof
+			proofData.Source = "refactor"
+		}
+	}
+
+	// Extract test files
+	if v, ok := d.GetOk("test_files"); ok {
+		for _, path := range expandStringList(v) {
+			if err := ctx.Err(); err != nil {
+				return nil, append(diags, diag.FromErr(err)...)
+			}
+
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return nil, append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Failed to read test file",
+					Detail:   err.Error(),
+				})
+			}
+
+			proof, parseDiags := challenges.ParseTestFile(path, src)
+			if parseDiags.HasErrors() {
+				return nil, append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Failed to parse test file",
+					Detail:   parseDiags.Error(),
+				})
+			}
+
+			proofData.TestFiles = append(proofData.TestFiles, *proof)
+		}
+
+		if len(proofData.TestFiles) > 0 {
+			proofData.Source = fmt.Sprintf("tests:%d", len(proofData.TestFiles))
+		}
+	}
+
+	// Extract provider proof
+	if v, ok := d.GetOk("provider_proof"); ok {
+		providerProofList := v.([]interface{})
+		if len(providerProofList) > 0 && providerProofList[0] != nil {
+			providerProof := providerProofList[0].(map[string]interface{})
+
+			proof := challenges.ProviderProof{}
+
+			if instances, ok := providerProof["instance"].([]interface{}); ok {
+				for _, i := range instances {
+					block := i.(map[string]interface{})
+					proof.Instances = append(proof.Instances, challenges.ProviderInstanceProof{
+						Alias:           block["alias"].(string),
+						InstanceName:    block["provider_instance"].(string),
+						PlayerName:      block["player_name"].(string),
+						ResourceAddress: block["resource_address"].(string),
+					})
+				}
+			}
+
+			if modules, ok := providerProof["module"].([]interface{}); ok {
+				for _, m := range modules {
+					block := m.(map[string]interface{})
+					module := challenges.ModuleProviders{
+						ModuleName:           block["module_name"].(string),
+						Providers:            make(map[string]string),
+						ConfigurationAliases: expandStringList(block["configuration_aliases"]),
+					}
+					if providers, ok := block["providers"].(map[string]interface{}); ok {
+						for k, v := range providers {
+							module.Providers[k] = v.(string)
+						}
+					}
+					proof.Modules = append(proof.Modules, module)
+				}
+			}
+
+			proofData.Providers = &proof
+			proofData.Source = fmt.Sprintf("providers:%d", len(proof.Instances))
+		}
+	}
+
+	// Extract expression
+	if v, ok := d.GetOk("expression"); ok {
+		expr, exprDiags := challenges.ParseExpression(v.(string))
+		if exprDiags.HasErrors() {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to evaluate expression",
+				Detail:   exprDiags.Error(),
+			})
+		}
+
+		proofData.Expression = expr
+		proofData.Source = "expression"
+	}
+
+	// Extract encoded output
+	if v, ok := d.GetOk("encoded_output"); ok {
+		proofData.EncodedOutput = v.(string)
+		proofData.Source = "encoding"
+	}
+
+	// Extract recovered flag
+	if v, ok := d.GetOk("recovered_flag"); ok {
+		proofData.RecoveredFlag = v.(string)
+		proofData.Source = "state"
+	}
+
+	// Look up the drift target's history in the simulated cloud
+	if v, ok := d.GetOk("drift_target_id"); ok {
+		proof, err := driftTargetProof(m, v.(string))
+		if err != nil {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to read drift target",
+				Detail:   err.Error(),
+			})
+		}
+
+		proofData.Drift = proof
+		proofData.Source = "drift"
+	}
+
+	// Look up the flaky object's history in the simulated cloud
+	if v, ok := d.GetOk("flaky_id"); ok {
+		proof, err := flakyProof(m, v.(string))
+		if err != nil {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to read flaky object",
+				Detail:   err.Error(),
+			})
+		}
+
+		proofData.Flaky = proof
+		proofData.Source = "flaky"
+	}
+
+	// Look up the puzzle chain's solved stages in recorded progress
+	if v, ok := d.GetOk("puzzle_box_id"); ok {
+		proof, err := puzzleChainProof(m, v.(string))
+		if err != nil {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to read puzzle chain",
+				Detail:   err.Error(),
+			})
+		}
+
+		proofData.Chain = proof
+		proofData.Source = "chain"
+	}
+
+	// Extract variable declarations
+	if v, ok := d.GetOk("variable_declarations"); ok {
+		variables, parseDiags := challenges.ParseVariableDeclarations("variables.tf", []byte(v.(string)))
+		if parseDiags.HasErrors() {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to parse variable declarations",
+				Detail:   parseDiags.Error(),
+			})
+		}
+
+		proofData.Variables = variables
+		if len(variables) > 0 {
+			proofData.Source = fmt.Sprintf("variables:%d", len(variables))
+		}
+	}
+
 	// Extract module proof
 	if v, ok := d.GetOk("module_proof"); ok {
 		moduleProofList := v.([]interface{})
@@ -306,7 +919,7 @@ func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagn
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "No proof provided",
-			Detail:   "You must provide one of: proof_of_work, resource_proof, data_source_proof, or module_proof",
+			Detail:   "You must provide one of: proof_of_work, resource_proof, data_source_proof, check_proof, refactor_proof, test_files, variable_declarations, provider_proof, expression, encoded_output, recovered_flag, drift_target_id, flaky_id, puzzle_box_id, or module_proof",
 		})
 		return nil, diags
 	}
@@ -315,6 +928,41 @@ func extractProofData(d *schema.ResourceData) (*challenges.ProofData, diag.Diagn
 }
 
 func resourceFlagValidatorCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	challengeID := d.Get("challenge_id").(string)
+
+	if _, exists := challenges.Challenges[challengeID]; !exists {
+		return diag.Errorf("Unknown challenge: %s", challengeID)
+	}
+
+	setProviderInstance(d, m)
+	diags := resourceFlagValidatorValidate(ctx, d, m)
+	if d.Get("proof_source").(string) != "" {
+		d.SetId(id.PrefixedUniqueId(challengeID + "-"))
+		diags = append(diags, recordProgress(m, d.Id(), flagValidatorRecord(d))...)
+	}
+	return diags
+}
+
+// flagValidatorRecord captures the completion attributes persisted for import.
+func flagValidatorRecord(d *schema.ResourceData) *progressRecord {
+	return &progressRecord{
+		ResourceType:     "ctfchallenge_flag_validator",
+		ChallengeID:      d.Get("challenge_id").(string),
+		Validated:        d.Get("validated").(bool),
+		Message:          d.Get("message").(string),
+		Points:           d.Get("points").(int),
+		ProofSource:      d.Get("proof_source").(string),
+		FlagHash:         d.Get("flag_hash").(string),
+		Timestamp:        d.Get("timestamp").(string),
+		LastValidatedAt:  d.Get("last_validated_at").(string),
+		ChallengeVersion: d.Get("challenge_version").(int),
+	}
+}
+
+// resourceFlagValidatorValidate validates the submitted proof and records the
+// outcome. The completion timestamp is kept from a previous validation unless
+// the outcome changes; last_validated_at is refreshed every time.
+func resourceFlagValidatorValidate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	challengeID := d.Get("challenge_id").(string)
@@ -325,35 +973,69 @@ func resourceFlagValidatorCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	// Extract proof data
-	proofData, extractDiags := extractProofData(d)
+	proofData, extractDiags := extractProofData(ctx, d, m)
 	diags = append(diags, extractDiags...)
 
 	if proofData == nil {
 		return diags
 	}
 
-	// Validate using the enhanced validator
-	result := challenge.ValidateProof(proofData)
+	// Validate using the challenge's validator
+	result, err := challenge.ValidateProof(ctx, proofData)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	wasValidated, _ := d.GetChange("validated")
+	completedAt, _ := d.GetChange("timestamp")
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	testFilesSum, err := hashTestFiles(expandStringList(d.Get("test_files")))
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
 
 	d.Set("proof_source", proofData.Source)
+	d.Set("test_files_sha256", testFilesSum)
 	d.Set("validated", result.Success)
 	d.Set("message", result.Message)
+	d.Set("preview", previewText(result.Success, result.Message))
 	d.Set("validation_details", result.Details)
+	d.Set("checks", flattenChecks(result.Checks))
+	d.Set("last_validated_at", now)
 
 	if result.Success {
 		d.Set("points", challenge.Points)
-		d.Set("flag", result.Flag)
-		d.Set("timestamp", time.Now().UTC().Format(time.RFC3339))
+		d.Set("flag_hash", hashFlag(result.Flag))
+		if d.Get("flag_storage").(string) == flagStorageHash {
+			d.Set("flag", "")
+		} else {
+			d.Set("flag", result.Flag)
+		}
+		d.Set("challenge_version", challenge.DefinitionVersion())
+		if wasValidated.(bool) && completedAt.(string) != "" {
+			d.Set("timestamp", completedAt)
+		} else {
+			d.Set("timestamp", now)
+		}
+
+		reward := "Check the 'flag' output for your reward!"
+		if d.Get("flag_storage").(string) == flagStorageHash {
+			reward = "Reveal your reward with the ctfchallenge_flag ephemeral resource."
+		}
 
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Warning,
 			Summary:  "🎉 Challenge Completed!",
-			Detail: fmt.Sprintf("You earned %d points for completing '%s'. Check the 'flag' output for your reward!\n\nValidation details:\n%s",
-				challenge.Points, challenge.Name, formatDetails(result.Details)),
+			Detail: fmt.Sprintf("You earned %d points for completing '%s'. %s\n\nValidation details:\n%s",
+				challenge.Points, challenge.Name, reward, formatDetails(result.Details)),
 		})
 	} else {
 		d.Set("points", 0)
 		d.Set("flag", "")
+		d.Set("flag_hash", "")
+		d.Set("challenge_version", 0)
+		d.Set("timestamp", "")
 
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -362,10 +1044,122 @@ func resourceFlagValidatorCreate(ctx context.Context, d *schema.ResourceData, m
 		})
 	}
 
-	d.SetId(fmt.Sprintf("%s-%d", challengeID, time.Now().Unix()))
 	return diags
 }
 
+// challengeVersionChanged reports whether a recorded completion was validated
+// against an older definition of its challenge.
+func challengeVersionChanged(d resourceGetter) bool {
+	if !d.Get("validated").(bool) {
+		return false
+	}
+	challenge, exists := challenges.Challenges[d.Get("challenge_id").(string)]
+	if !exists {
+		return false
+	}
+	return d.Get("challenge_version").(int) != challenge.DefinitionVersion()
+}
+
+// hashTestFiles returns a combined SHA-256 hash of the test files, or "" when
+// there are none.
+func hashTestFiles(paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", nil
+	}
+	h := sha256.New()
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading test file: %w", err)
+		}
+		sum := sha256.Sum256(src)
+		fmt.Fprintf(h, "%s %x\n", path, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// testFilesChanged reports whether the content of the submitted test files
+// differs from what was last validated. Unreadable files are reported by apply.
+func testFilesChanged(d *schema.ResourceDiff) bool {
+	if !configKnown(d, "test_files") {
+		return false
+	}
+	sum, err := hashTestFiles(expandStringList(d.Get("test_files")))
+	return err == nil && sum != d.Get("test_files_sha256").(string)
+}
+
+// proofInputKeys are the attributes that feed validation.
+var proofInputKeys = []string{"challenge_id", "proof_of_work", "resource_proof", "data_source_proof", "check_proof", "refactor_proof", "test_files", "variable_declarations", "provider_proof", "expression", "encoded_output", "recovered_flag", "drift_target_id", "flaky_id", "puzzle_box_id", "module_proof"}
+
+// resourceFlagValidatorCustomizeDiff runs validation during plan when every
+// proof input is known and the proof depends only on configuration, so the
+// outcome shows up before apply.
+func resourceFlagValidatorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Switching storage mode re-runs validation to reveal or drop the flag
+	if oldStorage, newStorage := d.GetChange("flag_storage"); d.Id() != "" && (oldStorage == flagStorageHash) != (newStorage == flagStorageHash) {
+		if err := d.SetNewComputed("flag"); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && !d.HasChanges(proofInputKeys...) && !challengeVersionChanged(d) && !testFilesChanged(d) {
+		return nil
+	}
+
+	if d.Id() != "" {
+		for _, key := range []string{"flag", "flag_hash", "test_files_sha256", "points", "validation_details", "checks", "challenge_version", "proof_source", "last_validated_at"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !configKnown(d, proofInputKeys...) || !configOnlyProof(d) {
+		for _, key := range []string{"validated", "message", "preview", "timestamp"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	challengeID := d.Get("challenge_id").(string)
+	challenge, exists := challenges.Challenges[challengeID]
+	if !exists {
+		return fmt.Errorf("unknown challenge: %s", challengeID)
+	}
+
+	proofData, extractDiags := extractProofData(ctx, d, m)
+	if extractDiags.HasError() || proofData == nil {
+		// Report proof extraction problems from apply with full diagnostics
+		return nil
+	}
+
+	result, err := challenge.ValidateProof(ctx, proofData)
+	if err != nil {
+		return err
+	}
+
+	if d.Get("fail_on_plan").(bool) && !result.Success {
+		return fmt.Errorf("challenge '%s' would not validate: %s\n\nValidation details:\n%s",
+			challenge.Name, result.Message, formatDetails(result.Details))
+	}
+
+	if wasValidated, _ := d.GetChange("validated"); wasValidated.(bool) != result.Success {
+		if err := d.SetNewComputed("timestamp"); err != nil {
+			return err
+		}
+	}
+
+	if err := d.SetNew("validated", result.Success); err != nil {
+		return err
+	}
+	if err := d.SetNew("message", result.Message); err != nil {
+		return err
+	}
+	return d.SetNew("preview", previewText(result.Success, result.Message))
+}
+
 func formatDetails(details []string) string {
 	if len(details) == 0 {
 		return "No additional details"
@@ -377,15 +1171,128 @@ func formatDetails(details []string) string {
 	return result
 }
 
+func expandStringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func flattenChecks(checks []challenges.Check) []interface{} {
+	result := make([]interface{}, 0, len(checks))
+	for _, c := range checks {
+		result = append(result, map[string]interface{}{
+			"id":       c.ID,
+			"status":   string(c.Status),
+			"expected": c.Expected,
+			"actual":   c.Actual,
+			"message":  c.Message,
+			"hint":     c.Hint,
+		})
+	}
+	return result
+}
+
 func resourceFlagValidatorRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return nil
+	var diags diag.Diagnostics
+
+	challengeID := d.Get("challenge_id").(string)
+	if _, exists := challenges.Challenges[challengeID]; !exists {
+		d.SetId("")
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Challenge no longer exists",
+			Detail:   fmt.Sprintf("Challenge '%s' has been removed from the provider, so its completion record was dropped from state.", challengeID),
+		})
+	}
+
+	if challengeVersionChanged(d) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Challenge requirements changed",
+			Detail: fmt.Sprintf("'%s' was completed against version %d of the challenge, but the current version is %d. "+
+				"The next apply will re-validate your proof against the new requirements.",
+				challengeID, d.Get("challenge_version").(int), challenges.Challenges[challengeID].DefinitionVersion()),
+		})
+	}
+
+	return diags
 }
 
 func resourceFlagValidatorUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return resourceFlagValidatorCreate(ctx, d, m)
+	diags := resourceFlagValidatorValidate(ctx, d, m)
+	if d.Get("proof_source").(string) != "" {
+		diags = append(diags, recordProgress(m, d.Id(), flagValidatorRecord(d))...)
+	}
+	return diags
 }
 
 func resourceFlagValidatorDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := forgetProgress(m, d.Id())
 	d.SetId("")
-	return nil
+	return diags
+}
+
+// resourceFlagValidatorImport accepts either a full resource ID
+// (<challenge_id>-<suffix>) or a bare challenge ID, which selects the player's
+// latest validated completion of that challenge. Computed attributes are
+// restored from recorded progress when available; otherwise only challenge_id
+// is set and the proof is re-validated on the next apply.
+func resourceFlagValidatorImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	importID := d.Id()
+
+	if _, exists := challenges.Challenges[importID]; exists {
+		config := providerConfig(m)
+		latestID, _, err := config.Progress.FindLatest("ctfchallenge_flag_validator", config.PlayerName, importID)
+		if err != nil {
+			return nil, err
+		}
+		if latestID == "" {
+			latestID = id.PrefixedUniqueId(importID + "-")
+		}
+		d.SetId(latestID)
+	}
+
+	record, err := importRecord(m, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	setProviderInstance(d, m)
+
+	if record == nil {
+		challengeID := trimUniqueSuffix(d.Id())
+		if _, exists := challenges.Challenges[challengeID]; !exists {
+			return nil, fmt.Errorf("cannot import %q: expected <challenge_id> or <challenge_id>-<suffix> for a known challenge", d.Id())
+		}
+		d.Set("challenge_id", challengeID)
+		d.Set("validated", false)
+		return []*schema.ResourceData{d}, nil
+	}
+
+	d.Set("challenge_id", record.ChallengeID)
+	d.Set("validated", record.Validated)
+	d.Set("message", record.Message)
+	d.Set("preview", previewText(record.Validated, record.Message))
+	d.Set("points", record.Points)
+	d.Set("proof_source", record.ProofSource)
+	d.Set("flag_hash", record.FlagHash)
+	d.Set("timestamp", record.Timestamp)
+	d.Set("last_validated_at", record.LastValidatedAt)
+	d.Set("challenge_version", record.ChallengeVersion)
+	d.Set("fail_on_plan", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// trimUniqueSuffix strips the id.PrefixedUniqueId suffix from a resource ID.
+func trimUniqueSuffix(resourceID string) string {
+	cut := len(resourceID) - id.UniqueIDSuffixLength - 1
+	if cut <= 0 || resourceID[cut] != '-' {
+		return resourceID
+	}
+	return resourceID[:cut]
 }
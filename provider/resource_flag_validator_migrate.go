@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceFlagValidatorV0 is the schema of ctfchallenge_flag_validator before
+// schema versioning was introduced.
+func resourceFlagValidatorV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"challenge_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"proof_of_work": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"resource_proof": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type":    {Type: schema.TypeString, Required: true},
+						"resource_name":    {Type: schema.TypeString, Required: true},
+						"attributes":       {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"lifecycle_config": {Type: schema.TypeString, Optional: true},
+						"meta_arguments":   {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					},
+				},
+			},
+			"data_source_proof": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"data_source_type": {Type: schema.TypeString, Required: true},
+						"data_source_name": {Type: schema.TypeString, Required: true},
+						"attributes":       {Type: schema.TypeMap, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"lifecycle_config": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"module_proof": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"module_name":        {Type: schema.TypeString, Required: true},
+						"input_validations":  {Type: schema.TypeString, Optional: true},
+						"output_validations": {Type: schema.TypeString, Optional: true},
+						"resources_count":    {Type: schema.TypeInt, Optional: true},
+					},
+				},
+			},
+			"validated":          {Type: schema.TypeBool, Computed: true},
+			"message":            {Type: schema.TypeString, Computed: true},
+			"flag":               {Type: schema.TypeString, Computed: true, Sensitive: true},
+			"points":             {Type: schema.TypeInt, Computed: true},
+			"timestamp":          {Type: schema.TypeString, Computed: true},
+			"proof_source":       {Type: schema.TypeString, Computed: true},
+			"validation_details": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+	}
+}
+
+// resourceFlagValidatorStateUpgradeV0 adds the plan preview, structured
+// checks and challenge definition version. Completions recorded before
+// versioning were validated against version 1 of every challenge.
+func resourceFlagValidatorStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		rawState = map[string]interface{}{}
+	}
+
+	setDefault(rawState, "fail_on_plan", false)
+	setDefault(rawState, "preview", previewText(stateBool(rawState, "validated"), stateString(rawState, "message")))
+	setDefault(rawState, "checks", []interface{}{})
+	if stateBool(rawState, "validated") {
+		setDefault(rawState, "challenge_version", 1)
+	}
+
+	return rawState, nil
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+// testProviderConfig configures a provider with its own progress and cloud
+// files.
+func testProviderConfig(t *testing.T) *ProviderConfig {
+	t.Helper()
+
+	dir := t.TempDir()
+	return newProviderConfig("alice", "", filepath.Join(dir, "progress.json"), "", filepath.Join(dir, "cloud.json"), "")
+}
+
+// planFlagValidator plans a ctfchallenge_flag_validator from state to
+// config, as terraform plan does, and returns the planned attributes.
+func planFlagValidator(t *testing.T, meta *ProviderConfig, state map[string]string, config map[string]interface{}) map[string]*terraform.ResourceAttrDiff {
+	t.Helper()
+
+	resource := resourceFlagValidator()
+	raw, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("error encoding config: %s", err)
+	}
+	rawConfig, err := ctyjson.Unmarshal(raw, resource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatalf("error decoding config: %s", err)
+	}
+	instance := &terraform.InstanceState{RawConfig: rawConfig}
+	if state != nil {
+		instance.ID = state["id"]
+		instance.Attributes = state
+	}
+
+	diff, err := resource.SimpleDiff(context.Background(), instance, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("error planning: %s", err)
+	}
+	if diff == nil {
+		return map[string]*terraform.ResourceAttrDiff{}
+	}
+	return diff.Attributes
+}
+
+func TestResourceFlagValidatorChallengeVersion(t *testing.T) {
+	const expression = `base64encode(sha256("terraformexpressionsrock"))`
+
+	cases := map[string]struct {
+		challenge  string
+		version    string
+		validated  string
+		revalidate bool
+	}{
+		"expression expert from version 1":     {challenge: "expression_expert", version: "1", validated: "true", revalidate: true},
+		"expression expert at version 2":       {challenge: "expression_expert", version: "2", validated: "true"},
+		"cryptographic compute from version 1": {challenge: "cryptographic_compute", version: "1", validated: "true", revalidate: true},
+		"unsolved at version 1":                {challenge: "expression_expert", version: "1", validated: "false"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := map[string]string{
+				"id":                tc.challenge + "-1",
+				"challenge_id":      tc.challenge,
+				"expression":        expression,
+				"validated":         tc.validated,
+				"challenge_version": tc.version,
+				"fail_on_plan":      "false",
+				"flag_storage":      "plain",
+			}
+			d := schema.TestResourceDataRaw(t, resourceFlagValidator().Schema, map[string]interface{}{
+				"challenge_id":      tc.challenge,
+				"validated":         tc.validated == "true",
+				"challenge_version": tc.version,
+			})
+			if actual := challengeVersionChanged(d); actual != tc.revalidate {
+				t.Fatalf("expected challengeVersionChanged=%t, got %t", tc.revalidate, actual)
+			}
+
+			attributes := planFlagValidator(t, testProviderConfig(t), state, map[string]interface{}{
+				"challenge_id": tc.challenge,
+				"expression":   expression,
+				"flag_storage": "plain",
+			})
+			planned := attributes["challenge_version"] != nil && attributes["challenge_version"].NewComputed
+			if planned != tc.revalidate {
+				t.Fatalf("expected the plan to re-validate=%t, got %t: %v", tc.revalidate, planned, attributes)
+			}
+		})
+	}
+}
+
+func TestResourceFlagValidatorPlanPreview(t *testing.T) {
+	driftChallenge := challenges.DriftChallengeIDs()[0]
+
+	cases := map[string]struct {
+		challenge string
+		proof     map[string]string
+		// preview is the planned preview prefix, or "" when the outcome is
+		// only known after apply
+		preview string
+	}{
+		"expression": {
+			challenge: "expression_expert",
+			proof:     map[string]string{"expression": `base64encode(sha256("terraformexpressionsrock"))`},
+			preview:   "pass: ",
+		},
+		"recovered flag": {
+			challenge: "sealed_secret",
+			proof:     map[string]string{"recovered_flag": "flag{wrong}"},
+			preview:   "fail: ",
+		},
+		"drift target": {
+			challenge: driftChallenge,
+			proof:     map[string]string{"drift_target_id": "drift-1"},
+		},
+		"flaky object": {
+			challenge: challenges.FlakyChallengeIDs()[0],
+			proof:     map[string]string{"flaky_id": "flaky-1"},
+		},
+		"puzzle box": {
+			challenge: challenges.PuzzleChainIDs()[0],
+			proof:     map[string]string{"puzzle_box_id": "box-1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			meta := testProviderConfig(t)
+			err := meta.Cloud.Update(func(file *simCloudFile) error {
+				file.DriftTargets["drift-1"] = &simDriftTarget{ID: "drift-1", ChallengeID: driftChallenge, Name: "target", Size: 1}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("error seeding the cloud: %s", err)
+			}
+
+			// A validated resource whose proof changes, so the plan re-runs
+			// validation
+			state := map[string]string{
+				"id":                tc.challenge + "-1",
+				"challenge_id":      tc.challenge,
+				"validated":         "true",
+				"message":           "solved",
+				"challenge_version": "1",
+				"fail_on_plan":      "false",
+				"flag_storage":      "plain",
+			}
+			config := map[string]interface{}{"challenge_id": tc.challenge, "flag_storage": "plain"}
+			for key, value := range tc.proof {
+				state[key] = "old"
+				config[key] = value
+			}
+
+			attributes := planFlagValidator(t, meta, state, config)
+			for _, key := range []string{"message", "preview"} {
+				attribute := attributes[key]
+				if attribute == nil {
+					t.Fatalf("expected %s to be planned, got %v", key, attributes)
+				}
+				if computed := tc.preview == ""; attribute.NewComputed != computed {
+					t.Fatalf("expected %s computed=%t, got %t", key, computed, attribute.NewComputed)
+				}
+			}
+			if preview := attributes["preview"].New; !strings.HasPrefix(preview, tc.preview) {
+				t.Fatalf("\n\nexpected:\n\n%q...\n\ngot:\n\n%q\n\n", tc.preview, preview)
+			}
+		})
+	}
+}
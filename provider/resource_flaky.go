@@ -0,0 +1,429 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+// simFlaky is a flaky object in the simulated cloud.
+type simFlaky struct {
+	ID          string `json:"id"`
+	ChallengeID string `json:"challenge_id"`
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	// Status is healthy, or partial when a failed create left the object
+	// behind
+	Status string `json:"status"`
+	// Attempt is the create attempt that made the object
+	Attempt        int    `json:"attempt"`
+	UpdateAttempts int    `json:"update_attempts"`
+	FailureMode    string `json:"failure_mode"`
+	FailOnAttempt  int    `json:"fail_on_attempt"`
+	DelaySeconds   int    `json:"delay_seconds"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// simFlakyRecord is the operation history of every flaky object with the same
+// challenge and name, so it outlives failed creates and replacements.
+type simFlakyRecord struct {
+	CreateAttempts int             `json:"create_attempts"`
+	Events         []simFlakyEvent `json:"events"`
+}
+
+type simFlakyEvent struct {
+	Operation      string `json:"operation"`
+	ObjectID       string `json:"object_id,omitempty"`
+	Outcome        string `json:"outcome"`
+	Attempt        int    `json:"attempt"`
+	Mode           string `json:"mode"`
+	DelaySeconds   int    `json:"delay_seconds"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	At             string `json:"at"`
+}
+
+// flakyHistoryLength is the number of events kept in a flaky record.
+const flakyHistoryLength = 50
+
+// flakyFailureModes are the failure modes of ctfchallenge_flaky.
+var flakyFailureModes = []string{"none", "fail_nth_create", "fail_after_delay", "fail_on_update", "partial_state"}
+
+// flakyDefaultTimeout is ctfchallenge_flaky's default create and update
+// timeout, deliberately short so slow operations need a timeouts block.
+const flakyDefaultTimeout = 3 * time.Second
+
+func (r *simFlakyRecord) record(event simFlakyEvent) {
+	r.Events = append(r.Events, event)
+	if len(r.Events) > flakyHistoryLength {
+		r.Events = r.Events[len(r.Events)-flakyHistoryLength:]
+	}
+}
+
+// history describes the recorded events for the history attribute.
+func (r *simFlakyRecord) history() []string {
+	history := make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		line := fmt.Sprintf("%s attempt %d: %s", event.Operation, event.Attempt, event.Outcome)
+		if event.ObjectID != "" {
+			line += " (" + event.ObjectID + ")"
+		}
+		history = append(history, line)
+	}
+	return history
+}
+
+// flakyRecord returns the record for a challenge and name, creating it if
+// needed.
+func (f *simCloudFile) flakyRecord(challengeID, name string) *simFlakyRecord {
+	key := challengeID + "/" + name
+	record, ok := f.FlakyRecords[key]
+	if !ok {
+		record = &simFlakyRecord{}
+		f.FlakyRecords[key] = record
+	}
+	return record
+}
+
+// flakyWait simulates an operation that takes delay seconds, giving up when
+// ctx ends first.
+func flakyWait(ctx context.Context, delay int) error {
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(delay) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func resourceFlaky() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFlakyCreate,
+		ReadContext:   resourceFlakyRead,
+		UpdateContext: resourceFlakyUpdate,
+		DeleteContext: resourceFlakyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(flakyDefaultTimeout),
+			Update: schema.DefaultTimeout(flakyDefaultTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"challenge_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The resilience challenge the object is created for",
+				ValidateFunc: validation.StringInSlice(challenges.FlakyChallengeIDs(), false),
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Name of the object. Objects with the same challenge_id and name share a history",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An arbitrary value to update in place",
+			},
+			"failure_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "none",
+				Description:  "How the object fails: none, fail_nth_create, fail_after_delay, fail_on_update or partial_state",
+				ValidateFunc: validation.StringInSlice(flakyFailureModes, false),
+			},
+			"fail_on_attempt": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Which attempt fails: the Nth create for this challenge_id and name, or the Nth update of the object for fail_on_update",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"delay_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "How long each create and update takes, in seconds",
+				ValidateFunc: validation.IntBetween(0, 300),
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "healthy, or partial when a failed create left the object behind",
+			},
+			"attempt": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The create attempt that made the object",
+			},
+			"history": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Recorded operations for this challenge_id and name, oldest first",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceFlakyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	object := &simFlaky{
+		ID:            newSimID("flaky"),
+		ChallengeID:   d.Get("challenge_id").(string),
+		Name:          d.Get("name").(string),
+		Value:         d.Get("value").(string),
+		Status:        "healthy",
+		FailureMode:   d.Get("failure_mode").(string),
+		FailOnAttempt: d.Get("fail_on_attempt").(int),
+		DelaySeconds:  d.Get("delay_seconds").(int),
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	timeout := d.Timeout(schema.TimeoutCreate)
+
+	waitErr := flakyWait(ctx, object.DelaySeconds)
+	if waitErr != nil && !errors.Is(waitErr, context.DeadlineExceeded) {
+		return diag.FromErr(waitErr)
+	}
+
+	event := simFlakyEvent{
+		Operation:      "create",
+		Mode:           object.FailureMode,
+		DelaySeconds:   object.DelaySeconds,
+		TimeoutSeconds: int(timeout.Seconds()),
+		At:             time.Now().UTC().Format(time.RFC3339),
+	}
+	var history []string
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		record := file.flakyRecord(object.ChallengeID, object.Name)
+		record.CreateAttempts++
+		event.Attempt = record.CreateAttempts
+		object.Attempt = record.CreateAttempts
+
+		failing := event.Attempt == object.FailOnAttempt
+		switch {
+		case waitErr != nil:
+			event.Outcome = "timeout"
+		case failing && (object.FailureMode == "fail_nth_create" || object.FailureMode == "fail_after_delay"):
+			event.Outcome = "failed"
+		case failing && object.FailureMode == "partial_state":
+			event.Outcome = "partial"
+			object.Status = "partial"
+		default:
+			event.Outcome = "ok"
+		}
+
+		if event.Outcome == "ok" || event.Outcome == "partial" {
+			event.ObjectID = object.ID
+			file.Flaky[object.ID] = object
+		}
+		record.record(event)
+		history = record.history()
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	switch event.Outcome {
+	case "timeout":
+		return diag.Errorf("RequestTimeout: creating %s takes %ds, longer than the %s create timeout", object.Name, object.DelaySeconds, timeout)
+	case "failed":
+		return diag.Errorf("InternalError: create attempt %d of %s failed (failure_mode = %q)", event.Attempt, object.Name, object.FailureMode)
+	}
+
+	// A partially created object is saved before failing, so Terraform
+	// records it as tainted
+	d.SetId(object.ID)
+	setProviderInstance(d, m)
+	setFlaky(d, object, history)
+
+	if event.Outcome == "partial" {
+		return diag.Errorf("PartialFailure: create attempt %d of %s failed after creating %s; the object is incomplete", event.Attempt, object.Name, object.ID)
+	}
+	return nil
+}
+
+func resourceFlakyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var object simFlaky
+	var history []string
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		o, ok := file.Flaky[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+		object = *o
+		history = file.flakyRecord(o.ChallengeID, o.Name).history()
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	setFlaky(d, &object, history)
+	return nil
+}
+
+// resourceFlakyUpdate updates the object in place. A failed update leaves the
+// object and its state unchanged, so the next plan proposes it again.
+func resourceFlakyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	delay := d.Get("delay_seconds").(int)
+	mode := d.Get("failure_mode").(string)
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	waitErr := flakyWait(ctx, delay)
+	if waitErr != nil && !errors.Is(waitErr, context.DeadlineExceeded) {
+		return diag.FromErr(waitErr)
+	}
+
+	event := simFlakyEvent{
+		Operation:      "update",
+		ObjectID:       d.Id(),
+		Mode:           mode,
+		DelaySeconds:   delay,
+		TimeoutSeconds: int(timeout.Seconds()),
+		At:             time.Now().UTC().Format(time.RFC3339),
+	}
+	var object simFlaky
+	var history []string
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		o, ok := file.Flaky[d.Id()]
+		if !ok {
+			return fmt.Errorf("flaky object %s %w", d.Id(), errSimNotFound)
+		}
+		o.UpdateAttempts++
+		event.Attempt = o.UpdateAttempts
+
+		switch {
+		case waitErr != nil:
+			event.Outcome = "timeout"
+		case mode == "fail_on_update" && event.Attempt == d.Get("fail_on_attempt").(int):
+			event.Outcome = "failed"
+		default:
+			event.Outcome = "ok"
+			o.Value = d.Get("value").(string)
+			o.FailureMode = mode
+			o.FailOnAttempt = d.Get("fail_on_attempt").(int)
+			o.DelaySeconds = delay
+		}
+
+		record := file.flakyRecord(o.ChallengeID, o.Name)
+		record.record(event)
+		history = record.history()
+		object = *o
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	switch event.Outcome {
+	case "timeout":
+		d.Partial(true)
+		return diag.Errorf("RequestTimeout: updating %s takes %ds, longer than the %s update timeout", object.Name, delay, timeout)
+	case "failed":
+		d.Partial(true)
+		return diag.Errorf("InternalError: update attempt %d of %s failed (failure_mode = %q)", event.Attempt, object.Name, mode)
+	}
+
+	setFlaky(d, &object, history)
+	return nil
+}
+
+func resourceFlakyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		o, ok := file.Flaky[d.Id()]
+		if !ok {
+			return nil
+		}
+
+		file.flakyRecord(o.ChallengeID, o.Name).record(simFlakyEvent{
+			Operation: "delete",
+			ObjectID:  o.ID,
+			Outcome:   "ok",
+			Mode:      o.FailureMode,
+			At:        time.Now().UTC().Format(time.RFC3339),
+		})
+		delete(file.Flaky, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func setFlaky(d *schema.ResourceData, o *simFlaky, history []string) {
+	d.Set("challenge_id", o.ChallengeID)
+	d.Set("name", o.Name)
+	d.Set("value", o.Value)
+	d.Set("failure_mode", o.FailureMode)
+	d.Set("fail_on_attempt", o.FailOnAttempt)
+	d.Set("delay_seconds", o.DelaySeconds)
+	d.Set("status", o.Status)
+	d.Set("attempt", o.Attempt)
+	d.Set("history", history)
+}
+
+// flakyProof builds flaky proof from an object's recorded history in the
+// simulated cloud.
+func flakyProof(m interface{}, id string) (*challenges.FlakyProof, error) {
+	var proof *challenges.FlakyProof
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		o, ok := file.Flaky[id]
+		if !ok {
+			return fmt.Errorf("flaky object %s %w", id, errSimNotFound)
+		}
+
+		proof = &challenges.FlakyProof{
+			ObjectID:    o.ID,
+			Name:        o.Name,
+			ChallengeID: o.ChallengeID,
+			Status:      o.Status,
+			History:     []challenges.FlakyEvent{},
+		}
+		for _, event := range file.flakyRecord(o.ChallengeID, o.Name).Events {
+			proof.History = append(proof.History, challenges.FlakyEvent{
+				Operation:      event.Operation,
+				ObjectID:       event.ObjectID,
+				Outcome:        event.Outcome,
+				Attempt:        event.Attempt,
+				Mode:           event.Mode,
+				DelaySeconds:   event.DelaySeconds,
+				TimeoutSeconds: event.TimeoutSeconds,
+			})
+		}
+		return nil
+	})
+	return proof, err
+}
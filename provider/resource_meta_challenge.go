@@ -3,9 +3,9 @@ package provider
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -15,6 +15,10 @@ func resourceMetaChallenge() *schema.Resource {
 		ReadContext:   resourceMetaChallengeRead,
 		UpdateContext: resourceMetaChallengeUpdate,
 		DeleteContext: resourceMetaChallengeDelete,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			upgradeStep(0, resourceMetaChallengeV0(), resourceMetaChallengeStateUpgradeV0),
+		},
 		Schema: map[string]*schema.Schema{
 			"challenge_type": {
 				Type:        schema.TypeString,
@@ -75,11 +79,30 @@ func resourceMetaChallenge() *schema.Resource {
 				Computed:    true,
 				Description: "Whether the challenge was completed successfully",
 			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
 		},
 	}
 }
 
 func resourceMetaChallengeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := resourceMetaChallengeValidate(d)
+	d.SetId(id.PrefixedUniqueId(fmt.Sprintf("meta-%s-", d.Get("challenge_type").(string))))
+	setProviderInstance(d, m)
+	return diags
+}
+
+// resourceMetaChallengeValidate checks the submitted configuration for the
+// challenge type and records the outcome.
+func resourceMetaChallengeValidate(d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	challengeType := d.Get("challenge_type").(string)
@@ -117,7 +140,6 @@ func resourceMetaChallengeCreate(ctx context.Context, d *schema.ResourceData, m
 		})
 	}
 
-	d.SetId(fmt.Sprintf("meta-%s-%d", challengeType, time.Now().Unix()))
 	return diags
 }
 
@@ -154,7 +176,7 @@ func resourceMetaChallengeRead(ctx context.Context, d *schema.ResourceData, m in
 }
 
 func resourceMetaChallengeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return resourceMetaChallengeCreate(ctx, d, m)
+	return resourceMetaChallengeValidate(d)
 }
 
 func resourceMetaChallengeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceMetaChallengeV0 is the schema of ctfchallenge_meta_challenge before
+// schema versioning was introduced.
+func resourceMetaChallengeV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"challenge_type": {Type: schema.TypeString, Required: true, ForceNew: true},
+			"configuration":  {Type: schema.TypeMap, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"metadata": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"meta_arguments_used": {Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+						"resource_count":      {Type: schema.TypeInt, Optional: true},
+						"complexity_score":    {Type: schema.TypeInt, Optional: true},
+						"notes":               {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"validation_result": {Type: schema.TypeString, Computed: true},
+			"hints_used":        {Type: schema.TypeInt, Optional: true, Default: 0},
+			"success":           {Type: schema.TypeBool, Computed: true},
+		},
+	}
+}
+
+// resourceMetaChallengeStateUpgradeV0 fills attributes that releases without
+// schema versioning could leave null.
+func resourceMetaChallengeStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		rawState = map[string]interface{}{}
+	}
+
+	setDefault(rawState, "hints_used", 0)
+	setDefault(rawState, "success", false)
+
+	return rawState, nil
+}
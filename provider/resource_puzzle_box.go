@@ -3,10 +3,13 @@ package provider
 import (
 	"context"
 	"fmt"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
 )
 
@@ -16,13 +19,87 @@ func resourcePuzzleBox() *schema.Resource {
 		ReadContext:   resourcePuzzleBoxRead,
 		UpdateContext: resourcePuzzleBoxUpdate,
 		DeleteContext: resourcePuzzleBoxDelete,
+		SchemaVersion: 2,
+		StateUpgraders: []schema.StateUpgrader{
+			upgradeStep(0, resourcePuzzleBoxV0(), resourcePuzzleBoxStateUpgradeV0),
+			upgradeStep(1, resourcePuzzleBoxV1(), resourcePuzzleBoxStateUpgradeV1),
+		},
+		CustomizeDiff: resourcePuzzleBoxCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePuzzleBoxImport,
+		},
 		Schema: map[string]*schema.Schema{
+			"puzzle_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      challenges.DefaultPuzzleType,
+				Description:  "Puzzle to play: " + strings.Join(challenges.PuzzleTypes(), ", "),
+				ValidateFunc: validation.StringInSlice(challenges.PuzzleTypes(), false),
+			},
+			"chain": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Puzzle chain the box is a stage of: " + strings.Join(challenges.PuzzleChainIDs(), ", "),
+				ValidateFunc: validation.StringInSlice(challenges.PuzzleChainIDs(), false),
+				RequiredWith: []string{"stage"},
+			},
+			"stage": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Stage of the chain the box plays, numbered from 1",
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"chain"},
+			},
+			"stage_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				Description:  "secret_output of the previous stage of the chain, which unlocks this stage",
+				RequiredWith: []string{"chain"},
+			},
 			"inputs": {
 				Type:        schema.TypeMap,
 				Required:    true,
 				Description: "Puzzle inputs to validate",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"fail_on_plan": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail the plan when the inputs do not solve the puzzle",
+			},
+			"prompt": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The puzzle to solve, including the data needed to solve it",
+			},
+			"parameters": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The puzzle's data, for use in expressions",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"seed": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Seed the puzzle was generated from, derived from puzzle_type, player_name and the provider's event_salt, and for chain stages from the chain's earlier stages",
+			},
+			"input_schema": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The inputs the puzzle reads",
+			},
+			"difficulty": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Difficulty of the puzzle",
+			},
+			"preview": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Plan-time puzzle outcome, prefixed with pass: or fail:",
+			},
 			"solved": {
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -39,30 +116,367 @@ func resourcePuzzleBox() *schema.Resource {
 				Sensitive:   true,
 				Description: "Secret revealed when puzzle is solved",
 			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
 		},
 	}
 }
 
 func resourcePuzzleBoxCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := resourcePuzzleBoxSolve(d, m)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(id.PrefixedUniqueId("puzzle-"))
+	setProviderInstance(d, m)
+	return append(diags, recordProgress(m, d.Id(), puzzleBoxRecord(d))...)
+}
+
+// puzzleBoxRecord captures the puzzle outcome persisted for import. Chain
+// stages also record the chain as the challenge, and hashes of the stage's
+// key and output, which later stages and the chain challenge check.
+func puzzleBoxRecord(d *schema.ResourceData) *progressRecord {
+	record := &progressRecord{
+		ResourceType: "ctfchallenge_puzzle_box",
+		Validated:    d.Get("solved").(bool),
+		Message:      d.Get("message").(string),
+		Attributes: map[string]string{
+			"puzzle_type": d.Get("puzzle_type").(string),
+		},
+	}
+
+	if chain := d.Get("chain").(string); chain != "" {
+		record.ChallengeID = chain
+		record.Attributes["stage"] = strconv.Itoa(d.Get("stage").(int))
+		if key := d.Get("stage_key").(string); key != "" {
+			record.Attributes["key_sha256"] = challenges.ChainHash(key)
+		}
+		if record.Validated {
+			record.Attributes["output_sha256"] = challenges.ChainHash(d.Get("secret_output").(string))
+		}
+	}
+	return record
+}
+
+// resourcePuzzleBoxSolve checks the puzzle inputs against the player's
+// instance of the puzzle and records the outcome.
+func resourcePuzzleBoxSolve(d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	inputs := d.Get("inputs").(map[string]interface{})
-	solved, message := challenges.ValidatePuzzleInput(inputs)
+	stage, err := puzzleBoxStage(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	puzzle, seed, err := playerPuzzle(m, d.Get("puzzle_type").(string), stage)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	lock, err := stageLock(m, stage)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
+	for key, value := range puzzleDetails(puzzle, seed) {
+		d.Set(key, value)
+	}
+
+	solved, message := false, lock
+	if lock != "" {
+		lockDetails(d.Set, lock)
+	} else {
+		solved, message = puzzle.Check(d.Get("inputs").(map[string]interface{}))
+	}
 	d.Set("solved", solved)
 	d.Set("message", message)
+	d.Set("preview", previewText(solved, message))
+
+	if !solved {
+		d.Set("secret_output", "")
+		return diags
+	}
+
+	detail := "Check the secret_output for your reward"
+	secret := puzzle.Flag()
+	if stage != nil {
+		config := providerConfig(m)
+		if stage.stage < len(stage.chain.Stages) {
+			secret = stage.chain.StageOutput(stage.stage, config.PlayerName, config.EventSalt)
+			detail = fmt.Sprintf("The secret_output is the stage_key of stage %d of %s", stage.stage+1, stage.chain.ID)
+		} else {
+			detail = fmt.Sprintf("Every stage of %s is solved. Validate the chain with a ctfchallenge_flag_validator whose puzzle_box_id is this box's ID for the %s bonus",
+				stage.chain.ID, stage.chain.ID)
+		}
+	}
+	d.Set("secret_output", secret)
+	return append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Puzzle Solved!",
+		Detail:   detail,
+	})
+}
+
+// puzzleStage is the stage of a puzzle chain a puzzle box plays.
+type puzzleStage struct {
+	chain *challenges.PuzzleChain
+	stage int
+	key   string
+}
+
+// puzzleBoxStage returns the chain stage a puzzle box plays, or nil when the
+// box is not a stage of a chain. The box's puzzle_type must be the stage's.
+func puzzleBoxStage(d resourceGetter) (*puzzleStage, error) {
+	chainID := d.Get("chain").(string)
+	if chainID == "" {
+		return nil, nil
+	}
+	chain, ok := challenges.PuzzleChains[chainID]
+	if !ok {
+		return nil, fmt.Errorf("unknown chain %q (expected one of %s)", chainID, strings.Join(challenges.PuzzleChainIDs(), ", "))
+	}
+
+	stage := &puzzleStage{chain: chain, stage: d.Get("stage").(int), key: d.Get("stage_key").(string)}
+	puzzleType, ok := chain.StageType(stage.stage)
+	if !ok {
+		return nil, fmt.Errorf("%s has stages 1 to %d, not %d", chain.ID, len(chain.Stages), stage.stage)
+	}
+	if current := d.Get("puzzle_type").(string); current != puzzleType {
+		return nil, fmt.Errorf("stage %d of %s is a %s puzzle, not %s: set puzzle_type = %q", stage.stage, chain.ID, puzzleType, current, puzzleType)
+	}
+	if stage.stage == 1 && stage.key != "" {
+		return nil, fmt.Errorf("stage 1 of %s is unlocked from the start: remove stage_key", chain.ID)
+	}
+	return stage, nil
+}
+
+// stageLock explains why a chain stage is locked, or returns "" when it is
+// not. A stage after the first is locked until its stage_key is the
+// secret_output of the previous stage and the player has solved that stage.
+func stageLock(m interface{}, stage *puzzleStage) (string, error) {
+	if stage == nil {
+		return "", nil
+	}
+	config := providerConfig(m)
+	if config.Progress == nil {
+		return "", fmt.Errorf("puzzle chains record solved stages in the progress file, which is disabled: set the provider's progress_file")
+	}
+	if stage.stage == 1 {
+		return "", nil
+	}
+
+	previous := stage.stage - 1
+	if stage.key != stage.chain.StageKey(stage.stage, config.PlayerName, config.EventSalt) {
+		return fmt.Sprintf("Stage %d of %s is locked: stage_key is not the secret_output of stage %d", stage.stage, stage.chain.ID, previous), nil
+	}
 
-	if solved {
-		d.Set("secret_output", "flag{xor_puzzl3_s0lv3d}")
-		diags = append(diags, diag.Diagnostic{
-			Severity: diag.Warning,
-			Summary:  "Puzzle Solved!",
-			Detail:   "Check the secret_output for your reward",
+	_, record, err := config.Progress.FindLatestWith("ctfchallenge_puzzle_box", config.PlayerName, stage.chain.ID, map[string]string{
+		"stage":         strconv.Itoa(previous),
+		"output_sha256": challenges.ChainHash(stage.key),
+	})
+	if err != nil {
+		return "", err
+	}
+	if record == nil {
+		return fmt.Sprintf("Stage %d of %s is locked: %s has no solved stage %d in recorded progress", stage.stage, stage.chain.ID, config.PlayerName, previous), nil
+	}
+	return "", nil
+}
+
+// puzzleChainProof reads the player's progress through the chain a puzzle
+// box is a stage of from recorded progress, taking the latest solved record
+// of each stage.
+func puzzleChainProof(m interface{}, id string) (*challenges.ChainProof, error) {
+	config := providerConfig(m)
+	record, err := config.Progress.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.ResourceType != "ctfchallenge_puzzle_box" {
+		return nil, fmt.Errorf("no recorded progress for puzzle box %s", id)
+	}
+	if record.Player != config.PlayerName {
+		return nil, fmt.Errorf("puzzle box %s was played by %q, not %q", id, record.Player, config.PlayerName)
+	}
+
+	proof := &challenges.ChainProof{
+		BoxID:  id,
+		Chain:  record.ChallengeID,
+		Player: record.Player,
+		Stages: []challenges.ChainStageProof{},
+	}
+	chain, ok := challenges.PuzzleChains[record.ChallengeID]
+	if !ok {
+		return proof, nil
+	}
+
+	for n := range chain.Stages {
+		stageID, stage, err := config.Progress.FindLatestWith("ctfchallenge_puzzle_box", record.Player, chain.ID, map[string]string{
+			"stage": strconv.Itoa(n + 1),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if stage == nil {
+			continue
+		}
+		proof.Stages = append(proof.Stages, challenges.ChainStageProof{
+			Stage:      n + 1,
+			BoxID:      stageID,
+			PuzzleType: stage.Attributes["puzzle_type"],
+			KeyHash:    stage.Attributes["key_sha256"],
+			OutputHash: stage.Attributes["output_sha256"],
 		})
 	}
+	return proof, nil
+}
 
-	d.SetId(fmt.Sprintf("puzzle-%d", time.Now().Unix()))
-	return diags
+// lockDetails hides the puzzle of a locked stage behind the lock message.
+func lockDetails(set func(key string, value interface{}) error, lock string) error {
+	for key, value := range map[string]interface{}{
+		"prompt":       lock,
+		"parameters":   map[string]interface{}{},
+		"input_schema": "",
+	} {
+		if err := set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playerPuzzle generates the configured player's instance of a puzzle type,
+// or of a chain stage when stage is not nil, and returns it with its seed.
+func playerPuzzle(m interface{}, puzzleType string, stage *puzzleStage) (challenges.Puzzle, string, error) {
+	config := providerConfig(m)
+	salt := config.EventSalt
+	if stage != nil {
+		salt = stage.chain.StageSalt(stage.stage, config.PlayerName, salt)
+	}
+	return generatePuzzle(puzzleType, config.PlayerName, salt)
+}
+
+// generatePuzzle generates a player's instance of a puzzle type and returns
+// it with its seed, in hex.
+func generatePuzzle(puzzleType, player, salt string) (challenges.Puzzle, string, error) {
+	puzzle, err := challenges.GeneratePuzzle(puzzleType, player, salt)
+	if err != nil {
+		return nil, "", err
+	}
+	return puzzle, fmt.Sprintf("%016x", uint64(challenges.PuzzleSeed(puzzleType, player, salt))), nil
+}
+
+// puzzleDetails are the attributes that describe a puzzle instance.
+func puzzleDetails(puzzle challenges.Puzzle, seed string) map[string]interface{} {
+	return map[string]interface{}{
+		"prompt":       puzzle.Prompt(),
+		"parameters":   puzzle.Parameters(),
+		"input_schema": puzzle.InputSchema(),
+		"difficulty":   puzzle.Difficulty(),
+		"seed":         seed,
+	}
+}
+
+// resourcePuzzleBoxCustomizeDiff describes the player's puzzle and checks
+// the puzzle inputs during plan when they are known.
+func resourcePuzzleBoxCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !configKnown(d, "puzzle_type", "chain", "stage") {
+		for _, key := range []string{"prompt", "parameters", "input_schema", "difficulty", "seed", "solved", "message", "preview", "secret_output"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	stage, err := puzzleBoxStage(d)
+	if err != nil {
+		return err
+	}
+	puzzle, seed, err := playerPuzzle(m, d.Get("puzzle_type").(string), stage)
+	if err != nil {
+		// Rejected by the attribute's validation
+		return nil
+	}
+
+	// A new player_name or event_salt changes the puzzle even when the
+	// inputs stay the same
+	if d.Id() != "" && !d.HasChanges("inputs", "puzzle_type", "chain", "stage", "stage_key") && d.Get("seed").(string) == seed {
+		return nil
+	}
+
+	if d.Id() != "" {
+		if err := d.SetNewComputed("secret_output"); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range puzzleDetails(puzzle, seed) {
+		if err := d.SetNew(key, value); err != nil {
+			return err
+		}
+	}
+
+	// A stage_key wired from the previous stage is only known once that
+	// stage is applied
+	if stage != nil && !configKnown(d, "stage_key") {
+		for _, key := range []string{"prompt", "parameters", "input_schema", "solved", "message", "preview"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	lock, err := stageLock(m, stage)
+	if err != nil {
+		return err
+	}
+	if lock != "" {
+		if d.Get("fail_on_plan").(bool) {
+			return fmt.Errorf("puzzle would not be solved: %s", lock)
+		}
+		if err := lockDetails(d.SetNew, lock); err != nil {
+			return err
+		}
+		if err := d.SetNew("solved", false); err != nil {
+			return err
+		}
+		if err := d.SetNew("message", lock); err != nil {
+			return err
+		}
+		return d.SetNew("preview", previewText(false, lock))
+	}
+
+	if !configKnown(d, "inputs") {
+		for _, key := range []string{"solved", "message", "preview"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	inputs := d.Get("inputs").(map[string]interface{})
+	solved, message := puzzle.Check(inputs)
+
+	if d.Get("fail_on_plan").(bool) && !solved {
+		return fmt.Errorf("puzzle would not be solved: %s", message)
+	}
+
+	if err := d.SetNew("solved", solved); err != nil {
+		return err
+	}
+	if err := d.SetNew("message", message); err != nil {
+		return err
+	}
+	return d.SetNew("preview", previewText(solved, message))
 }
 
 func resourcePuzzleBoxRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -70,10 +484,65 @@ func resourcePuzzleBoxRead(ctx context.Context, d *schema.ResourceData, m interf
 }
 
 func resourcePuzzleBoxUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return resourcePuzzleBoxCreate(ctx, d, m)
+	diags := resourcePuzzleBoxSolve(d, m)
+	if diags.HasError() {
+		d.Partial(true)
+		return diags
+	}
+	return append(diags, recordProgress(m, d.Id(), puzzleBoxRecord(d))...)
 }
 
 func resourcePuzzleBoxDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := forgetProgress(m, d.Id())
 	d.SetId("")
-	return nil
+	return diags
+}
+
+// resourcePuzzleBoxImport imports a puzzle box by its ID (puzzle-<suffix>).
+// The outcome, puzzle_type and chain stage are restored from recorded
+// progress when available; the inputs are checked again on the next apply either way.
+func resourcePuzzleBoxImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if !strings.HasPrefix(d.Id(), "puzzle-") {
+		return nil, fmt.Errorf("cannot import %q: expected an ID of the form puzzle-<suffix>", d.Id())
+	}
+
+	record, err := importRecord(m, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("fail_on_plan", false)
+	setProviderInstance(d, m)
+
+	puzzleType := challenges.DefaultPuzzleType
+	if record != nil && record.Attributes["puzzle_type"] != "" {
+		puzzleType = record.Attributes["puzzle_type"]
+	}
+	d.Set("puzzle_type", puzzleType)
+
+	// stage_key is not recorded; the next apply checks it again
+	if record != nil && record.ChallengeID != "" {
+		stage, _ := strconv.Atoi(record.Attributes["stage"])
+		d.Set("chain", record.ChallengeID)
+		d.Set("stage", stage)
+	}
+	stage, err := puzzleBoxStage(d)
+	if err != nil {
+		return nil, fmt.Errorf("cannot import %q: %w", d.Id(), err)
+	}
+	puzzle, seed, err := playerPuzzle(m, puzzleType, stage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot import %q: %w", d.Id(), err)
+	}
+	for key, value := range puzzleDetails(puzzle, seed) {
+		d.Set(key, value)
+	}
+
+	if record != nil {
+		d.Set("solved", record.Validated)
+		d.Set("message", record.Message)
+		d.Set("preview", previewText(record.Validated, record.Message))
+	}
+
+	return []*schema.ResourceData{d}, nil
 }
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+// resourcePuzzleBoxV0 is the schema of ctfchallenge_puzzle_box before schema
+// versioning was introduced.
+func resourcePuzzleBoxV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"inputs":        {Type: schema.TypeMap, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"solved":        {Type: schema.TypeBool, Computed: true},
+			"message":       {Type: schema.TypeString, Computed: true},
+			"secret_output": {Type: schema.TypeString, Computed: true, Sensitive: true},
+		},
+	}
+}
+
+// resourcePuzzleBoxStateUpgradeV0 adds the plan preview attributes.
+func resourcePuzzleBoxStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		rawState = map[string]interface{}{}
+	}
+
+	setDefault(rawState, "fail_on_plan", false)
+	setDefault(rawState, "preview", previewText(stateBool(rawState, "solved"), stateString(rawState, "message")))
+
+	return rawState, nil
+}
+
+// resourcePuzzleBoxV1 is the schema of ctfchallenge_puzzle_box before
+// puzzle_type was introduced, when every box played the XOR puzzle.
+func resourcePuzzleBoxV1() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"inputs":            {Type: schema.TypeMap, Required: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"fail_on_plan":      {Type: schema.TypeBool, Optional: true, Default: false},
+			"preview":           {Type: schema.TypeString, Computed: true},
+			"solved":            {Type: schema.TypeBool, Computed: true},
+			"message":           {Type: schema.TypeString, Computed: true},
+			"secret_output":     {Type: schema.TypeString, Computed: true, Sensitive: true},
+			"provider_instance": {Type: schema.TypeString, Computed: true},
+			"player_name":       {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// resourcePuzzleBoxStateUpgradeV1 records that existing boxes play the XOR
+// puzzle and describes it.
+func resourcePuzzleBoxStateUpgradeV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		rawState = map[string]interface{}{}
+	}
+
+	puzzle := challenges.Puzzles["xor"]
+	setDefault(rawState, "puzzle_type", puzzle.Type())
+	setDefault(rawState, "prompt", puzzle.Prompt())
+	setDefault(rawState, "input_schema", puzzle.InputSchema())
+	setDefault(rawState, "difficulty", puzzle.Difficulty())
+
+	return rawState, nil
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// simBucketName matches the bucket names accepted by the simulated cloud.
+var simBucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+func resourceSimBucket() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSimBucketCreate,
+		ReadContext:   resourceSimBucketRead,
+		UpdateContext: resourceSimBucketUpdate,
+		DeleteContext: resourceSimBucketDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				Description: "Name of the bucket, unique across the simulated cloud. 3 to 63 lowercase letters, " +
+					"numbers, dots and hyphens, starting and ending with a letter or number",
+				ValidateFunc: validation.StringMatch(simBucketName, "must be 3 to 63 lowercase letters, numbers, dots and hyphens, starting and ending with a letter or number"),
+			},
+			"versioning": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether object versioning is enabled",
+			},
+			"tags": simTagsSchema(),
+			"arn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ARN-style identifier of the bucket",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the bucket was created (RFC3339)",
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceSimBucketCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	bucket := &simBucket{
+		Name:       d.Get("name").(string),
+		Versioning: d.Get("versioning").(bool),
+		Tags:       simTags(d),
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+	}
+
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if _, exists := file.Buckets[bucket.Name]; exists {
+			return fmt.Errorf("BucketAlreadyExists: a bucket named %s already exists in the simulated cloud", bucket.Name)
+		}
+		file.Buckets[bucket.Name] = bucket
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(bucket.Name)
+	setProviderInstance(d, m)
+	return resourceSimBucketRead(ctx, d, m)
+}
+
+func resourceSimBucketRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var bucket simBucket
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		b, ok := file.Buckets[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+		bucket = *b
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", bucket.Name)
+	d.Set("versioning", bucket.Versioning)
+	d.Set("tags", bucket.Tags)
+	d.Set("arn", fmt.Sprintf("arn:sim:storage:::%s", bucket.Name))
+	d.Set("created_at", bucket.CreatedAt)
+	return nil
+}
+
+func resourceSimBucketUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		bucket, ok := file.Buckets[d.Id()]
+		if !ok {
+			return fmt.Errorf("bucket %s %w", d.Id(), errSimNotFound)
+		}
+		bucket.Versioning = d.Get("versioning").(bool)
+		bucket.Tags = simTags(d)
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceSimBucketRead(ctx, d, m)
+}
+
+func resourceSimBucketDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		delete(file.Buckets, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceSimInstance() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSimInstanceCreate,
+		ReadContext:   resourceSimInstanceRead,
+		UpdateContext: resourceSimInstanceUpdate,
+		DeleteContext: resourceSimInstanceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"subnet_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the subnet to launch the instance in",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the instance",
+			},
+			"instance_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Instance type: sim.micro, sim.small, sim.medium or sim.large",
+				ValidateFunc: validation.StringInSlice(simInstanceTypes, false),
+			},
+			"tags": simTagsSchema(),
+			"network_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the network the instance's subnet belongs to",
+			},
+			"private_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Private IP address allocated from the subnet",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State of the instance",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the instance was created (RFC3339)",
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceSimInstanceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	instance := &simInstance{
+		ID:           newSimID("i"),
+		SubnetID:     d.Get("subnet_id").(string),
+		Name:         d.Get("name").(string),
+		InstanceType: d.Get("instance_type").(string),
+		State:        "running",
+		Tags:         simTags(d),
+		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+	}
+
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		subnet, ok := file.Subnets[instance.SubnetID]
+		if !ok {
+			return fmt.Errorf("InvalidSubnetID: subnet %s does not exist", instance.SubnetID)
+		}
+		ip, err := file.allocateIP(subnet)
+		if err != nil {
+			return err
+		}
+		instance.PrivateIP = ip
+		file.Instances[instance.ID] = instance
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(instance.ID)
+	setProviderInstance(d, m)
+	return resourceSimInstanceRead(ctx, d, m)
+}
+
+func resourceSimInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var instance simInstance
+	var networkID string
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		i, ok := file.Instances[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+		instance = *i
+		if subnet, ok := file.Subnets[i.SubnetID]; ok {
+			networkID = subnet.NetworkID
+		}
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("subnet_id", instance.SubnetID)
+	d.Set("name", instance.Name)
+	d.Set("instance_type", instance.InstanceType)
+	d.Set("tags", instance.Tags)
+	d.Set("network_id", networkID)
+	d.Set("private_ip", instance.PrivateIP)
+	d.Set("state", instance.State)
+	d.Set("created_at", instance.CreatedAt)
+	return nil
+}
+
+// resourceSimInstanceUpdate resizes or renames an instance in place.
+func resourceSimInstanceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		instance, ok := file.Instances[d.Id()]
+		if !ok {
+			return fmt.Errorf("instance %s %w", d.Id(), errSimNotFound)
+		}
+		instance.Name = d.Get("name").(string)
+		instance.InstanceType = d.Get("instance_type").(string)
+		instance.Tags = simTags(d)
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceSimInstanceRead(ctx, d, m)
+}
+
+func resourceSimInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		delete(file.Instances, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceSimNetwork() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSimNetworkCreate,
+		ReadContext:   resourceSimNetworkRead,
+		UpdateContext: resourceSimNetworkUpdate,
+		DeleteContext: resourceSimNetworkDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the network",
+			},
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "IPv4 CIDR block of the network, between /16 and /28",
+				ValidateFunc: validation.IsCIDRNetwork(16, 28),
+			},
+			"tags": simTagsSchema(),
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the network was created (RFC3339)",
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceSimNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	network := &simNetwork{
+		ID:        newSimID("net"),
+		Name:      d.Get("name").(string),
+		CIDRBlock: d.Get("cidr_block").(string),
+		Tags:      simTags(d),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		file.Networks[network.ID] = network
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(network.ID)
+	setProviderInstance(d, m)
+	return resourceSimNetworkRead(ctx, d, m)
+}
+
+func resourceSimNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var network simNetwork
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		n, ok := file.Networks[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+		network = *n
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("name", network.Name)
+	d.Set("cidr_block", network.CIDRBlock)
+	d.Set("tags", network.Tags)
+	d.Set("created_at", network.CreatedAt)
+	return nil
+}
+
+func resourceSimNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		network, ok := file.Networks[d.Id()]
+		if !ok {
+			return fmt.Errorf("network %s %w", d.Id(), errSimNotFound)
+		}
+		network.Name = d.Get("name").(string)
+		network.Tags = simTags(d)
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceSimNetworkRead(ctx, d, m)
+}
+
+// resourceSimNetworkDelete refuses to delete a network that still has
+// subnets, like a real cloud would.
+func resourceSimNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if _, ok := file.Networks[d.Id()]; !ok {
+			return nil
+		}
+		if subnets := file.subnetsIn(d.Id()); len(subnets) > 0 {
+			return fmt.Errorf("DependencyViolation: network %s has dependent subnets: %s", d.Id(), strings.Join(subnets, ", "))
+		}
+		delete(file.Networks, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
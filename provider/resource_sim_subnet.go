@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceSimSubnet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSimSubnetCreate,
+		ReadContext:   resourceSimSubnetRead,
+		UpdateContext: resourceSimSubnetUpdate,
+		DeleteContext: resourceSimSubnetDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSimResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"network_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the network the subnet belongs to",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the subnet",
+			},
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "IPv4 CIDR block of the subnet, inside the network's CIDR block and not overlapping its other subnets",
+				ValidateFunc: validation.IsCIDRNetwork(16, 28),
+			},
+			"tags": simTagsSchema(),
+			"available_ip_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of addresses still free for instances",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the subnet was created (RFC3339)",
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceSimSubnetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	subnet := &simSubnet{
+		ID:        newSimID("subnet"),
+		NetworkID: d.Get("network_id").(string),
+		Name:      d.Get("name").(string),
+		CIDRBlock: d.Get("cidr_block").(string),
+		Tags:      simTags(d),
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if err := checkSubnetPlacement(file, subnet); err != nil {
+			return err
+		}
+		file.Subnets[subnet.ID] = subnet
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(subnet.ID)
+	setProviderInstance(d, m)
+	return resourceSimSubnetRead(ctx, d, m)
+}
+
+// checkSubnetPlacement enforces that a subnet sits inside an existing
+// network's CIDR block without overlapping the network's other subnets.
+func checkSubnetPlacement(file *simCloudFile, subnet *simSubnet) error {
+	network, ok := file.Networks[subnet.NetworkID]
+	if !ok {
+		return fmt.Errorf("InvalidNetworkID: network %s does not exist", subnet.NetworkID)
+	}
+
+	_, networkCIDR, err := net.ParseCIDR(network.CIDRBlock)
+	if err != nil {
+		return fmt.Errorf("network %s has an invalid cidr_block %q: %w", network.ID, network.CIDRBlock, err)
+	}
+	_, subnetCIDR, err := net.ParseCIDR(subnet.CIDRBlock)
+	if err != nil {
+		return err
+	}
+
+	if !cidrContains(networkCIDR, subnetCIDR) {
+		return fmt.Errorf("InvalidSubnetRange: %s is not inside network %s (%s)", subnet.CIDRBlock, network.ID, network.CIDRBlock)
+	}
+
+	for _, id := range file.subnetsIn(network.ID) {
+		other := file.Subnets[id]
+		_, otherCIDR, err := net.ParseCIDR(other.CIDRBlock)
+		if err == nil && cidrOverlaps(subnetCIDR, otherCIDR) {
+			return fmt.Errorf("InvalidSubnetConflict: %s overlaps subnet %s (%s)", subnet.CIDRBlock, other.ID, other.CIDRBlock)
+		}
+	}
+	return nil
+}
+
+func resourceSimSubnetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var subnet simSubnet
+	var available int
+	err := simCloudFrom(m).View(func(file *simCloudFile) error {
+		s, ok := file.Subnets[d.Id()]
+		if !ok {
+			return errSimNotFound
+		}
+		subnet = *s
+		available = file.availableIPs(s)
+		return nil
+	})
+	if err == errSimNotFound {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("network_id", subnet.NetworkID)
+	d.Set("name", subnet.Name)
+	d.Set("cidr_block", subnet.CIDRBlock)
+	d.Set("tags", subnet.Tags)
+	d.Set("available_ip_count", available)
+	d.Set("created_at", subnet.CreatedAt)
+	return nil
+}
+
+func resourceSimSubnetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		subnet, ok := file.Subnets[d.Id()]
+		if !ok {
+			return fmt.Errorf("subnet %s %w", d.Id(), errSimNotFound)
+		}
+		subnet.Name = d.Get("name").(string)
+		subnet.Tags = simTags(d)
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceSimSubnetRead(ctx, d, m)
+}
+
+// resourceSimSubnetDelete refuses to delete a subnet that still has
+// instances.
+func resourceSimSubnetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := simCloudFrom(m).Update(func(file *simCloudFile) error {
+		if _, ok := file.Subnets[d.Id()]; !ok {
+			return nil
+		}
+		if instances := file.instancesIn(d.Id()); len(instances) > 0 {
+			return fmt.Errorf("DependencyViolation: subnet %s has dependent instances: %s", d.Id(), strings.Join(instances, ", "))
+		}
+		delete(file.Subnets, d.Id())
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
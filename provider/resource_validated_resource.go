@@ -2,11 +2,17 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceValidatedResource() *schema.Resource {
@@ -15,6 +21,14 @@ func resourceValidatedResource() *schema.Resource {
 		ReadContext:   resourceValidatedResourceRead,
 		UpdateContext: resourceValidatedResourceUpdate,
 		DeleteContext: resourceValidatedResourceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceValidatedResourceImport,
+		},
+		CustomizeDiff: resourceValidatedResourceCustomizeDiff,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			upgradeStep(0, resourceValidatedResourceV0(), resourceValidatedResourceStateUpgradeV0),
+		},
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -63,6 +77,67 @@ func resourceValidatedResource() *schema.Resource {
 							Default:     false,
 							Description: "Whether output is validated",
 						},
+						"error_message": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "Message explaining what a valid value looks like",
+						},
+					},
+				},
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "A rule the provider evaluates against one of the resource's values during plan and apply",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Rule type: regex, length, enum, cidr, semver or json_schema",
+							ValidateFunc: validation.StringInSlice(validationRuleTypes, false),
+						},
+						"attribute": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "required_value",
+							Description:  "Attribute the rule checks: required_value, optional_value or name",
+							ValidateFunc: validation.StringInSlice(validationRuleAttributes, false),
+						},
+						"pattern": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Regular expression the value must match (regex)",
+						},
+						"min_length": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Minimum length in characters (length)",
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"max_length": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  "Maximum length in characters, 0 for no maximum (length)",
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"values": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Allowed values (enum)",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"schema": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "JSON Schema the value, parsed as JSON, must match (json_schema)",
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Message reported when the value does not satisfy the rule",
+						},
 					},
 				},
 			},
@@ -97,27 +172,112 @@ func resourceValidatedResource() *schema.Resource {
 				Computed:    true,
 				Description: "Quality score of the resource",
 			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
 		},
 	}
 }
 
 func resourceValidatedResourceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	diags := resourceValidatedResourceValidate(d)
+	if diags.HasError() {
+		return diags
+	}
+
+	// computed_id doubles as the resource ID and stays stable across updates
+	computedID := id.PrefixedUniqueId(fmt.Sprintf("validated-%s-", name))
+	d.Set("computed_id", computedID)
+	d.SetId(computedID)
+	setProviderInstance(d, m)
+
+	return append(diags, recordProgress(m, d.Id(), validatedResourceRecord(d))...)
+}
+
+// validatedResourceRecord captures the validation outcome persisted for import
+// and, for ctfchallenge_validation_helper, each rule's type, attribute,
+// message and result as the provider evaluated them.
+func validatedResourceRecord(d *schema.ResourceData) *progressRecord {
+	outcome := evaluateValidatedResource(d)
+	attributes := map[string]string{
+		"name":           d.Get("name").(string),
+		"quality_score":  strconv.Itoa(outcome.qualityScore),
+		"required_value": d.Get("required_value").(string),
+		"optional_value": d.Get("optional_value").(string),
+		"rules":          strconv.Itoa(len(outcome.rules)),
+	}
+	for i, rule := range outcome.rules {
+		prefix := fmt.Sprintf("rule.%d.", i)
+		attributes[prefix+"type"] = rule.Type
+		attributes[prefix+"attribute"] = rule.Attribute
+		attributes[prefix+"message"] = rule.Message
+		attributes[prefix+"result"] = outcome.results[i]
+	}
+
+	return &progressRecord{
+		ResourceType: "ctfchallenge_validated_resource",
+		Validated:    outcome.validated,
+		Timestamp:    d.Get("validation_timestamp").(string),
+		Attributes:   attributes,
+	}
+}
+
+// resourceValidatedResourceValidate evaluates the rules against the resource
+// values, reporting each rule that fails at the attribute it checks. The
+// validation timestamp only moves when the validation outcome changes.
+func resourceValidatedResourceValidate(d *schema.ResourceData) diag.Diagnostics {
 	var diags diag.Diagnostics
 
 	name := d.Get("name").(string)
-	requiredValue := d.Get("required_value").(string)
 
-	// Simulate validation
-	validated := len(requiredValue) > 0
+	for _, rule := range expandValidationRules(d.Get("rule").([]interface{})) {
+		if argument, err := rule.checkDefinition(); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid validation rule",
+				Detail:        fmt.Sprintf("%s: %s", rule, err),
+				AttributePath: cty.GetAttrPath("rule").IndexInt(rule.Index).GetAttr(argument),
+			})
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	outcome := evaluateValidatedResource(d)
+	for _, failure := range outcome.failures {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Validation %s failed", failure.rule),
+			Detail:        fmt.Sprintf("%s: %s", failure.rule.Attribute, failure.reason),
+			AttributePath: cty.GetAttrPath(failure.rule.Attribute),
+		})
+	}
+	if diags.HasError() {
+		return diags
+	}
+	validated := outcome.validated
+
+	wasValidated, _ := d.GetChange("validated")
+	validatedAt, _ := d.GetChange("validation_timestamp")
 
 	// Set computed values that can be referenced with 'self'
-	computedID := fmt.Sprintf("validated-%s-%d", name, time.Now().Unix())
-	d.Set("computed_id", computedID)
 	d.Set("state", "active")
 	d.Set("validated", validated)
-	d.Set("validation_timestamp", time.Now().UTC().Format(time.RFC3339))
+	if wasValidated.(bool) != validated || validatedAt.(string) == "" {
+		d.Set("validation_timestamp", time.Now().UTC().Format(time.RFC3339))
+	}
 	d.Set("solved", validated)
-	d.Set("quality_score", len(requiredValue)*10)
+	d.Set("quality_score", outcome.qualityScore)
 
 	if validated {
 		diags = append(diags, diag.Diagnostic{
@@ -127,19 +287,100 @@ func resourceValidatedResourceCreate(ctx context.Context, d *schema.ResourceData
 		})
 	}
 
-	d.SetId(computedID)
 	return diags
 }
 
+// resourceValidatedResourceCustomizeDiff evaluates the rules during plan when
+// the values are known, so rule failures and the new quality_score show up
+// before apply.
+func resourceValidatedResourceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !configKnown(d, "rule") {
+		return nil
+	}
+
+	var errs []error
+	for _, rule := range expandValidationRules(d.Get("rule").([]interface{})) {
+		if argument, err := rule.checkDefinition(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", rule, argument, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if !configKnown(d, "name", "required_value", "optional_value") {
+		if d.Id() != "" {
+			for _, key := range []string{"validated", "solved", "quality_score"} {
+				if err := d.SetNewComputed(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	outcome := evaluateValidatedResource(d)
+	for _, failure := range outcome.failures {
+		errs = append(errs, fmt.Errorf("%s failed on %s: %s", failure.rule, failure.rule.Attribute, failure.reason))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := d.SetNew("validated", outcome.validated); err != nil {
+		return err
+	}
+	if err := d.SetNew("solved", outcome.validated); err != nil {
+		return err
+	}
+	return d.SetNew("quality_score", outcome.qualityScore)
+}
+
 func resourceValidatedResourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	return nil
 }
 
 func resourceValidatedResourceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return resourceValidatedResourceCreate(ctx, d, m)
+	diags := resourceValidatedResourceValidate(d)
+	if diags.HasError() {
+		d.Partial(true)
+		return diags
+	}
+	return append(diags, recordProgress(m, d.Id(), validatedResourceRecord(d))...)
 }
 
 func resourceValidatedResourceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := forgetProgress(m, d.Id())
 	d.SetId("")
-	return nil
+	return diags
+}
+
+// resourceValidatedResourceImport imports a resource by its ID
+// (validated-<name>-<suffix>). Computed attributes are restored from recorded
+// progress when available; the values are validated again on the next apply.
+func resourceValidatedResourceImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	base := trimUniqueSuffix(d.Id())
+	name := strings.TrimPrefix(base, "validated-")
+	if base == d.Id() || !strings.HasPrefix(base, "validated-") || name == "" {
+		return nil, fmt.Errorf("cannot import %q: expected an ID of the form validated-<name>-<suffix>", d.Id())
+	}
+
+	record, err := importRecord(m, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("name", name)
+	d.Set("computed_id", d.Id())
+	setProviderInstance(d, m)
+	if record != nil {
+		score, _ := strconv.Atoi(record.Attributes["quality_score"])
+		d.Set("state", "active")
+		d.Set("validated", record.Validated)
+		d.Set("solved", record.Validated)
+		d.Set("validation_timestamp", record.Timestamp)
+		d.Set("quality_score", score)
+	}
+
+	return []*schema.ResourceData{d}, nil
 }
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceValidatedResourceV0 is the schema of ctfchallenge_validated_resource
+// before schema versioning was introduced.
+func resourceValidatedResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":           {Type: schema.TypeString, Required: true},
+			"required_value": {Type: schema.TypeString, Required: true},
+			"optional_value": {Type: schema.TypeString, Optional: true, Default: ""},
+			"validation_rules": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"has_precondition":  {Type: schema.TypeBool, Optional: true, Default: false},
+						"has_postcondition": {Type: schema.TypeBool, Optional: true, Default: false},
+						"validates_input":   {Type: schema.TypeBool, Optional: true, Default: false},
+						"validates_output":  {Type: schema.TypeBool, Optional: true, Default: false},
+					},
+				},
+			},
+			"state":                {Type: schema.TypeString, Computed: true},
+			"validated":            {Type: schema.TypeBool, Computed: true},
+			"validation_timestamp": {Type: schema.TypeString, Computed: true},
+			"computed_id":          {Type: schema.TypeString, Computed: true},
+			"solved":               {Type: schema.TypeBool, Computed: true},
+			"quality_score":        {Type: schema.TypeInt, Computed: true},
+		},
+	}
+}
+
+// resourceValidatedResourceStateUpgradeV0 fills attributes that releases
+// without schema versioning could leave null.
+func resourceValidatedResourceStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		rawState = map[string]interface{}{}
+	}
+
+	setDefault(rawState, "optional_value", "")
+	setDefault(rawState, "quality_score", 0)
+
+	return rawState, nil
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/omghozlan/terraform-provider-ctfchallenge/challenges"
+)
+
+func resourceVault() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVaultCreate,
+		ReadContext:   resourceVaultRead,
+		DeleteContext: resourceVaultDelete,
+		CustomizeDiff: resourceVaultCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"challenge_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "The state forensics challenge whose flag the vault holds",
+				ValidateFunc: validation.StringInSlice(challenges.VaultChallengeIDs(), false),
+			},
+			"shard": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     1,
+				Description: "Which shard of the flag the vault holds, for challenges split across several vaults",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The secret sealed in the vault",
+			},
+			"compartment": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Compartments nested in the vault",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"position": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"fragment": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+			"provider_instance": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "instance_name of the provider instance that created this resource",
+			},
+			"player_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "player_name of the provider instance that created this resource",
+			},
+		},
+	}
+}
+
+func resourceVaultCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	contents, err := challenges.Vault(d.Get("challenge_id").(string), d.Get("shard").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(id.PrefixedUniqueId("vault-"))
+	setProviderInstance(d, m)
+	setVaultContents(d, contents)
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Vault Sealed",
+			Detail:   "The flag is somewhere in this resource's state. Recover it and submit it as recovered_flag on ctfchallenge_flag_validator.",
+		},
+	}
+}
+
+// setVaultContents writes the vault contents into state.
+func setVaultContents(d *schema.ResourceData, contents challenges.VaultContents) {
+	compartments := make([]interface{}, 0, len(contents.Compartments))
+	for _, c := range contents.Compartments {
+		compartments = append(compartments, map[string]interface{}{
+			"label":    c.Label,
+			"position": c.Position,
+			"fragment": c.Fragment,
+		})
+	}
+
+	d.Set("secret", contents.Secret)
+	d.Set("compartment", compartments)
+}
+
+// resourceVaultCustomizeDiff rejects a shard the challenge does not have
+// during plan.
+func resourceVaultCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !configKnown(d, "challenge_id", "shard") {
+		return nil
+	}
+
+	_, err := challenges.Vault(d.Get("challenge_id").(string), d.Get("shard").(int))
+	return err
+}
+
+// resourceVaultRead restores the vault contents, which are fixed for a
+// challenge and shard, so that they survive edits to the state file.
+func resourceVaultRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	contents, err := challenges.Vault(d.Get("challenge_id").(string), d.Get("shard").(int))
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("reading vault %s: %w", d.Id(), err))
+	}
+
+	setVaultContents(d, contents)
+	return nil
+}
+
+func resourceVaultDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
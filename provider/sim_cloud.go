@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// simCloud is the simulated cloud behind the ctfchallenge_sim_* resources: a
+// local JSON file holding every network, subnet, instance and bucket, and the
+// drift targets and flaky objects. Editing the file behaves like changing the cloud outside
+// Terraform.
+type simCloud struct {
+	path string
+	mu   sync.Mutex
+}
+
+// simCloudFile is the on-disk layout of the simulated cloud.
+type simCloudFile struct {
+	Networks  map[string]*simNetwork  `json:"networks"`
+	Subnets   map[string]*simSubnet   `json:"subnets"`
+	Instances map[string]*simInstance `json:"instances"`
+	Buckets   map[string]*simBucket   `json:"buckets"`
+
+	DriftTargets map[string]*simDriftTarget `json:"drift_targets"`
+	Flaky        map[string]*simFlaky       `json:"flaky"`
+	FlakyRecords map[string]*simFlakyRecord `json:"flaky_records"`
+}
+
+type simNetwork struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	CIDRBlock string            `json:"cidr_block"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt string            `json:"created_at"`
+}
+
+type simSubnet struct {
+	ID        string            `json:"id"`
+	NetworkID string            `json:"network_id"`
+	Name      string            `json:"name"`
+	CIDRBlock string            `json:"cidr_block"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt string            `json:"created_at"`
+}
+
+type simInstance struct {
+	ID           string            `json:"id"`
+	SubnetID     string            `json:"subnet_id"`
+	Name         string            `json:"name"`
+	InstanceType string            `json:"instance_type"`
+	PrivateIP    string            `json:"private_ip"`
+	State        string            `json:"state"`
+	Tags         map[string]string `json:"tags,omitempty"`
+	CreatedAt    string            `json:"created_at"`
+}
+
+type simBucket struct {
+	Name       string            `json:"name"`
+	Versioning bool              `json:"versioning"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	CreatedAt  string            `json:"created_at"`
+}
+
+// errSimNotFound is returned when an object is missing from the simulated
+// cloud, usually because it was deleted outside Terraform.
+var errSimNotFound = errors.New("not found in the simulated cloud")
+
+// simInstanceTypes are the instance types the simulated cloud offers.
+var simInstanceTypes = []string{"sim.micro", "sim.small", "sim.medium", "sim.large"}
+
+// simReservedIPs is the number of addresses at the start of every subnet
+// reserved by the simulated cloud, as in most real clouds.
+const simReservedIPs = 4
+
+// defaultCloudFile returns ~/.ctfchallenge/cloud.json, or "" when the home
+// directory cannot be determined.
+func defaultCloudFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ctfchallenge", "cloud.json")
+}
+
+func newSimCloud(path string) *simCloud {
+	if path == "" {
+		return nil
+	}
+	return &simCloud{path: path}
+}
+
+func (c *simCloud) load() (*simCloudFile, error) {
+	file := &simCloudFile{}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading cloud file %s: %w", c.path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("parsing cloud file %s: %w", c.path, err)
+		}
+	}
+
+	if file.Networks == nil {
+		file.Networks = make(map[string]*simNetwork)
+	}
+	if file.Subnets == nil {
+		file.Subnets = make(map[string]*simSubnet)
+	}
+	if file.Instances == nil {
+		file.Instances = make(map[string]*simInstance)
+	}
+	if file.Buckets == nil {
+		file.Buckets = make(map[string]*simBucket)
+	}
+	if file.DriftTargets == nil {
+		file.DriftTargets = make(map[string]*simDriftTarget)
+	}
+	if file.Flaky == nil {
+		file.Flaky = make(map[string]*simFlaky)
+	}
+	if file.FlakyRecords == nil {
+		file.FlakyRecords = make(map[string]*simFlakyRecord)
+	}
+	return file, nil
+}
+
+func (c *simCloud) save(file *simCloudFile) error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("creating cloud directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(c.path, data); err != nil {
+		return fmt.Errorf("writing cloud file %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// View calls fn with the current contents of the cloud. It takes no file
+// lock: save replaces the file in a single rename.
+func (c *simCloud) View(fn func(file *simCloudFile) error) error {
+	if c == nil {
+		return errors.New("the simulated cloud is not configured: set cloud_file in the provider configuration")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := c.load()
+	if err != nil {
+		return err
+	}
+	return fn(file)
+}
+
+// Update calls fn with the current contents of the cloud and saves the
+// result unless fn returns an error. The cloud file is locked throughout, so
+// that configurations sharing it do not overwrite each other's changes.
+func (c *simCloud) Update(fn func(file *simCloudFile) error) error {
+	if c == nil {
+		return errors.New("the simulated cloud is not configured: set cloud_file in the provider configuration")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unlock, err := lockFile(c.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := c.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(file); err != nil {
+		return err
+	}
+	return c.save(file)
+}
+
+// subnetsIn returns the IDs of the subnets in a network, sorted.
+func (f *simCloudFile) subnetsIn(networkID string) []string {
+	ids := []string{}
+	for id, subnet := range f.Subnets {
+		if subnet.NetworkID == networkID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// instancesIn returns the IDs of the instances in a subnet, sorted.
+func (f *simCloudFile) instancesIn(subnetID string) []string {
+	ids := []string{}
+	for id, instance := range f.Instances {
+		if instance.SubnetID == subnetID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// allocateIP returns the first free host address in a subnet.
+func (f *simCloudFile) allocateIP(subnet *simSubnet) (string, error) {
+	_, cidr, err := net.ParseCIDR(subnet.CIDRBlock)
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool)
+	for _, id := range f.instancesIn(subnet.ID) {
+		used[f.Instances[id].PrivateIP] = true
+	}
+
+	base := binary.BigEndian.Uint32(cidr.IP.To4())
+	for offset := uint32(simReservedIPs); offset < cidrSize(cidr)-1; offset++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, base+offset)
+		if !used[ip.String()] {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("InsufficientFreeAddresses: subnet %s (%s) has no free addresses", subnet.ID, subnet.CIDRBlock)
+}
+
+// availableIPs returns the number of free host addresses in a subnet.
+func (f *simCloudFile) availableIPs(subnet *simSubnet) int {
+	_, cidr, err := net.ParseCIDR(subnet.CIDRBlock)
+	if err != nil {
+		return 0
+	}
+	return int(cidrSize(cidr)) - simReservedIPs - 1 - len(f.instancesIn(subnet.ID))
+}
+
+// cidrSize returns the number of addresses in an IPv4 network.
+func cidrSize(cidr *net.IPNet) uint32 {
+	ones, bits := cidr.Mask.Size()
+	return 1 << uint(bits-ones)
+}
+
+// cidrContains reports whether inner lies entirely inside outer.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return innerOnes >= outerOnes && outer.Contains(inner.IP)
+}
+
+// cidrOverlaps reports whether two networks share any address.
+func cidrOverlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// newSimID returns a random object ID such as net-0123456789abcdef.
+func newSimID(prefix string) string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("generating ID: %s", err))
+	}
+	return prefix + "-" + hex.EncodeToString(b)
+}
+
+// simTags converts the tags attribute into a map of strings.
+func simTags(d *schema.ResourceData) map[string]string {
+	tags := make(map[string]string)
+	for k, v := range d.Get("tags").(map[string]interface{}) {
+		tags[k] = v.(string)
+	}
+	return tags
+}
+
+// simCloudFrom returns the simulated cloud of the configured provider.
+func simCloudFrom(m interface{}) *simCloud {
+	return providerConfig(m).Cloud
+}
+
+// importSimResource imports a simulated cloud object by its ID. Read fills
+// in the rest from the cloud file.
+func importSimResource(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	setProviderInstance(d, m)
+	return []*schema.ResourceData{d}, nil
+}
+
+// simTagsSchema is the tags argument shared by the simulated cloud resources.
+func simTagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Optional:    true,
+		Description: "Tags to assign to the object",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
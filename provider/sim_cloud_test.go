@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSimCloudConcurrentUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cloud.json")
+
+	// Each cloud stands in for the provider process of another
+	// configuration sharing the cloud file
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 8; i++ {
+		cloud := newSimCloud(path)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				name := fmt.Sprintf("bucket-%d-%d", i, j)
+				errs <- cloud.Update(func(file *simCloudFile) error {
+					file.Buckets[name] = &simBucket{Name: name}
+					return nil
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("error updating the cloud: %s", err)
+		}
+	}
+
+	err := newSimCloud(path).View(func(file *simCloudFile) error {
+		if len(file.Buckets) != 200 {
+			return fmt.Errorf("expected 200 buckets, got %d: concurrent updates lost buckets", len(file.Buckets))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, entry := range entries {
+		if name := entry.Name(); name != "cloud.json" && name != "cloud.json.lock" {
+			t.Errorf("unexpected file %s left next to the cloud file", name)
+		}
+	}
+}
+
+func TestSimCloudFailedUpdate(t *testing.T) {
+	cloud := newSimCloud(filepath.Join(t.TempDir(), "cloud.json"))
+	if err := cloud.Update(func(file *simCloudFile) error {
+		file.Buckets["kept"] = &simBucket{Name: "kept"}
+		return nil
+	}); err != nil {
+		t.Fatalf("error updating the cloud: %s", err)
+	}
+
+	if err := cloud.Update(func(file *simCloudFile) error {
+		delete(file.Buckets, "kept")
+		return errSimNotFound
+	}); err != errSimNotFound {
+		t.Fatalf("expected the update's error, got %v", err)
+	}
+
+	cloud.View(func(file *simCloudFile) error {
+		if file.Buckets["kept"] == nil {
+			t.Fatal("a failed update was saved")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resources declare a SchemaVersion and one StateUpgrader per earlier
+// version. The schema of each earlier version is kept in a
+// resource<Name>V<N>() function in the resource's _migrate.go file so the
+// upgrader type stays fixed when the current schema evolves. Upgraders
+// receive the raw state of that version and return it in the shape of the
+// next one; they run in sequence until the current version is reached.
+
+// upgradeStep builds the StateUpgrader that moves state from version to
+// version+1, using prior as the schema that state was written with.
+func upgradeStep(version int, prior *schema.Resource, upgrade schema.StateUpgradeFunc) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    prior.CoreConfigSchema().ImpliedType(),
+		Upgrade: upgrade,
+	}
+}
+
+// setDefault sets key in rawState when it is missing or null.
+func setDefault(rawState map[string]interface{}, key string, value interface{}) {
+	if v, ok := rawState[key]; !ok || v == nil {
+		rawState[key] = value
+	}
+}
+
+// stateString returns the string stored at key, or "" when it is absent.
+func stateString(rawState map[string]interface{}, key string) string {
+	s, _ := rawState[key].(string)
+	return s
+}
+
+// stateBool returns the bool stored at key, or false when it is absent.
+func stateBool(rawState map[string]interface{}, key string) bool {
+	b, _ := rawState[key].(bool)
+	return b
+}
@@ -0,0 +1,170 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceFlagValidatorStateUpgradeV0(t *testing.T) {
+	cases := map[string]struct {
+		rawState map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"validated": {
+			rawState: map[string]interface{}{
+				"id":           "terraform_basics-1700000000",
+				"challenge_id": "terraform_basics",
+				"validated":    true,
+				"message":      "✓ Challenge 'Terraform Basics' completed successfully!",
+				"points":       100,
+			},
+			expected: map[string]interface{}{
+				"id":                "terraform_basics-1700000000",
+				"challenge_id":      "terraform_basics",
+				"validated":         true,
+				"message":           "✓ Challenge 'Terraform Basics' completed successfully!",
+				"points":            100,
+				"fail_on_plan":      false,
+				"preview":           "pass: ✓ Challenge 'Terraform Basics' completed successfully!",
+				"checks":            []interface{}{},
+				"challenge_version": 1,
+			},
+		},
+		"failed": {
+			rawState: map[string]interface{}{
+				"id":           "terraform_basics-1700000000",
+				"challenge_id": "terraform_basics",
+				"validated":    false,
+				"message":      "Challenge requirements not met",
+			},
+			expected: map[string]interface{}{
+				"id":           "terraform_basics-1700000000",
+				"challenge_id": "terraform_basics",
+				"validated":    false,
+				"message":      "Challenge requirements not met",
+				"fail_on_plan": false,
+				"preview":      "fail: Challenge requirements not met",
+				"checks":       []interface{}{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := resourceFlagValidatorStateUpgradeV0(context.Background(), tc.rawState, nil)
+			if err != nil {
+				t.Fatalf("error migrating state: %s", err)
+			}
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestResourcePuzzleBoxStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":            "puzzle-1700000000",
+		"inputs":        map[string]interface{}{"input_1": "0"},
+		"solved":        true,
+		"message":       "Puzzle solved! XOR of all inputs equals zero.",
+		"secret_output": "flag{xor_puzzl3_s0lv3d}",
+	}
+	expected := map[string]interface{}{
+		"id":            "puzzle-1700000000",
+		"inputs":        map[string]interface{}{"input_1": "0"},
+		"solved":        true,
+		"message":       "Puzzle solved! XOR of all inputs equals zero.",
+		"secret_output": "flag{xor_puzzl3_s0lv3d}",
+		"fail_on_plan":  false,
+		"preview":       "pass: Puzzle solved! XOR of all inputs equals zero.",
+	}
+
+	actual, err := resourcePuzzleBoxStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourcePuzzleBoxStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":            "puzzle-1700000000",
+		"inputs":        map[string]interface{}{"input_1": "15"},
+		"fail_on_plan":  false,
+		"preview":       "fail: Provide exactly 5 numbers (input_1 through input_5)",
+		"solved":        false,
+		"message":       "Provide exactly 5 numbers (input_1 through input_5)",
+		"secret_output": "",
+	}
+	expected := map[string]interface{}{
+		"id":            "puzzle-1700000000",
+		"inputs":        map[string]interface{}{"input_1": "15"},
+		"fail_on_plan":  false,
+		"preview":       "fail: Provide exactly 5 numbers (input_1 through input_5)",
+		"solved":        false,
+		"message":       "Provide exactly 5 numbers (input_1 through input_5)",
+		"secret_output": "",
+		"puzzle_type":   "xor",
+		"prompt":        "Find 5 distinct numbers between 1 and 255 whose XOR is 0",
+		"input_schema":  "input_1 through input_5: distinct whole numbers between 1 and 255",
+		"difficulty":    "beginner",
+	}
+
+	actual, err := resourcePuzzleBoxStateUpgradeV1(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceValidatedResourceStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":             "validated-demo-1700000000",
+		"name":           "demo",
+		"required_value": "value",
+		"optional_value": nil,
+	}
+	expected := map[string]interface{}{
+		"id":             "validated-demo-1700000000",
+		"name":           "demo",
+		"required_value": "value",
+		"optional_value": "",
+		"quality_score":  0,
+	}
+
+	actual, err := resourceValidatedResourceStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceMetaChallengeStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":             "meta-count-1700000000",
+		"challenge_type": "count",
+		"success":        true,
+	}
+	expected := map[string]interface{}{
+		"id":             "meta-count-1700000000",
+		"challenge_type": "count",
+		"success":        true,
+		"hints_used":     0,
+	}
+
+	actual, err := resourceMetaChallengeStateUpgradeV0(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
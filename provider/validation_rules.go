@@ -0,0 +1,449 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// validationRule is one rule block of a ctfchallenge_validated_resource.
+type validationRule struct {
+	Index     int
+	Type      string
+	Attribute string
+	Pattern   string
+	MinLength int
+	MaxLength int
+	Values    []string
+	Schema    string
+	Message   string
+}
+
+// validationRuleTypes are the rule types the provider evaluates.
+var validationRuleTypes = []string{"regex", "length", "enum", "cidr", "semver", "json_schema"}
+
+// validationRuleAttributes are the attributes a rule can check.
+var validationRuleAttributes = []string{"required_value", "optional_value", "name"}
+
+// semverPattern is the regular expression suggested by semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func expandValidationRules(raw []interface{}) []validationRule {
+	rules := make([]validationRule, 0, len(raw))
+	for i, r := range raw {
+		if r == nil {
+			continue
+		}
+		m := r.(map[string]interface{})
+		rule := validationRule{
+			Index:     i,
+			Type:      m["type"].(string),
+			Attribute: m["attribute"].(string),
+			Pattern:   m["pattern"].(string),
+			MinLength: m["min_length"].(int),
+			MaxLength: m["max_length"].(int),
+			Schema:    m["schema"].(string),
+			Message:   m["message"].(string),
+		}
+		for _, v := range m["values"].([]interface{}) {
+			if s, ok := v.(string); ok {
+				rule.Values = append(rule.Values, s)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// String identifies the rule in diagnostics, e.g. rule[1] (cidr).
+func (r validationRule) String() string {
+	return fmt.Sprintf("rule[%d] (%s)", r.Index, r.Type)
+}
+
+// checkDefinition reports a rule that cannot be evaluated, such as an invalid
+// regular expression, and the argument at fault.
+func (r validationRule) checkDefinition() (string, error) {
+	switch r.Type {
+	case "regex":
+		if r.Pattern == "" {
+			return "pattern", fmt.Errorf("regex rules need a pattern")
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return "pattern", fmt.Errorf("invalid pattern: %s", err)
+		}
+	case "length":
+		if r.MinLength == 0 && r.MaxLength == 0 {
+			return "min_length", fmt.Errorf("length rules need min_length, max_length or both")
+		}
+		if r.MaxLength != 0 && r.MaxLength < r.MinLength {
+			return "max_length", fmt.Errorf("max_length %d is less than min_length %d", r.MaxLength, r.MinLength)
+		}
+	case "enum":
+		if len(r.Values) == 0 {
+			return "values", fmt.Errorf("enum rules need at least one value")
+		}
+	case "json_schema":
+		if r.Schema == "" {
+			return "schema", fmt.Errorf("json_schema rules need a schema")
+		}
+		var schema interface{}
+		if err := json.Unmarshal([]byte(r.Schema), &schema); err != nil {
+			return "schema", fmt.Errorf("schema is not valid JSON: %s", err)
+		}
+		if err := checkJSONSchema(schema, "#"); err != nil {
+			return "schema", err
+		}
+	}
+	return "", nil
+}
+
+// evaluate returns why value does not satisfy the rule, or "" when it does.
+// The rule's message leads the explanation when set.
+func (r validationRule) evaluate(value string) string {
+	reason := r.reason(value)
+	if reason != "" && r.Message != "" {
+		return fmt.Sprintf("%s (%s)", r.Message, reason)
+	}
+	return reason
+}
+
+func (r validationRule) reason(value string) string {
+	switch r.Type {
+	case "regex":
+		if !regexp.MustCompile(r.Pattern).MatchString(value) {
+			return fmt.Sprintf("%q does not match %s", value, r.Pattern)
+		}
+	case "length":
+		n := utf8.RuneCountInString(value)
+		if n < r.MinLength {
+			return fmt.Sprintf("length %d is less than the minimum of %d", n, r.MinLength)
+		}
+		if r.MaxLength != 0 && n > r.MaxLength {
+			return fmt.Sprintf("length %d is more than the maximum of %d", n, r.MaxLength)
+		}
+	case "enum":
+		for _, allowed := range r.Values {
+			if value == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", value, strings.Join(r.Values, ", "))
+	case "cidr":
+		ip, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Sprintf("%q is not a CIDR block such as 10.0.0.0/16", value)
+		}
+		if !ip.Equal(network.IP) {
+			return fmt.Sprintf("%q has host bits set; the network address is %s", value, network)
+		}
+	case "semver":
+		if !semverPattern.MatchString(value) {
+			hint := ""
+			if strings.HasPrefix(value, "v") {
+				hint = " (drop the leading v)"
+			}
+			return fmt.Sprintf("%q is not a semantic version such as 1.2.3%s", value, hint)
+		}
+	case "json_schema":
+		var document, schema interface{}
+		if err := json.Unmarshal([]byte(value), &document); err != nil {
+			return fmt.Sprintf("value is not valid JSON: %s", err)
+		}
+		json.Unmarshal([]byte(r.Schema), &schema)
+		if err := matchJSONSchema(schema.(map[string]interface{}), document, "$"); err != "" {
+			return err
+		}
+	}
+	return ""
+}
+
+// jsonSchemaKeywords are the JSON Schema keywords json_schema rules support.
+// Annotations are accepted and ignored.
+var jsonSchemaKeywords = map[string]bool{
+	"type": true, "properties": true, "required": true, "additionalProperties": true,
+	"items": true, "enum": true, "minimum": true, "maximum": true,
+	"minLength": true, "maxLength": true, "pattern": true, "minItems": true, "maxItems": true,
+	"$schema": true, "$id": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+// checkJSONSchema checks that a schema only uses supported keywords, so no
+// constraint is silently ignored.
+func checkJSONSchema(schema interface{}, path string) error {
+	m, ok := schema.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: a schema must be a JSON object", path)
+	}
+
+	keywords := make([]string, 0, len(m))
+	for keyword := range m {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		value := m[keyword]
+		if !jsonSchemaKeywords[keyword] {
+			return fmt.Errorf("%s: unsupported keyword %q", path, keyword)
+		}
+		switch keyword {
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s/properties: must be an object", path)
+			}
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if err := checkJSONSchema(properties[name], path+"/properties/"+name); err != nil {
+					return err
+				}
+			}
+		case "items":
+			if err := checkJSONSchema(value, path+"/items"); err != nil {
+				return err
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%s/pattern: must be a string", path)
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("%s/pattern: %s", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchJSONSchema returns the first way document violates schema, or "".
+func matchJSONSchema(schema map[string]interface{}, document interface{}, path string) string {
+	if types, ok := schema["type"]; ok && !matchesJSONType(types, document) {
+		return fmt.Sprintf("%s: expected %s, got %s", path, describeJSONTypes(types), jsonTypeOf(document))
+	}
+
+	if allowed, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range allowed {
+			if jsonEqual(v, document) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s: value is not one of the allowed values", path)
+		}
+	}
+
+	switch v := document.(type) {
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Sprintf("%s: %v is less than the minimum of %v", path, v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Sprintf("%s: %v is more than the maximum of %v", path, v, max)
+		}
+	case string:
+		n := float64(utf8.RuneCountInString(v))
+		if min, ok := schema["minLength"].(float64); ok && n < min {
+			return fmt.Sprintf("%s: length %v is less than the minimum of %v", path, n, min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && n > max {
+			return fmt.Sprintf("%s: length %v is more than the maximum of %v", path, n, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Sprintf("%s: %q does not match %s", path, v, pattern)
+		}
+	case []interface{}:
+		n := float64(len(v))
+		if min, ok := schema["minItems"].(float64); ok && n < min {
+			return fmt.Sprintf("%s: %v items is fewer than the minimum of %v", path, n, min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && n > max {
+			return fmt.Sprintf("%s: %v items is more than the maximum of %v", path, n, max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := matchJSONSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != "" {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[fmt.Sprint(name)]; !ok {
+					return fmt.Sprintf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Sprintf("%s: property %q is not allowed", path, name)
+				}
+				continue
+			}
+			if err := matchJSONSchema(property, v[name], path+"."+name); err != "" {
+				return err
+			}
+		}
+	}
+	return ""
+}
+
+func matchesJSONType(types, document interface{}) bool {
+	if list, ok := types.([]interface{}); ok {
+		for _, t := range list {
+			if matchesJSONType(t, document) {
+				return true
+			}
+		}
+		return false
+	}
+
+	actual := jsonTypeOf(document)
+	switch types {
+	case actual:
+		return true
+	case "number":
+		return actual == "integer"
+	}
+	return false
+}
+
+func describeJSONTypes(types interface{}) string {
+	if list, ok := types.([]interface{}); ok {
+		names := make([]string, len(list))
+		for i, t := range list {
+			names[i] = fmt.Sprint(t)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+func jsonTypeOf(document interface{}) string {
+	switch v := document.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
+
+// Rule results recorded for each rule of a validated resource.
+const (
+	rulePassed  = "passed"
+	ruleFailed  = "failed"
+	ruleSkipped = "skipped"
+)
+
+// validationOutcome is the result of evaluating a validated resource.
+type validationOutcome struct {
+	validated    bool
+	qualityScore int
+	failures     []ruleFailure
+	// rules are the evaluated rules and results their results, in order
+	rules   []validationRule
+	results []string
+}
+
+// ruleFailure is a rule a value does not satisfy.
+type ruleFailure struct {
+	rule   validationRule
+	reason string
+}
+
+// evaluateValidatedResource evaluates the rules of a validated resource
+// against its values and scores it out of 100:
+//
+//   - 30 when required_value is not blank
+//   - 20 when required_value is at least 8 characters
+//   - 10 for each satisfied rule, up to 30
+//   - 10 when the satisfied rules are of at least two types
+//   - 10 when every rule has a message
+//
+// Rules on an empty optional_value are skipped and earn nothing.
+func evaluateValidatedResource(d resourceGetter) validationOutcome {
+	values := map[string]string{
+		"required_value": d.Get("required_value").(string),
+		"optional_value": d.Get("optional_value").(string),
+		"name":           d.Get("name").(string),
+	}
+	rules := expandValidationRules(d.Get("rule").([]interface{}))
+
+	outcome := validationOutcome{rules: rules, results: make([]string, len(rules))}
+	satisfied := 0
+	types := make(map[string]bool)
+	allMessages := len(rules) > 0
+	for i, rule := range rules {
+		if rule.Message == "" {
+			allMessages = false
+		}
+
+		value := values[rule.Attribute]
+		if rule.Attribute == "optional_value" && value == "" {
+			outcome.results[i] = ruleSkipped
+			continue
+		}
+		if reason := rule.evaluate(value); reason != "" {
+			outcome.failures = append(outcome.failures, ruleFailure{rule: rule, reason: reason})
+			outcome.results[i] = ruleFailed
+			continue
+		}
+		outcome.results[i] = rulePassed
+		satisfied++
+		types[rule.Type] = true
+	}
+
+	required := values["required_value"]
+	outcome.validated = strings.TrimSpace(required) != "" && len(outcome.failures) == 0
+
+	if strings.TrimSpace(required) != "" {
+		outcome.qualityScore += 30
+	}
+	if utf8.RuneCountInString(required) >= 8 {
+		outcome.qualityScore += 20
+	}
+	outcome.qualityScore += 10 * min(satisfied, 3)
+	if len(types) >= 2 {
+		outcome.qualityScore += 10
+	}
+	if allMessages {
+		outcome.qualityScore += 10
+	}
+	return outcome
+}
@@ -0,0 +1,554 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestValidationRuleEvaluate(t *testing.T) {
+	cases := map[string]struct {
+		rule     validationRule
+		value    string
+		expected string
+	}{
+		"regex match": {
+			rule:  validationRule{Type: "regex", Pattern: `^[a-z]+-\d+$`},
+			value: "web-01",
+		},
+		"regex mismatch": {
+			rule:     validationRule{Type: "regex", Pattern: `^[a-z]+-\d+$`},
+			value:    "Web-01",
+			expected: `"Web-01" does not match ^[a-z]+-\d+$`,
+		},
+		"length within bounds": {
+			rule:  validationRule{Type: "length", MinLength: 3, MaxLength: 5},
+			value: "abcde",
+		},
+		"length counts characters": {
+			rule:  validationRule{Type: "length", MaxLength: 3},
+			value: "äöü",
+		},
+		"length no maximum": {
+			rule:  validationRule{Type: "length", MinLength: 3},
+			value: "a long value well past any maximum",
+		},
+		"length too short": {
+			rule:     validationRule{Type: "length", MinLength: 3},
+			value:    "ab",
+			expected: "length 2 is less than the minimum of 3",
+		},
+		"length too long": {
+			rule:     validationRule{Type: "length", MinLength: 1, MaxLength: 5},
+			value:    "abcdef",
+			expected: "length 6 is more than the maximum of 5",
+		},
+		"enum allowed": {
+			rule:  validationRule{Type: "enum", Values: []string{"dev", "prod"}},
+			value: "prod",
+		},
+		"enum not allowed": {
+			rule:     validationRule{Type: "enum", Values: []string{"dev", "prod"}},
+			value:    "Prod",
+			expected: `"Prod" is not one of dev, prod`,
+		},
+		"cidr network": {
+			rule:  validationRule{Type: "cidr"},
+			value: "10.20.0.0/16",
+		},
+		"cidr host bits set": {
+			rule:     validationRule{Type: "cidr"},
+			value:    "10.20.1.0/16",
+			expected: `"10.20.1.0/16" has host bits set; the network address is 10.20.0.0/16`,
+		},
+		"cidr single host": {
+			rule:  validationRule{Type: "cidr"},
+			value: "10.20.1.7/32",
+		},
+		"cidr ipv6 network": {
+			rule:  validationRule{Type: "cidr"},
+			value: "2001:db8::/32",
+		},
+		"cidr ipv6 host bits set": {
+			rule:     validationRule{Type: "cidr"},
+			value:    "2001:db8::1/32",
+			expected: `"2001:db8::1/32" has host bits set; the network address is 2001:db8::/32`,
+		},
+		"cidr missing prefix": {
+			rule:     validationRule{Type: "cidr"},
+			value:    "10.20.0.0",
+			expected: `"10.20.0.0" is not a CIDR block such as 10.0.0.0/16`,
+		},
+		"semver release": {
+			rule:  validationRule{Type: "semver"},
+			value: "1.2.3",
+		},
+		"semver prerelease and build": {
+			rule:  validationRule{Type: "semver"},
+			value: "1.0.0-rc.1+build.5",
+		},
+		"semver leading v": {
+			rule:     validationRule{Type: "semver"},
+			value:    "v1.2.3",
+			expected: `"v1.2.3" is not a semantic version such as 1.2.3 (drop the leading v)`,
+		},
+		"semver leading zero": {
+			rule:     validationRule{Type: "semver"},
+			value:    "1.02.3",
+			expected: `"1.02.3" is not a semantic version such as 1.2.3`,
+		},
+		"semver leading zero in prerelease": {
+			rule:     validationRule{Type: "semver"},
+			value:    "1.2.3-rc.01",
+			expected: `"1.2.3-rc.01" is not a semantic version such as 1.2.3`,
+		},
+		"semver missing patch": {
+			rule:     validationRule{Type: "semver"},
+			value:    "1.2",
+			expected: `"1.2" is not a semantic version such as 1.2.3`,
+		},
+		"json_schema match": {
+			rule:  validationRule{Type: "json_schema", Schema: `{"type": "object", "required": ["port"], "properties": {"port": {"type": "integer"}}}`},
+			value: `{"port": 8080}`,
+		},
+		"json_schema mismatch": {
+			rule:     validationRule{Type: "json_schema", Schema: `{"type": "object", "required": ["port"]}`},
+			value:    `{"host": "a"}`,
+			expected: `$: missing required property "port"`,
+		},
+		"json_schema invalid json": {
+			rule:     validationRule{Type: "json_schema", Schema: `{"type": "object"}`},
+			value:    `{port: 8080}`,
+			expected: "value is not valid JSON: invalid character 'p' looking for beginning of object key string",
+		},
+		"message leads the reason": {
+			rule:     validationRule{Type: "enum", Values: []string{"dev", "prod"}, Message: "environment must be dev or prod"},
+			value:    "test",
+			expected: `environment must be dev or prod ("test" is not one of dev, prod)`,
+		},
+		"message unused on success": {
+			rule:  validationRule{Type: "enum", Values: []string{"dev", "prod"}, Message: "environment must be dev or prod"},
+			value: "dev",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tc.rule.checkDefinition(); err != nil {
+				t.Fatalf("invalid rule definition: %s", err)
+			}
+			if actual := tc.rule.evaluate(tc.value); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestValidationRuleCheckDefinition(t *testing.T) {
+	cases := map[string]struct {
+		rule     validationRule
+		argument string
+		expected string
+	}{
+		"regex without pattern": {
+			rule:     validationRule{Type: "regex"},
+			argument: "pattern",
+			expected: "regex rules need a pattern",
+		},
+		"regex invalid pattern": {
+			rule:     validationRule{Type: "regex", Pattern: "[a-z"},
+			argument: "pattern",
+			expected: "invalid pattern: error parsing regexp: missing closing ]: `[a-z`",
+		},
+		"length without bounds": {
+			rule:     validationRule{Type: "length"},
+			argument: "min_length",
+			expected: "length rules need min_length, max_length or both",
+		},
+		"length inverted bounds": {
+			rule:     validationRule{Type: "length", MinLength: 5, MaxLength: 3},
+			argument: "max_length",
+			expected: "max_length 3 is less than min_length 5",
+		},
+		"enum without values": {
+			rule:     validationRule{Type: "enum"},
+			argument: "values",
+			expected: "enum rules need at least one value",
+		},
+		"json_schema without schema": {
+			rule:     validationRule{Type: "json_schema"},
+			argument: "schema",
+			expected: "json_schema rules need a schema",
+		},
+		"json_schema invalid json": {
+			rule:     validationRule{Type: "json_schema", Schema: `{"type": }`},
+			argument: "schema",
+			expected: "schema is not valid JSON: invalid character '}' looking for beginning of value",
+		},
+		"json_schema unsupported keyword": {
+			rule:     validationRule{Type: "json_schema", Schema: `{"type": "object", "oneOf": []}`},
+			argument: "schema",
+			expected: `#: unsupported keyword "oneOf"`,
+		},
+		"cidr": {
+			rule: validationRule{Type: "cidr"},
+		},
+		"semver": {
+			rule: validationRule{Type: "semver"},
+		},
+		"valid length": {
+			rule: validationRule{Type: "length", MaxLength: 3},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			argument, err := tc.rule.checkDefinition()
+			actual := ""
+			if err != nil {
+				actual = err.Error()
+			}
+			if argument != tc.argument || actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%s: %q\n\ngot:\n\n%s: %q\n\n", tc.argument, tc.expected, argument, actual)
+			}
+		})
+	}
+}
+
+func TestCheckJSONSchema(t *testing.T) {
+	cases := map[string]struct {
+		schema   string
+		expected string
+	}{
+		"supported keywords": {
+			schema: `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Server", "type": "object", "required": ["name"],
+				"properties": {"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"}, "ports": {"type": "array", "minItems": 1, "items": {"type": "integer", "minimum": 1, "maximum": 65535}}},
+				"additionalProperties": false}`,
+		},
+		"not an object": {
+			schema:   `["string"]`,
+			expected: "#: a schema must be a JSON object",
+		},
+		"unsupported nested property keyword": {
+			schema:   `{"type": "object", "properties": {"name": {"type": "string", "format": "hostname"}}}`,
+			expected: `#/properties/name: unsupported keyword "format"`,
+		},
+		"unsupported keyword in items": {
+			schema:   `{"type": "array", "items": {"type": "object", "properties": {"tags": {"type": "array", "items": {"uniqueItems": true}}}}}`,
+			expected: `#/items/properties/tags/items: unsupported keyword "uniqueItems"`,
+		},
+		"properties not an object": {
+			schema:   `{"properties": ["name"]}`,
+			expected: "#/properties: must be an object",
+		},
+		"items not a schema": {
+			schema:   `{"items": "string"}`,
+			expected: "#/items: a schema must be a JSON object",
+		},
+		"pattern not a string": {
+			schema:   `{"properties": {"name": {"pattern": 3}}}`,
+			expected: "#/properties/name/pattern: must be a string",
+		},
+		"invalid pattern": {
+			schema:   `{"pattern": "(a"}`,
+			expected: "#/pattern: error parsing regexp: missing closing ): `(a`",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var schema interface{}
+			if err := json.Unmarshal([]byte(tc.schema), &schema); err != nil {
+				t.Fatalf("invalid test schema: %s", err)
+			}
+			actual := ""
+			if err := checkJSONSchema(schema, "#"); err != nil {
+				actual = err.Error()
+			}
+			if actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatchJSONSchema(t *testing.T) {
+	const serverSchema = `{
+		"type": "object",
+		"required": ["name", "ports"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 8, "pattern": "^[a-z]+$"},
+			"ports": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"type": "integer", "minimum": 1, "maximum": 65535}},
+			"owner": {
+				"type": "object",
+				"required": ["team"],
+				"properties": {"team": {"enum": ["blue", "red"]}},
+				"additionalProperties": false
+			},
+			"weight": {"type": ["number", "null"]}
+		},
+		"additionalProperties": false
+	}`
+
+	cases := map[string]struct {
+		document string
+		expected string
+	}{
+		"valid": {
+			document: `{"name": "web", "ports": [80, 443], "owner": {"team": "blue"}, "weight": 0.5}`,
+		},
+		"null in type list": {
+			document: `{"name": "web", "ports": [80], "weight": null}`,
+		},
+		"integer is a number": {
+			document: `{"name": "web", "ports": [80], "weight": 2}`,
+		},
+		"wrong root type": {
+			document: `["web"]`,
+			expected: "$: expected object, got array",
+		},
+		"missing required property": {
+			document: `{"name": "web"}`,
+			expected: `$: missing required property "ports"`,
+		},
+		"additional property": {
+			document: `{"name": "web", "ports": [80], "zone": "a"}`,
+			expected: `$: property "zone" is not allowed`,
+		},
+		"nested additional property": {
+			document: `{"name": "web", "ports": [80], "owner": {"team": "blue", "lead": "ada"}}`,
+			expected: `$.owner: property "lead" is not allowed`,
+		},
+		"nested missing required property": {
+			document: `{"name": "web", "ports": [80], "owner": {}}`,
+			expected: `$.owner: missing required property "team"`,
+		},
+		"nested enum": {
+			document: `{"name": "web", "ports": [80], "owner": {"team": "green"}}`,
+			expected: "$.owner.team: value is not one of the allowed values",
+		},
+		"string too short": {
+			document: `{"name": "w", "ports": [80]}`,
+			expected: "$.name: length 1 is less than the minimum of 2",
+		},
+		"string too long": {
+			document: `{"name": "webserver", "ports": [80]}`,
+			expected: "$.name: length 9 is more than the maximum of 8",
+		},
+		"string pattern": {
+			document: `{"name": "web1", "ports": [80]}`,
+			expected: `$.name: "web1" does not match ^[a-z]+$`,
+		},
+		"too few items": {
+			document: `{"name": "web", "ports": []}`,
+			expected: "$.ports: 0 items is fewer than the minimum of 1",
+		},
+		"too many items": {
+			document: `{"name": "web", "ports": [80, 443, 8080, 8443]}`,
+			expected: "$.ports: 4 items is more than the maximum of 3",
+		},
+		"item type": {
+			document: `{"name": "web", "ports": [80, "443"]}`,
+			expected: "$.ports[1]: expected integer, got string",
+		},
+		"item fraction": {
+			document: `{"name": "web", "ports": [80.5]}`,
+			expected: "$.ports[0]: expected integer, got number",
+		},
+		"item below minimum": {
+			document: `{"name": "web", "ports": [0]}`,
+			expected: "$.ports[0]: 0 is less than the minimum of 1",
+		},
+		"item above maximum": {
+			document: `{"name": "web", "ports": [80, 70000]}`,
+			expected: "$.ports[1]: 70000 is more than the maximum of 65535",
+		},
+		"type list": {
+			document: `{"name": "web", "ports": [80], "weight": "heavy"}`,
+			expected: "$.weight: expected number or null, got string",
+		},
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(serverSchema), &schema); err != nil {
+		t.Fatalf("invalid test schema: %s", err)
+	}
+	if err := checkJSONSchema(schema, "#"); err != nil {
+		t.Fatalf("unsupported test schema: %s", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var document interface{}
+			if err := json.Unmarshal([]byte(tc.document), &document); err != nil {
+				t.Fatalf("invalid test document: %s", err)
+			}
+			if actual := matchJSONSchema(schema, document, "$"); actual != tc.expected {
+				t.Fatalf("\n\nexpected:\n\n%q\n\ngot:\n\n%q\n\n", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatchJSONSchemaAdditionalPropertiesAllowed(t *testing.T) {
+	for _, schema := range []string{
+		`{"properties": {"name": {"type": "string"}}}`,
+		`{"properties": {"name": {"type": "string"}}, "additionalProperties": true}`,
+	} {
+		var s map[string]interface{}
+		if err := json.Unmarshal([]byte(schema), &s); err != nil {
+			t.Fatalf("invalid test schema: %s", err)
+		}
+		if actual := matchJSONSchema(s, map[string]interface{}{"name": "web", "zone": "a"}, "$"); actual != "" {
+			t.Fatalf("%s: expected additional properties to be allowed, got %q", schema, actual)
+		}
+	}
+}
+
+func TestMatchesJSONType(t *testing.T) {
+	cases := map[string]struct {
+		types    interface{}
+		document interface{}
+		expected bool
+	}{
+		"string":                          {types: "string", document: "a", expected: true},
+		"integer":                         {types: "integer", document: float64(3), expected: true},
+		"integer is a number":             {types: "number", document: float64(3), expected: true},
+		"fraction is a number":            {types: "number", document: 3.5, expected: true},
+		"fraction is not integer":         {types: "integer", document: 3.5, expected: false},
+		"boolean":                         {types: "boolean", document: true, expected: true},
+		"null":                            {types: "null", document: nil, expected: true},
+		"array":                           {types: "array", document: []interface{}{}, expected: true},
+		"object":                          {types: "object", document: map[string]interface{}{}, expected: true},
+		"object is not array":             {types: "array", document: map[string]interface{}{}, expected: false},
+		"string is not number":            {types: "number", document: "3", expected: false},
+		"type list match":                 {types: []interface{}{"string", "null"}, document: nil, expected: true},
+		"type list integer":               {types: []interface{}{"string", "number"}, document: float64(3), expected: true},
+		"type list mismatch":              {types: []interface{}{"string", "null"}, document: true, expected: false},
+		"empty type list matches nothing": {types: []interface{}{}, document: "a", expected: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := matchesJSONType(tc.types, tc.document); actual != tc.expected {
+				t.Fatalf("matchesJSONType(%#v, %#v) = %t, expected %t", tc.types, tc.document, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEvaluateValidatedResource(t *testing.T) {
+	lengthRule := map[string]interface{}{"type": "length", "min_length": 3, "message": "required_value must be at least 3 characters"}
+	cidrRule := map[string]interface{}{"type": "cidr", "message": "required_value must be a network address"}
+	regexRule := map[string]interface{}{"type": "regex", "pattern": `^10\.`, "message": "required_value must be in 10.0.0.0/8"}
+	semverRule := map[string]interface{}{"type": "semver", "attribute": "optional_value", "message": "optional_value must be a version"}
+
+	cases := map[string]struct {
+		raw       map[string]interface{}
+		validated bool
+		score     int
+		results   []string
+	}{
+		"blank required_value": {
+			raw:     map[string]interface{}{"name": "a", "required_value": " "},
+			score:   0,
+			results: []string{},
+		},
+		"short required_value without rules": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "abc"},
+			validated: true,
+			score:     30,
+			results:   []string{},
+		},
+		"long required_value without rules": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "abcdefgh"},
+			validated: true,
+			score:     50,
+			results:   []string{},
+		},
+		"one rule": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "abcdefgh", "rule": []interface{}{lengthRule}},
+			validated: true,
+			score:     70,
+			results:   []string{rulePassed},
+		},
+		"rules of two types": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, cidrRule}},
+			validated: true,
+			score:     90,
+			results:   []string{rulePassed, rulePassed},
+		},
+		"full marks": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{lengthRule, cidrRule, regexRule}},
+			validated: true,
+			score:     100,
+			results:   []string{rulePassed, rulePassed, rulePassed},
+		},
+		"satisfied rules capped at three": {
+			raw: map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{
+				lengthRule, cidrRule, regexRule, map[string]interface{}{"type": "length", "max_length": 20, "message": "required_value must be at most 20 characters"},
+			}},
+			validated: true,
+			score:     100,
+			results:   []string{rulePassed, rulePassed, rulePassed, rulePassed},
+		},
+		"missing message": {
+			raw: map[string]interface{}{"name": "a", "required_value": "10.0.0.0/8", "rule": []interface{}{
+				lengthRule, map[string]interface{}{"type": "cidr"}, regexRule,
+			}},
+			validated: true,
+			score:     90,
+			results:   []string{rulePassed, rulePassed, rulePassed},
+		},
+		"skipped rule earns nothing": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "abcdefgh", "rule": []interface{}{lengthRule, semverRule}},
+			validated: true,
+			score:     70,
+			results:   []string{rulePassed, ruleSkipped},
+		},
+		"optional_value rule applies when set": {
+			raw:       map[string]interface{}{"name": "a", "required_value": "abcdefgh", "optional_value": "1.2.3", "rule": []interface{}{lengthRule, semverRule}},
+			validated: true,
+			score:     90,
+			results:   []string{rulePassed, rulePassed},
+		},
+		"failed rule": {
+			raw:     map[string]interface{}{"name": "a", "required_value": "10.0.0.1/8", "rule": []interface{}{lengthRule, cidrRule}},
+			score:   70,
+			results: []string{rulePassed, ruleFailed},
+		},
+		"rule on name": {
+			raw: map[string]interface{}{"name": "Web", "required_value": "abcdefgh", "rule": []interface{}{
+				map[string]interface{}{"type": "regex", "attribute": "name", "pattern": "^[a-z]+$", "message": "name must be lowercase"},
+			}},
+			score:   60,
+			results: []string{ruleFailed},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceValidatedResource().Schema, tc.raw)
+			outcome := evaluateValidatedResource(d)
+			if outcome.validated != tc.validated || outcome.qualityScore != tc.score || !reflect.DeepEqual(outcome.results, tc.results) {
+				t.Fatalf("\n\nexpected:\n\nvalidated=%t score=%d results=%v\n\ngot:\n\nvalidated=%t score=%d results=%v\n\n",
+					tc.validated, tc.score, tc.results, outcome.validated, outcome.qualityScore, outcome.results)
+			}
+			if len(outcome.failures) != countResults(outcome.results, ruleFailed) {
+				t.Fatalf("expected a failure for every failed rule, got %d failure(s) for results %v", len(outcome.failures), outcome.results)
+			}
+		})
+	}
+}
+
+func countResults(results []string, result string) int {
+	n := 0
+	for _, r := range results {
+		if r == result {
+			n++
+		}
+	}
+	return n
+}